@@ -1,7 +1,7 @@
 package converter
 
 import (
-	"github.com/kdy1/go-typescript-eslint/internal/ast"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 )
 
 // Converter transforms TypeScript AST nodes into ESTree-compatible format.
@@ -33,6 +33,9 @@ type Options struct {
 
 	// SuppressDeprecatedPropertyWarnings disables warnings for deprecated properties.
 	SuppressDeprecatedPropertyWarnings bool
+
+	// SetParents links every converted node to its parent.
+	SetParents bool
 }
 
 // NodeMaps contains the bidirectional mappings between TypeScript and ESTree nodes.
@@ -92,6 +95,10 @@ func (c *Converter) ConvertProgram(program *ast.Program) *ast.Program {
 	// Register the program node mapping
 	c.registerNodeMapping(program, result)
 
+	if c.options.SetParents {
+		ast.SetParents(result)
+	}
+
 	return result
 }
 
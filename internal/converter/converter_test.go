@@ -3,7 +3,7 @@ package converter
 import (
 	"testing"
 
-	"github.com/kdy1/go-typescript-eslint/internal/ast"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 )
 
 // TestNewConverter tests creating a new converter.
@@ -1,7 +1,7 @@
 package converter
 
 import (
-	"github.com/kdy1/go-typescript-eslint/internal/ast"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 )
 
 // convertIdentifier converts an Identifier node.
@@ -172,13 +172,16 @@ func (c *Converter) convertClassExpression(node *ast.ClassExpression) *ast.Class
 		}
 	}
 
+	superTypeArguments := c.convertTSTypeParameterInstantiation(node.SuperTypeArguments)
+
 	result := &ast.ClassExpression{
 		BaseNode:            c.copyBaseNode(&node.BaseNode),
 		ID:                  c.convertIdentifier(node.ID),
 		SuperClass:          c.convertExpression(node.SuperClass),
 		Body:                c.convertClassBody(node.Body),
 		TypeParameters:      c.convertTSTypeParameterDeclaration(node.TypeParameters),
-		SuperTypeParameters: c.convertTSTypeParameterInstantiation(node.SuperTypeParameters),
+		SuperTypeArguments:  superTypeArguments,
+		SuperTypeParameters: superTypeArguments,
 		Implements:          implements,
 		Decorators:          c.convertDecorators(node.Decorators),
 		Abstract:            node.Abstract,
@@ -1,7 +1,7 @@
 package converter
 
 import (
-	"github.com/kdy1/go-typescript-eslint/internal/ast"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 )
 
 // convertArrayPattern converts an ArrayPattern node.
@@ -45,8 +45,8 @@ func (s *Scanner) Scan() Token {
 		return s.current
 	}
 
-	// Identifiers and keywords
-	if isIdentifierStart(ch) {
+	// Identifiers and keywords, possibly starting with a Unicode escape
+	if r, _ := s.currentRune(); isIdentifierStart(r) || (ch == '\\' && s.peek(1) == 'u') {
 		token := s.scanIdentifier()
 		s.current = token
 		return token
@@ -416,8 +416,8 @@ func (s *Scanner) Scan() Token {
 
 	default:
 		// Unknown character
-		s.next()
-		s.current = s.createToken(ILLEGAL, string(ch))
+		r := s.nextRune()
+		s.current = s.createToken(ILLEGAL, string(r))
 		return s.current
 	}
 }
@@ -435,3 +435,56 @@ func (s *Scanner) Reset() {
 	s.line = 1
 	s.column = 0
 }
+
+// Seek moves the scanner to byte offset pos, which must lie between tokens,
+// and sets the line and column it is at. It lets the parser start scanning
+// in the middle of a file whose preceding text has already been parsed.
+func (s *Scanner) Seek(pos, line, column int) {
+	s.pos = pos
+	s.offset = pos
+	s.fullOffset = pos
+	s.line = line
+	s.column = column
+}
+
+// ScannerState is a snapshot of the scanner position that can be restored later.
+// It is used by the parser to rewind after a failed speculative parse.
+type ScannerState struct {
+	current     Token
+	pos         int
+	offset      int
+	fullOffset  int
+	line        int
+	column      int
+	tokenLine   int
+	tokenColumn int
+	jsxMode     bool
+}
+
+// Save returns a snapshot of the current scanner state.
+func (s *Scanner) Save() ScannerState {
+	return ScannerState{
+		current:     s.current,
+		pos:         s.pos,
+		offset:      s.offset,
+		fullOffset:  s.fullOffset,
+		line:        s.line,
+		column:      s.column,
+		tokenLine:   s.tokenLine,
+		tokenColumn: s.tokenColumn,
+		jsxMode:     s.jsxMode,
+	}
+}
+
+// Restore rewinds the scanner to a snapshot previously returned by Save.
+func (s *Scanner) Restore(state ScannerState) {
+	s.current = state.current
+	s.pos = state.pos
+	s.offset = state.offset
+	s.fullOffset = state.fullOffset
+	s.line = state.line
+	s.column = state.column
+	s.tokenLine = state.tokenLine
+	s.tokenColumn = state.tokenColumn
+	s.jsxMode = state.jsxMode
+}
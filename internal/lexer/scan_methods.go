@@ -3,56 +3,103 @@ package lexer
 import (
 	"strconv"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 )
 
-// scanIdentifier scans an identifier or keyword.
-//
-//nolint:cyclop // Identifier scanning requires checking many character classes
+// scanIdentifier scans an identifier or keyword. Identifiers may contain
+// \uXXXX and \u{X} escapes, in which case the token literal is the cooked
+// name. Escaped reserved words are rejected, and escaped contextual keywords
+// are plain identifiers.
 func (s *Scanner) scanIdentifier() Token {
 	start := s.pos
-	ch := s.char()
 
-	// Check if this is a valid identifier start
-	if !isIdentifierStart(ch) {
-		return s.createToken(ILLEGAL, string(ch))
+	// Fast path for the ASCII prefix. Scan only calls this at a valid
+	// identifier start, so the first character needs no extra check.
+	for s.pos < s.length && s.source[s.pos] < utf8.RuneSelf && isIdentifierPart(rune(s.source[s.pos])) {
+		s.next()
 	}
 
-	// Fast path for ASCII-only identifiers
-	if (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch == '_' || ch == '$' {
-		hasUnicode := false
-		for {
-			s.next()
-			ch = s.char()
-			if ch >= utf8.RuneSelf {
-				// Contains Unicode, switch to slow path
-				hasUnicode = true
-				break
+	var cooked strings.Builder
+	escaped := false
+	for {
+		ch, _ := s.currentRune()
+		valid := isIdentifierPart
+		if s.pos == start {
+			valid = isIdentifierStart
+		}
+
+		if ch == '\\' {
+			escapeStart := s.pos
+			r, ok := s.scanIdentifierEscape()
+			if !ok || !valid(r) {
+				return s.createToken(ILLEGAL, s.source[start:s.pos])
 			}
-			if !((ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') ||
-				(ch >= '0' && ch <= '9') || ch == '_' || ch == '$') {
-				break
+			if !escaped {
+				cooked.WriteString(s.source[start:escapeStart])
+				escaped = true
 			}
+			cooked.WriteRune(r)
+			continue
 		}
 
-		// If we found Unicode characters, continue with Unicode scanning
-		if hasUnicode {
-			for isIdentifierPart(s.char()) {
-				s.nextRune()
-			}
+		if !valid(ch) {
+			break
 		}
-	} else {
-		// Unicode identifier - use full rune scanning
 		s.nextRune()
-		for isIdentifierPart(s.char()) {
-			s.nextRune()
+		if escaped {
+			cooked.WriteRune(ch)
 		}
 	}
 
-	literal := s.source[start:s.pos]
-	tokenType := lookupKeyword(literal)
+	if !escaped {
+		literal := s.source[start:s.pos]
+		return s.createToken(lookupKeyword(literal), literal)
+	}
 
-	return s.createToken(tokenType, literal)
+	name := cooked.String()
+	if typ := lookupKeyword(name); typ >= BREAK && typ <= YIELD {
+		// Reserved words cannot be written with escapes
+		return s.createToken(ILLEGAL, s.source[start:s.pos])
+	}
+	return s.createToken(IDENT, name)
+}
+
+// scanIdentifierEscape scans a \uXXXX or \u{X} escape inside an identifier
+// and returns the code point it denotes. It reports false for malformed escapes.
+func (s *Scanner) scanIdentifierEscape() (rune, bool) {
+	s.next() // consume '\\'
+	if s.char() != 'u' {
+		return 0, false
+	}
+	s.next() // consume 'u'
+
+	var hex string
+	if s.char() == '{' {
+		s.next() // consume '{'
+		hex = s.scanHexDigits()
+		if hex == "" || s.char() != '}' {
+			return 0, false
+		}
+		s.next() // consume '}'
+	} else {
+		start := s.pos
+		//nolint:mnd // 4 hex digits for \uNNNN escape sequence
+		for i := 0; i < 4 && isHexDigit(s.char()); i++ {
+			s.next()
+		}
+		hex = s.source[start:s.pos]
+		//nolint:mnd // 4 hex digits for \uNNNN escape sequence
+		if len(hex) != 4 {
+			return 0, false
+		}
+	}
+
+	val, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || val > unicode.MaxRune {
+		return 0, false
+	}
+	return rune(val), true
 }
 
 // scanNumber scans a numeric literal (decimal, hex, binary, octal, float, bigint).
@@ -189,8 +236,7 @@ func (s *Scanner) scanString(quote rune) Token {
 			// Unescaped newline in string
 			return s.createToken(ILLEGAL, s.source[start:s.pos])
 		} else {
-			sb.WriteRune(ch)
-			s.next()
+			sb.WriteRune(s.nextRune())
 		}
 	}
 
@@ -316,7 +362,24 @@ func (s *Scanner) scanHexDigits() string {
 func (s *Scanner) scanTemplate() Token {
 	start := s.pos
 	s.next() // consume opening backtick
+	return s.scanTemplateSpan(start, TemplateNoSub, TemplateHead)
+}
 
+// ScanTemplateContinuation scans the part of a template literal that follows a
+// substitution. The scanner must be positioned immediately after the '}' that
+// closes the substitution; the returned TemplateMiddle or TemplateTail token
+// starts at that '}'.
+func (s *Scanner) ScanTemplateContinuation() Token {
+	s.offset = s.pos - 1
+	s.tokenLine = s.line
+	s.tokenColumn = s.column - 1
+	s.current = s.scanTemplateSpan(s.offset, TemplateTail, TemplateMiddle)
+	return s.current
+}
+
+// scanTemplateSpan scans template characters up to the closing backtick, which
+// yields a closeType token, or up to a '${', which yields a substType token.
+func (s *Scanner) scanTemplateSpan(start int, closeType, substType TokenType) Token {
 	var sb strings.Builder
 
 	for {
@@ -329,22 +392,14 @@ func (s *Scanner) scanTemplate() Token {
 
 		if ch == '`' {
 			s.next() // consume closing backtick
-			// Template with no substitution
-			return s.createToken(TemplateNoSub, sb.String())
+			return s.createToken(closeType, sb.String())
 		}
 
 		if ch == '$' && s.peek(1) == '{' {
 			// Template substitution
 			s.next() // consume '$'
 			s.next() // consume '{'
-
-			// Return template head or middle token
-			tokenType := TemplateHead
-			if start != s.offset {
-				tokenType = TemplateMiddle
-			}
-
-			return s.createToken(tokenType, sb.String())
+			return s.createToken(substType, sb.String())
 		}
 
 		if ch == '\\' {
@@ -353,10 +408,67 @@ func (s *Scanner) scanTemplate() Token {
 			escaped := s.scanEscapeSequence()
 			sb.WriteString(escaped)
 		} else {
-			sb.WriteRune(ch)
-			s.next()
+			sb.WriteRune(s.nextRune())
+		}
+	}
+}
+
+// ScanJSXText scans JSX child text from the current position up to the next
+// '{' or '<'. The literal is the raw source text; HTML entities are left for
+// the parser to decode. If no text precedes the next token, that token is
+// scanned and returned instead.
+func (s *Scanner) ScanJSXText() Token {
+	s.offset = s.pos
+	s.tokenLine = s.line
+	s.tokenColumn = s.column
+
+	for {
+		ch := s.char()
+		if ch == -1 || ch == '{' || ch == '<' {
+			break
 		}
+		s.nextRune()
+	}
+
+	if s.pos == s.offset {
+		return s.Scan()
 	}
+	s.current = s.createToken(JSXText, s.source[s.offset:s.pos])
+	return s.current
+}
+
+// ScanJSXAttributeValue scans the value that follows '=' in a JSX attribute.
+// Quoted values are scanned without escape processing, since JSX strings have
+// no escapes, and returned as a JSXAttributeString whose literal is the raw
+// text including the quotes. Any other value is scanned as a regular token.
+func (s *Scanner) ScanJSXAttributeValue() Token {
+	s.skipWhitespace()
+
+	quote := s.char()
+	if quote != '"' && quote != '\'' {
+		return s.Scan()
+	}
+
+	s.offset = s.pos
+	s.tokenLine = s.line
+	s.tokenColumn = s.column
+	s.next() // consume opening quote
+
+	for {
+		ch := s.char()
+		if ch == -1 {
+			// Unterminated string
+			s.current = s.createToken(ILLEGAL, s.source[s.offset:s.pos])
+			return s.current
+		}
+		s.nextRune()
+		if ch == quote {
+			break
+		}
+	}
+
+	s.current = s.createToken(JSXAttributeString, s.source[s.offset:s.pos])
+	return s.current
 }
 
 // scanLineComment scans a single-line comment (//...).
@@ -398,9 +510,20 @@ func (s *Scanner) scanBlockComment() Token {
 	return s.createToken(COMMENT, s.source[start:s.pos])
 }
 
+// ScanRegExp rescans the '/' or '/=' token just returned by Scan as the start
+// of a regular expression literal. The scanner cannot tell the two apart on
+// its own, so its caller decides from the preceding token.
+func (s *Scanner) ScanRegExp() Token {
+	s.pos = s.offset
+	s.line = s.tokenLine
+	s.column = s.tokenColumn
+	s.current = s.scanRegExp()
+	return s.current
+}
+
 // scanRegExp scans a regular expression literal.
 //
-//nolint:cyclop,unused // RegExp scanning requires handling many special cases, will be used in future
+//nolint:cyclop // RegExp scanning requires handling many special cases
 func (s *Scanner) scanRegExp() Token {
 	start := s.pos
 	s.next() // consume opening '/'
@@ -434,14 +557,9 @@ func (s *Scanner) scanRegExp() Token {
 		s.next()
 	}
 
-	// Scan regex flags (g, i, m, s, u, y)
-	for {
-		ch := s.char()
-		if ch == 'g' || ch == 'i' || ch == 'm' || ch == 's' || ch == 'u' || ch == 'y' {
-			s.next()
-		} else {
-			break
-		}
+	// Scan regex flags (d, g, i, m, s, u, v, y)
+	for strings.ContainsRune("dgimsuvy", s.char()) {
+		s.next()
 	}
 
 	return s.createToken(REGEXP, s.source[start:s.pos])
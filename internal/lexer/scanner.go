@@ -18,7 +18,8 @@ type Scanner struct {
 	fullOffset int // Start including whitespace/comments
 	length     int // Length of source in bytes
 
-	// Line/column tracking (1-based line, 0-based column)
+	// Line/column tracking (1-based line, 0-based column). Columns are
+	// counted in UTF-16 code units, matching ESTree's loc.column.
 	line        int
 	column      int
 	tokenLine   int // Line number at token start
@@ -60,6 +61,18 @@ func (s *Scanner) char() rune {
 	return rune(s.source[s.pos])
 }
 
+// currentRune decodes the full rune at the current position and returns it
+// with its size in bytes. Returns -1 and 0 if at EOF.
+func (s *Scanner) currentRune() (rune, int) {
+	if s.pos >= s.length {
+		return -1, 0
+	}
+	if b := s.source[s.pos]; b < utf8.RuneSelf {
+		return rune(b), 1
+	}
+	return utf8.DecodeRuneInString(s.source[s.pos:])
+}
+
 // peek returns the character at the given offset from current position.
 // Returns -1 if offset is out of bounds.
 func (s *Scanner) peek(offset int) rune {
@@ -94,12 +107,28 @@ func (s *Scanner) next() rune {
 			s.pos++
 		}
 	} else {
-		s.column++
+		s.column += utf16Width(byte(ch))
 	}
 
 	return ch
 }
 
+// utf16Width returns how many UTF-16 code units the rune starting with the
+// given UTF-8 byte contributes to a column. Continuation bytes contribute
+// nothing and 4-byte sequences encode as a surrogate pair.
+func utf16Width(b byte) int {
+	switch {
+	case b < utf8.RuneSelf:
+		return 1
+	case !utf8.RuneStart(b):
+		return 0
+	case b >= 0xF0:
+		return 2
+	default:
+		return 1
+	}
+}
+
 // nextRune advances the position by one full UTF-8 rune and returns it.
 // Returns -1 if at EOF.
 //
@@ -120,7 +149,7 @@ func (s *Scanner) nextRune() rune {
 	s.pos += size
 
 	// Track line/column
-	if ch == '\n' {
+	if ch == '\n' || ch == lineSeparator || ch == paragraphSeparator {
 		s.line++
 		s.column = 0
 	} else if ch == '\r' {
@@ -132,27 +161,55 @@ func (s *Scanner) nextRune() rune {
 		}
 	} else {
 		s.column++
+		if ch > 0xFFFF {
+			// Encoded as a surrogate pair in UTF-16.
+			s.column++
+		}
 	}
 
 	return ch
 }
 
-// skipWhitespace advances the scanner position past any whitespace characters.
+// skipWhitespace advances the scanner position past any whitespace and line
+// terminator characters, including the Unicode ones ECMAScript recognizes.
 func (s *Scanner) skipWhitespace() {
 	for {
-		ch := s.char()
-		if ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n' {
-			s.next()
-		} else {
+		ch, _ := s.currentRune()
+		if !isWhitespace(ch) && !isLineTerminator(ch) {
 			break
 		}
+		s.nextRune()
+	}
+}
+
+// Unicode code points with special meaning to the lexical grammar.
+const (
+	zeroWidthNonJoiner = '\u200C'
+	zeroWidthJoiner    = '\u200D'
+	lineSeparator      = '\u2028'
+	paragraphSeparator = '\u2029'
+	noBreakSpace       = '\u00A0'
+	byteOrderMark      = '\uFEFF'
+)
+
+// isWhitespace checks if a rune is ECMAScript WhiteSpace: tab, vertical tab,
+// form feed, space, NBSP, BOM or any other Zs (space separator) character.
+func isWhitespace(ch rune) bool {
+	switch ch {
+	case '\t', '\v', '\f', ' ', noBreakSpace, byteOrderMark:
+		return true
 	}
+	return ch >= utf8.RuneSelf && unicode.Is(unicode.Zs, ch)
+}
+
+// isLineTerminator checks if a rune is an ECMAScript LineTerminator.
+func isLineTerminator(ch rune) bool {
+	return ch == '\n' || ch == '\r' || ch == lineSeparator || ch == paragraphSeparator
 }
 
-// isLetter checks if a rune is a letter (including Unicode).
-func isLetter(ch rune) bool {
-	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch == '_' || ch == '$' ||
-		(ch >= utf8.RuneSelf && unicode.IsLetter(ch))
+// isASCIILetter checks if a rune is an ASCII letter, '_' or '$'.
+func isASCIILetter(ch rune) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch == '_' || ch == '$'
 }
 
 // isDigit checks if a rune is a decimal digit.
@@ -175,14 +232,23 @@ func isOctalDigit(ch rune) bool {
 	return ch >= '0' && ch <= '7'
 }
 
-// isIdentifierStart checks if a rune can start an identifier.
+// isIdentifierStart checks if a rune can start an identifier: '$', '_' or a
+// character with the Unicode ID_Start property.
 func isIdentifierStart(ch rune) bool {
-	return isLetter(ch)
+	if ch < utf8.RuneSelf {
+		return isASCIILetter(ch)
+	}
+	return unicode.In(ch, unicode.L, unicode.Nl, unicode.Other_ID_Start)
 }
 
-// isIdentifierPart checks if a rune can be part of an identifier.
+// isIdentifierPart checks if a rune can be part of an identifier: '$', ZWNJ,
+// ZWJ or a character with the Unicode ID_Continue property.
 func isIdentifierPart(ch rune) bool {
-	return isLetter(ch) || isDigit(ch) || (ch >= utf8.RuneSelf && unicode.IsDigit(ch))
+	if ch < utf8.RuneSelf {
+		return isASCIILetter(ch) || isDigit(ch)
+	}
+	return isIdentifierStart(ch) || ch == zeroWidthNonJoiner || ch == zeroWidthJoiner ||
+		unicode.In(ch, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc, unicode.Other_ID_Continue)
 }
 
 // createToken creates a token with the current position information.
@@ -1,6 +1,7 @@
 package lexer
 
 import (
+	"slices"
 	"testing"
 )
 
@@ -161,7 +162,7 @@ func TestScannerIdentifiers(t *testing.T) {
 		{"dollar", "$jQuery"},
 		{"mixed", "foo_bar$123"},
 		{"unicode", "naïve"},
-		{"emoji", "test😀"},
+		{"astral plane", "test𝑥"},
 	}
 
 	for _, tt := range tests {
@@ -178,6 +179,63 @@ func TestScannerIdentifiers(t *testing.T) {
 	}
 }
 
+func TestScannerUnicodeIdentifiers(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		typ     TokenType
+		literal string
+	}{
+		{"ID_Start letter", "café", IDENT, "café"},
+		{"letterlike symbol", "ℳ", IDENT, "ℳ"},
+		{"Other_ID_Start", "℘x", IDENT, "℘x"},
+		{"combining mark", "é", IDENT, "é"},
+		{"ZWNJ and ZWJ", "a\u200cb\u200dc", IDENT, "a\u200cb\u200dc"},
+		{"escape", `\u0061bc`, IDENT, "abc"},
+		{"braced escape", `a\u{62}c`, IDENT, "abc"},
+		{"astral escape", `\u{1D465}`, IDENT, "𝑥"},
+		{"escaped contextual keyword", `\u0061sync`, IDENT, "async"},
+		{"escaped reserved word", `\u0062reak`, ILLEGAL, `\u0062reak`},
+		{"escape of invalid start", `\u0031a`, ILLEGAL, `\u0031`},
+		{"malformed escape", `\u00`, ILLEGAL, `\u00`},
+		{"emoji is not an identifier part", "test😀", IDENT, "test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := NewScanner(tt.input).Scan()
+			if token.Type != tt.typ {
+				t.Errorf("expected %v, got %v", tt.typ, token.Type)
+			}
+			if token.Literal != tt.literal {
+				t.Errorf("expected %q, got %q", tt.literal, token.Literal)
+			}
+		})
+	}
+}
+
+func TestScannerUnicodeWhitespace(t *testing.T) {
+	// NBSP, BOM, EN QUAD (Zs), vertical tab, form feed and the two Unicode
+	// line terminators all separate tokens.
+	input := "\ufeffa\u00a0b\u2000c\vd\fe\u2028f\u2029g"
+
+	scanner := NewScanner(input)
+	var got []string
+	for tok := scanner.Scan(); tok.Type != EOF; tok = scanner.Scan() {
+		if tok.Type != IDENT {
+			t.Fatalf("expected IDENT, got %v %q", tok.Type, tok.Literal)
+		}
+		got = append(got, tok.Literal)
+	}
+	if !slices.Equal(got, []string{"a", "b", "c", "d", "e", "f", "g"}) {
+		t.Errorf("unexpected tokens %q", got)
+	}
+
+	if line := scanner.Current().Line; line != 3 {
+		t.Errorf("expected line separators to advance to line 3, got %d", line)
+	}
+}
+
 func TestScannerNumbers(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -228,6 +286,8 @@ func TestScannerStrings(t *testing.T) {
 		{"hex_escape", `"\x41"`, "A"},
 		{"unicode_escape", `"\u0041"`, "A"},
 		{"unicode_extended", `"\u{1F600}"`, "😀"},
+		{"non_ascii", `"😀 é 中文"`, "😀 é 中文"},
+		{"non_ascii_with_escape", `'é\n😀'`, "é\n😀"},
 	}
 
 	for _, tt := range tests {
@@ -265,6 +325,25 @@ func TestScannerTemplates(t *testing.T) {
 	}
 }
 
+func TestScannerTemplateValues(t *testing.T) {
+	scanner := NewScanner("`é ${x} 😀`")
+
+	head := scanner.Scan()
+	if head.Type != TemplateHead || head.Literal != "é " {
+		t.Errorf("expected TemplateHead %q, got %v %q", "é ", head.Type, head.Literal)
+	}
+	if ident := scanner.Scan(); ident.Type != IDENT {
+		t.Fatalf("expected IDENT, got %v", ident.Type)
+	}
+	if brace := scanner.Scan(); brace.Type != RBRACE {
+		t.Fatalf("expected RBRACE, got %v", brace.Type)
+	}
+	tail := scanner.ScanTemplateContinuation()
+	if tail.Type != TemplateTail || tail.Literal != " 😀" {
+		t.Errorf("expected TemplateTail %q, got %v %q", " 😀", tail.Type, tail.Literal)
+	}
+}
+
 func TestScannerComments(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -318,6 +397,38 @@ func TestScannerPositionTracking(t *testing.T) {
 	}
 }
 
+func TestScannerColumnsInUTF16(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		column int
+	}{
+		{"ascii", `"ab" x`, 5},
+		{"two-byte rune", `"é" x`, 4},
+		{"three-byte rune", `"中文" x`, 5},
+		{"surrogate pair", `"😀" x`, 5},
+		{"comment", "/* 😀 */ x", 9},
+		{"next line", "'😀'\n  x", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner := NewScanner(tt.input)
+			scanner.SetSkipComments(true)
+			tok := scanner.Scan()
+			for tok.Type != IDENT && tok.Type != EOF {
+				tok = scanner.Scan()
+			}
+			if tok.Literal != "x" {
+				t.Fatalf("expected identifier x, got %q", tok.Literal)
+			}
+			if tok.Column != tt.column {
+				t.Errorf("expected column %d, got %d", tt.column, tok.Column)
+			}
+		})
+	}
+}
+
 func TestScannerComplexExpression(t *testing.T) {
 	input := `const x: number = 42;`
 	expected := []TokenType{CONST, IDENT, COLON, NumberKeyword, ASSIGN, NUMBER, SEMICOLON, EOF}
@@ -447,3 +558,100 @@ func TestLexerNextToken(t *testing.T) {
 		t.Errorf("expected IDENT, got %v", token2.Type)
 	}
 }
+
+func TestScannerSaveRestore(t *testing.T) {
+	scanner := NewScanner("a + b")
+	scanner.Scan()
+
+	state := scanner.Save()
+	first := scanner.Scan()
+	scanner.Scan()
+
+	scanner.Restore(state)
+	again := scanner.Scan()
+	if again != first {
+		t.Errorf("expected %+v after restore, got %+v", first, again)
+	}
+	if next := scanner.Scan(); next.Type != IDENT || next.Literal != "b" {
+		t.Errorf("expected IDENT 'b', got %v %q", next.Type, next.Literal)
+	}
+}
+
+func TestScannerTemplateContinuation(t *testing.T) {
+	scanner := NewScanner("`a${x}b${y}c`")
+
+	expected := []struct {
+		typ     TokenType
+		literal string
+	}{
+		{TemplateHead, "a"},
+		{IDENT, "x"},
+		{TemplateMiddle, "b"},
+		{IDENT, "y"},
+		{TemplateTail, "c"},
+	}
+
+	for i, want := range expected {
+		var token Token
+		if want.typ == TemplateMiddle || want.typ == TemplateTail {
+			if rbrace := scanner.Scan(); rbrace.Type != RBRACE {
+				t.Fatalf("token %d: expected RBRACE, got %v", i, rbrace.Type)
+			}
+			token = scanner.ScanTemplateContinuation()
+		} else {
+			token = scanner.Scan()
+		}
+		if token.Type != want.typ || token.Literal != want.literal {
+			t.Errorf("token %d: expected %v %q, got %v %q", i, want.typ, want.literal, token.Type, token.Literal)
+		}
+	}
+}
+
+func TestScannerJSX(t *testing.T) {
+	scanner := NewScanner(`<a b='x\n' c={1}> // not a comment &amp; </a>`)
+
+	expected := []struct {
+		typ     TokenType
+		literal string
+		scan    func() Token
+	}{
+		{LSS, "<", scanner.Scan},
+		{IDENT, "a", scanner.Scan},
+		{IDENT, "b", scanner.Scan},
+		{ASSIGN, "=", scanner.Scan},
+		{JSXAttributeString, `'x\n'`, scanner.ScanJSXAttributeValue},
+		{IDENT, "c", scanner.Scan},
+		{ASSIGN, "=", scanner.Scan},
+		{LBRACE, "{", scanner.ScanJSXAttributeValue},
+		{NUMBER, "1", scanner.Scan},
+		{RBRACE, "}", scanner.Scan},
+		{GTR, ">", scanner.Scan},
+		{JSXText, " // not a comment &amp; ", scanner.ScanJSXText},
+		{LSS, "<", scanner.ScanJSXText},
+	}
+
+	for i, want := range expected {
+		token := want.scan()
+		if token.Type != want.typ || token.Literal != want.literal {
+			t.Errorf("token %d: expected %v %q, got %v %q", i, want.typ, want.literal, token.Type, token.Literal)
+		}
+	}
+}
+
+func TestScannerRegExp(t *testing.T) {
+	scanner := NewScanner("x = /=[/]\\//dv.source")
+
+	scanner.Scan() // x
+	scanner.Scan() // =
+	if tok := scanner.Scan(); tok.Type != QuoAssign {
+		t.Fatalf("expected /=, got %v %q", tok.Type, tok.Literal)
+	}
+
+	tok := scanner.ScanRegExp()
+	if tok.Type != REGEXP || tok.Literal != `/=[/]\//dv` || tok.Pos != 4 || tok.Column != 4 {
+		t.Errorf("expected regular expression at 4, got %v %q at %d", tok.Type, tok.Literal, tok.Pos)
+	}
+	if tok := scanner.Scan(); tok.Type != PERIOD {
+		t.Errorf("expected . after the regular expression, got %v %q", tok.Type, tok.Literal)
+	}
+}
@@ -1,8 +1,8 @@
 package parser
 
 import (
-	"github.com/kdy1/go-typescript-eslint/internal/ast"
 	"github.com/kdy1/go-typescript-eslint/internal/lexer"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 )
 
 // parseImportDeclaration parses an import declaration.
@@ -49,12 +49,107 @@ func (p *Parser) parseImportDeclaration() (*ast.ImportDeclaration, error) {
 }
 
 // parseImportKind checks for and parses the 'type' keyword in TypeScript imports.
+// In import type from 'x' and import type, { a } from 'x' the 'type' keyword is
+// the default binding rather than a modifier.
 func (p *Parser) parseImportKind() string {
-	if p.current.Type == lexer.TYPE {
-		p.nextToken()
-		return "type"
+	if p.current.Type != lexer.TYPE {
+		return "value"
+	}
+
+	switch {
+	case p.peek.Type == lexer.LBRACE, p.peek.Type == lexer.MUL:
+	case p.peek.Type == lexer.FROM:
+		// import type from from 'x'
+		isModifier := p.lookahead(func() bool {
+			p.nextToken() // consume 'type'
+			return p.peek.Type == lexer.FROM
+		})
+		if !isModifier {
+			return "value"
+		}
+	case p.peek.Type == lexer.IDENT, isContextualKeyword(p.peek.Type):
+	default:
+		return "value"
 	}
-	return "value"
+
+	p.nextToken()
+	return "type"
+}
+
+// parseSpecifierKind checks for and parses a 'type' modifier on an import or
+// export specifier. In { type } and { type as x } the 'type' keyword is the
+// specifier name itself, while { type as } and { type as as x } refer to a
+// type-only specifier named 'as'.
+func (p *Parser) parseSpecifierKind() string {
+	if p.current.Type != lexer.TYPE {
+		return "value"
+	}
+
+	isModifier := false
+	switch {
+	case p.peek.Type == lexer.AS:
+		isModifier = p.lookahead(func() bool {
+			p.nextToken() // consume 'type'
+			p.nextToken() // consume 'as'
+			// { type as }, { type as as x } and { type as as as } are type-only
+			// imports of 'as'; { type as x } and { type as as } rename 'type'.
+			if p.current.Type == lexer.AS {
+				return isIdentifierName(p.peek.Type)
+			}
+			return !isIdentifierName(p.current.Type)
+		})
+	case isIdentifierName(p.peek.Type), p.peek.Type == lexer.STRING:
+		isModifier = true
+	}
+
+	if !isModifier {
+		return "value"
+	}
+	p.nextToken()
+	return "type"
+}
+
+// parseModuleExportName parses the name of an import or export specifier,
+// which may be any identifier name, including reserved words, or a string literal.
+func (p *Parser) parseModuleExportName() (ast.Node, error) {
+	if p.current.Type == lexer.STRING {
+		return p.parseModuleSpecifier()
+	}
+	return p.parseIdentifierName()
+}
+
+// parseIdentifierName parses an identifier name, which may be a reserved word.
+func (p *Parser) parseIdentifierName() (*ast.Identifier, error) {
+	if !isIdentifierName(p.current.Type) {
+		return nil, p.errorAtCurrent("expected identifier")
+	}
+
+	name := &ast.Identifier{
+		BaseNode: ast.BaseNode{
+			NodeType: ast.NodeTypeIdentifier.String(),
+			Range:    &ast.Range{p.current.Pos, p.current.End},
+		},
+		Name: p.current.Literal,
+	}
+	p.nextToken()
+	return name, nil
+}
+
+// parseImportBinding parses the local name bound by an import specifier.
+func (p *Parser) parseImportBinding() (*ast.Identifier, error) {
+	if p.current.Type != lexer.IDENT && !isContextualKeyword(p.current.Type) {
+		return nil, p.errorAtCurrent("expected identifier")
+	}
+
+	local := &ast.Identifier{
+		BaseNode: ast.BaseNode{
+			NodeType: ast.NodeTypeIdentifier.String(),
+			Range:    &ast.Range{p.current.Pos, p.current.End},
+		},
+		Name: p.current.Literal,
+	}
+	p.nextToken()
+	return local, nil
 }
 
 // parseSideEffectImport parses a side-effect import: import 'module'
@@ -86,7 +181,7 @@ func (p *Parser) parseImportSpecifierList() ([]interface{}, error) {
 	specifiers := []interface{}{}
 
 	// Parse default import
-	if p.current.Type == lexer.IDENT {
+	if p.current.Type == lexer.IDENT || (isContextualKeyword(p.current.Type) && p.current.Type != lexer.FROM) {
 		defaultSpec := p.parseDefaultImportSpecifier()
 		specifiers = append(specifiers, defaultSpec)
 		p.nextToken()
@@ -194,18 +289,10 @@ func (p *Parser) parseImportNamespaceSpecifier() (*ast.ImportNamespaceSpecifier,
 		return nil, err
 	}
 
-	if p.current.Type != lexer.IDENT {
-		return nil, p.errorAtCurrent("expected identifier")
-	}
-
-	local := &ast.Identifier{
-		BaseNode: ast.BaseNode{
-			NodeType: ast.NodeTypeIdentifier.String(),
-			Range:    &ast.Range{p.current.Pos, p.current.End},
-		},
-		Name: p.current.Literal,
+	local, err := p.parseImportBinding()
+	if err != nil {
+		return nil, err
 	}
-	p.nextToken()
 
 	return &ast.ImportNamespaceSpecifier{
 		BaseNode: ast.BaseNode{
@@ -226,40 +313,21 @@ func (p *Parser) parseImportSpecifier() (*ast.ImportSpecifier, error) {
 	start := p.current.Pos
 
 	// Check for type import (TypeScript)
-	importKind := "value"
-	if p.current.Type == lexer.TYPE {
-		importKind = "type"
-		p.nextToken()
-	}
-
-	if p.current.Type != lexer.IDENT {
-		return nil, p.errorAtCurrent("expected identifier")
-	}
+	importKind := p.parseSpecifierKind()
 
-	imported := &ast.Identifier{
-		BaseNode: ast.BaseNode{
-			NodeType: ast.NodeTypeIdentifier.String(),
-			Range:    &ast.Range{p.current.Pos, p.current.End},
-		},
-		Name: p.current.Literal,
+	imported, err := p.parseIdentifierName()
+	if err != nil {
+		return nil, err
 	}
-	p.nextToken()
 
 	local := imported
 
 	// Check for 'as' clause
 	if p.consume(lexer.AS) {
-		if p.current.Type != lexer.IDENT {
-			return nil, p.errorAtCurrent("expected identifier")
-		}
-		local = &ast.Identifier{
-			BaseNode: ast.BaseNode{
-				NodeType: ast.NodeTypeIdentifier.String(),
-				Range:    &ast.Range{p.current.Pos, p.current.End},
-			},
-			Name: p.current.Literal,
+		local, err = p.parseImportBinding()
+		if err != nil {
+			return nil, err
 		}
-		p.nextToken()
 	}
 
 	return &ast.ImportSpecifier{
@@ -340,9 +408,10 @@ func (p *Parser) parseExportDeclaration() (ast.Statement, error) {
 	start := p.current.Pos
 	p.nextToken() // consume 'export'
 
-	// Handle export type (TypeScript)
+	// Handle export type { ... } and export type * (TypeScript).
+	// Otherwise 'type' starts a type alias declaration.
 	exportKind := "value"
-	if p.current.Type == lexer.TYPE {
+	if p.current.Type == lexer.TYPE && (p.peek.Type == lexer.LBRACE || p.peek.Type == lexer.MUL) {
 		exportKind = "type"
 		p.nextToken()
 	}
@@ -379,6 +448,12 @@ func (p *Parser) parseExportWithDeclaration(start int, exportKind string) (ast.S
 		decl, _ = declaration.(ast.Declaration) //nolint:errcheck // Type assertion is optional, error can be ignored
 	}
 
+	// Interfaces and type aliases only declare types
+	switch declaration.(type) {
+	case *ast.TSInterfaceDeclaration, *ast.TSTypeAliasDeclaration:
+		exportKind = "type"
+	}
+
 	return &ast.ExportNamedDeclaration{
 		BaseNode: ast.BaseNode{
 			NodeType: ast.NodeTypeExportNamedDeclaration.String(),
@@ -437,12 +512,18 @@ func (p *Parser) parseExportDefaultDeclaration(start int) (*ast.ExportDefaultDec
 		return nil, err
 	}
 
+	exportKind := "value"
+	if _, ok := declaration.(*ast.TSInterfaceDeclaration); ok {
+		exportKind = "type"
+	}
+
 	return &ast.ExportDefaultDeclaration{
 		BaseNode: ast.BaseNode{
 			NodeType: ast.NodeTypeExportDefaultDeclaration.String(),
 			Range:    &ast.Range{start, p.current.Pos},
 		},
 		Declaration: declaration,
+		ExportKind:  &exportKind,
 	}, nil
 }
 
@@ -549,40 +630,21 @@ func (p *Parser) parseExportSpecifier() (*ast.ExportSpecifier, error) {
 	start := p.current.Pos
 
 	// Check for type export (TypeScript)
-	exportKind := "value"
-	if p.current.Type == lexer.TYPE {
-		exportKind = "type"
-		p.nextToken()
-	}
-
-	if p.current.Type != lexer.IDENT {
-		return nil, p.errorAtCurrent("expected identifier")
-	}
+	exportKind := p.parseSpecifierKind()
 
-	local := &ast.Identifier{
-		BaseNode: ast.BaseNode{
-			NodeType: ast.NodeTypeIdentifier.String(),
-			Range:    &ast.Range{p.current.Pos, p.current.End},
-		},
-		Name: p.current.Literal,
+	local, err := p.parseModuleExportName()
+	if err != nil {
+		return nil, err
 	}
-	p.nextToken()
 
 	exported := local
 
 	// Check for 'as' clause
 	if p.consume(lexer.AS) {
-		if p.current.Type != lexer.IDENT {
-			return nil, p.errorAtCurrent("expected identifier")
-		}
-		exported = &ast.Identifier{
-			BaseNode: ast.BaseNode{
-				NodeType: ast.NodeTypeIdentifier.String(),
-				Range:    &ast.Range{p.current.Pos, p.current.End},
-			},
-			Name: p.current.Literal,
+		exported, err = p.parseModuleExportName()
+		if err != nil {
+			return nil, err
 		}
-		p.nextToken()
 	}
 
 	return &ast.ExportSpecifier{
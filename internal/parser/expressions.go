@@ -1,8 +1,8 @@
 package parser
 
 import (
-	"github.com/kdy1/go-typescript-eslint/internal/ast"
 	"github.com/kdy1/go-typescript-eslint/internal/lexer"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 )
 
 // Operator precedence levels (higher number = higher precedence)
@@ -179,7 +179,10 @@ func (p *Parser) createAssignmentExpression(left ast.Expression, operator string
 func (p *Parser) parseConditionalExpression(test ast.Expression) (ast.Expression, error) {
 	p.nextToken() // consume '?'
 
+	oldNoReturnTypeArrowAt := p.noReturnTypeArrowAt
+	p.noReturnTypeArrowAt = p.current.Pos
 	consequent, err := p.parseAssignmentExpression()
+	p.noReturnTypeArrowAt = oldNoReturnTypeArrowAt
 	if err != nil {
 		return nil, err
 	}
@@ -279,11 +282,17 @@ func (p *Parser) parseAwaitExpression(start int) (ast.Expression, error) {
 	}, nil
 }
 
-// parseLessTokenExpression parses < token which could be type assertion or JSX.
+// parseLessTokenExpression parses < token which could be a generic arrow
+// function, a type assertion or JSX.
 func (p *Parser) parseLessTokenExpression() (ast.Expression, error) {
+	arrow, ok, err := p.tryParseArrowFunction(p.current.Pos, false)
+	if ok {
+		return arrow, err
+	}
+
 	// Type assertion (TypeScript) or JSX
 	if p.jsxEnabled {
-		return p.parseJSXElement()
+		return p.parseJSXElementOrFragment(false)
 	}
 	return p.parseTSTypeAssertion()
 }
@@ -326,12 +335,31 @@ func (p *Parser) parseMemberOrCallExpression() (ast.Expression, error) {
 		return nil, err
 	}
 
+	// A single ChainExpression wraps the whole chain once an optional suffix
+	// is seen, so a?.b.c() is ChainExpression(CallExpression(MemberExpression)).
+	inChain := false
 	for {
+		switch p.current.Type {
+		case lexer.OPTIONAL:
+			inChain = true
+		case lexer.TEMPLATE, lexer.TemplateHead, lexer.TemplateNoSub:
+			if inChain {
+				return nil, p.errorAtCurrent("tagged template expressions are not permitted in an optional chain")
+			}
+		}
 		newExpr, done, err := p.parseMemberOrCallSuffix(expr)
 		if err != nil {
 			return nil, err
 		}
 		if done {
+			if inChain {
+				return &ast.ChainExpression{
+					BaseNode: ast.BaseNode{
+						NodeType: ast.NodeTypeChainExpression.String(),
+					},
+					Expression: expr,
+				}, nil
+			}
 			return expr, nil
 		}
 		expr = newExpr
@@ -352,11 +380,14 @@ func (p *Parser) parseMemberOrCallSuffix(expr ast.Expression) (ast.Expression, b
 		newExpr, err := p.parseOptionalChaining(expr)
 		return newExpr, false, err
 	case lexer.LPAREN:
-		newExpr, err := p.parseCallExpression(expr)
+		p.nextToken()
+		newExpr, err := p.parseCallExpression(expr, nil)
 		return newExpr, false, err
-	case lexer.TEMPLATE, lexer.TemplateHead:
-		newExpr, err := p.parseTaggedTemplate(expr)
+	case lexer.TEMPLATE, lexer.TemplateHead, lexer.TemplateNoSub:
+		newExpr, err := p.parseTaggedTemplate(expr, nil)
 		return newExpr, false, err
+	case lexer.LSS:
+		return p.parseTypeArgumentsSuffix(expr)
 	case lexer.NOT:
 		newExpr := p.parseNonNullAssertion(expr)
 		return newExpr, false, nil
@@ -365,6 +396,100 @@ func (p *Parser) parseMemberOrCallSuffix(expr ast.Expression) (ast.Expression, b
 	}
 }
 
+// parseTypeArgumentsSuffix parses type arguments following an expression:
+// f<T>(), tag<T>`template` or the instantiation expression f<T>.
+// If the '<' does not start type arguments, parsing of suffixes is done and
+// the '<' is left for the binary expression parser.
+func (p *Parser) parseTypeArgumentsSuffix(expr ast.Expression) (ast.Expression, bool, error) {
+	typeArgs := p.tryParseTypeArgumentsInExpression()
+	if typeArgs == nil {
+		return expr, true, nil
+	}
+
+	switch p.current.Type {
+	case lexer.LPAREN:
+		p.nextToken()
+		newExpr, err := p.parseCallExpression(expr, typeArgs)
+		return newExpr, false, err
+	case lexer.TEMPLATE, lexer.TemplateHead, lexer.TemplateNoSub:
+		newExpr, err := p.parseTaggedTemplate(expr, typeArgs)
+		return newExpr, false, err
+	default:
+		return &ast.TSInstantiationExpression{
+			BaseNode: ast.BaseNode{
+				NodeType: ast.NodeTypeTSInstantiationExpression.String(),
+			},
+			Expression:     expr,
+			TypeArguments:  typeArgs,
+			TypeParameters: typeArgs,
+		}, false, nil
+	}
+}
+
+// tryParseTypeArgumentsInExpression speculatively parses a type argument list
+// in an expression context. It returns nil without consuming any input when
+// the '<' is a relational operator, as in a < b > c.
+func (p *Parser) tryParseTypeArgumentsInExpression() *ast.TSTypeParameterInstantiation {
+	typeArgs, _ := tryParse(p, func() (*ast.TSTypeParameterInstantiation, error) {
+		typeArgs, err := p.parseTSTypeArguments()
+		if err != nil {
+			return nil, err
+		}
+		if !p.canFollowTypeArgumentsInExpression() {
+			return nil, p.errorAtCurrent("unexpected token after type arguments")
+		}
+		return typeArgs, nil
+	})
+	return typeArgs
+}
+
+// canFollowTypeArgumentsInExpression reports whether the current token may
+// follow a type argument list in an expression, following the rules of the
+// TypeScript compiler.
+func (p *Parser) canFollowTypeArgumentsInExpression() bool {
+	switch p.current.Type {
+	case lexer.LPAREN, lexer.TEMPLATE, lexer.TemplateHead, lexer.TemplateNoSub:
+		return true
+	// A type argument list followed by '<' never makes sense, and one followed
+	// by '>' is ambiguous with a '>>' operator. '+' and '-' are unary here.
+	case lexer.LSS, lexer.GTR, lexer.ADD, lexer.SUB:
+		return false
+	}
+
+	// Favor type arguments when followed by a line break, a binary operator,
+	// or something that cannot start an expression.
+	return p.hasPrecedingLineBreak() || isBinaryOperator(p.current.Type) || !p.isStartOfExpression()
+}
+
+// hasPrecedingLineBreak reports whether a line break separates the current
+// token from the previous one.
+func (p *Parser) hasPrecedingLineBreak() bool {
+	prev := len(p.allTokens) - 1
+	if p.current.Type != lexer.EOF {
+		prev--
+	}
+	if prev < 0 {
+		return false
+	}
+	return p.current.Line > p.allTokens[prev].Line
+}
+
+// isStartOfExpression reports whether the current token can begin an expression.
+func (p *Parser) isStartOfExpression() bool {
+	switch p.current.Type {
+	case lexer.IDENT, lexer.NUMBER, lexer.STRING, lexer.REGEXP,
+		lexer.TEMPLATE, lexer.TemplateHead, lexer.TemplateNoSub,
+		lexer.THIS, lexer.SUPER, lexer.NULL, lexer.TRUE, lexer.FALSE,
+		lexer.FUNCTION, lexer.CLASS, lexer.NEW, lexer.IMPORT,
+		lexer.TYPEOF, lexer.VOID, lexer.DELETE,
+		lexer.LPAREN, lexer.LBRACK, lexer.LBRACE,
+		lexer.ADD, lexer.SUB, lexer.NOT, lexer.BNOT, lexer.INC, lexer.DEC,
+		lexer.LSS, lexer.QUO, lexer.QuoAssign, lexer.JSXTagStart:
+		return true
+	}
+	return isContextualKeyword(p.current.Type)
+}
+
 // parseDotMemberAccess parses dot member access: obj.prop
 func (p *Parser) parseDotMemberAccess(expr ast.Expression) (ast.Expression, error) {
 	p.nextToken()
@@ -415,7 +540,17 @@ func (p *Parser) parseOptionalChaining(expr ast.Expression) (ast.Expression, err
 	if p.consume(lexer.LBRACK) {
 		return p.parseOptionalBracketAccess(expr)
 	} else if p.consume(lexer.LPAREN) {
-		return p.parseOptionalCall(expr)
+		return p.parseOptionalCall(expr, nil)
+	} else if p.current.Type == lexer.LSS {
+		// obj?.<T>()
+		typeArgs, err := p.parseTSTypeArguments()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(lexer.LPAREN); err != nil {
+			return nil, err
+		}
+		return p.parseOptionalCall(expr, typeArgs)
 	}
 	return p.parseOptionalDotAccess(expr)
 }
@@ -429,40 +564,32 @@ func (p *Parser) parseOptionalBracketAccess(expr ast.Expression) (ast.Expression
 	if err := p.expect(lexer.RBRACK); err != nil {
 		return nil, err
 	}
-	return &ast.ChainExpression{
+	return &ast.MemberExpression{
 		BaseNode: ast.BaseNode{
-			NodeType: ast.NodeTypeChainExpression.String(),
-		},
-		Expression: &ast.MemberExpression{
-			BaseNode: ast.BaseNode{
-				NodeType: ast.NodeTypeMemberExpression.String(),
-			},
-			Object:   expr,
-			Property: property,
-			Computed: true,
-			Optional: true,
+			NodeType: ast.NodeTypeMemberExpression.String(),
 		},
+		Object:   expr,
+		Property: property,
+		Computed: true,
+		Optional: true,
 	}, nil
 }
 
-// parseOptionalCall parses obj?.()
-func (p *Parser) parseOptionalCall(expr ast.Expression) (ast.Expression, error) {
+// parseOptionalCall parses obj?.() and obj?.<T>()
+func (p *Parser) parseOptionalCall(expr ast.Expression, typeArgs *ast.TSTypeParameterInstantiation) (ast.Expression, error) {
 	args, err := p.parseArguments()
 	if err != nil {
 		return nil, err
 	}
-	return &ast.ChainExpression{
+	return &ast.CallExpression{
 		BaseNode: ast.BaseNode{
-			NodeType: ast.NodeTypeChainExpression.String(),
-		},
-		Expression: &ast.CallExpression{
-			BaseNode: ast.BaseNode{
-				NodeType: ast.NodeTypeCallExpression.String(),
-			},
-			Callee:    expr,
-			Arguments: args,
-			Optional:  true,
+			NodeType: ast.NodeTypeCallExpression.String(),
 		},
+		Callee:         expr,
+		Arguments:      args,
+		Optional:       true,
+		TypeArguments:  typeArgs,
+		TypeParameters: typeArgs,
 	}, nil
 }
 
@@ -479,25 +606,20 @@ func (p *Parser) parseOptionalDotAccess(expr ast.Expression) (ast.Expression, er
 		Name: p.current.Literal,
 	}
 	p.nextToken()
-	return &ast.ChainExpression{
+	return &ast.MemberExpression{
 		BaseNode: ast.BaseNode{
-			NodeType: ast.NodeTypeChainExpression.String(),
-		},
-		Expression: &ast.MemberExpression{
-			BaseNode: ast.BaseNode{
-				NodeType: ast.NodeTypeMemberExpression.String(),
-			},
-			Object:   expr,
-			Property: property,
-			Computed: false,
-			Optional: true,
+			NodeType: ast.NodeTypeMemberExpression.String(),
 		},
+		Object:   expr,
+		Property: property,
+		Computed: false,
+		Optional: true,
 	}, nil
 }
 
-// parseCallExpression parses function call: func()
-func (p *Parser) parseCallExpression(expr ast.Expression) (ast.Expression, error) {
-	p.nextToken()
+// parseCallExpression parses function call: func() or func<T>().
+// The opening '(' must already be consumed.
+func (p *Parser) parseCallExpression(expr ast.Expression, typeArgs *ast.TSTypeParameterInstantiation) (ast.Expression, error) {
 	args, err := p.parseArguments()
 	if err != nil {
 		return nil, err
@@ -506,13 +628,15 @@ func (p *Parser) parseCallExpression(expr ast.Expression) (ast.Expression, error
 		BaseNode: ast.BaseNode{
 			NodeType: ast.NodeTypeCallExpression.String(),
 		},
-		Callee:    expr,
-		Arguments: args,
+		Callee:         expr,
+		Arguments:      args,
+		TypeArguments:  typeArgs,
+		TypeParameters: typeArgs,
 	}, nil
 }
 
-// parseTaggedTemplate parses tagged template expression: tag`template`
-func (p *Parser) parseTaggedTemplate(expr ast.Expression) (ast.Expression, error) {
+// parseTaggedTemplate parses tagged template expression: tag`template` or tag<T>`template`
+func (p *Parser) parseTaggedTemplate(expr ast.Expression, typeArgs *ast.TSTypeParameterInstantiation) (ast.Expression, error) {
 	template, err := p.parseTemplateLiteral()
 	if err != nil {
 		return nil, err
@@ -521,8 +645,10 @@ func (p *Parser) parseTaggedTemplate(expr ast.Expression) (ast.Expression, error
 		BaseNode: ast.BaseNode{
 			NodeType: ast.NodeTypeTaggedTemplateExpression.String(),
 		},
-		Tag:   expr,
-		Quasi: template,
+		Tag:            expr,
+		Quasi:          template,
+		TypeArguments:  typeArgs,
+		TypeParameters: typeArgs,
 	}, nil
 }
 
@@ -561,31 +687,7 @@ func (p *Parser) parsePrimaryExpression() (ast.Expression, error) {
 		}, nil
 
 	case lexer.IDENT:
-		name := p.current.Literal
-		p.nextToken()
-		id := &ast.Identifier{
-			BaseNode: ast.BaseNode{
-				NodeType: ast.NodeTypeIdentifier.String(),
-				Range:    &ast.Range{start, p.current.Pos},
-			},
-			Name: name,
-		}
-
-		// Check for type arguments (TypeScript)
-		if p.current.Type == lexer.LSS {
-			typeArgs, err := p.parseTSTypeArguments()
-			if err == nil && typeArgs != nil {
-				return &ast.TSInstantiationExpression{
-					BaseNode: ast.BaseNode{
-						NodeType: ast.NodeTypeTSInstantiationExpression.String(),
-					},
-					Expression:     id,
-					TypeParameters: typeArgs,
-				}, nil
-			}
-		}
-
-		return id, nil
+		return p.parseIdentifierReference()
 
 	case lexer.NULL:
 		p.nextToken()
@@ -692,15 +794,41 @@ func (p *Parser) parsePrimaryExpression() (ast.Expression, error) {
 
 	case lexer.JSXTagStart, lexer.LSS:
 		if p.jsxEnabled {
-			return p.parseJSXElement()
+			return p.parseJSXElementOrFragment(false)
 		}
 		return nil, p.errorAtCurrent("unexpected token '<'")
 
 	default:
+		// Contextual keywords are identifiers in expressions, as in require("x").
+		if isContextualKeyword(p.current.Type) {
+			return p.parseIdentifierReference()
+		}
 		return nil, p.errorAtCurrent("unexpected token in expression")
 	}
 }
 
+// parseIdentifierReference parses an identifier in an expression, or an arrow
+// function if it is the function's single unparenthesized parameter.
+func (p *Parser) parseIdentifierReference() (ast.Expression, error) {
+	start := p.current.Pos
+	name := p.current.Literal
+	p.nextToken()
+	id := &ast.Identifier{
+		BaseNode: ast.BaseNode{
+			NodeType: ast.NodeTypeIdentifier.String(),
+			Range:    &ast.Range{start, p.current.Pos},
+		},
+		Name: name,
+	}
+
+	// Arrow function with a single unparenthesized parameter: x => x
+	if p.current.Type == lexer.ARROW {
+		return p.parseArrowFunctionFromIdentifier(start, id, false)
+	}
+
+	return id, nil
+}
+
 // parseArguments parses function call arguments.
 func (p *Parser) parseArguments() ([]ast.Expression, error) {
 	args := []ast.Expression{}
@@ -841,58 +969,25 @@ func (p *Parser) parseObjectExpression() (*ast.ObjectExpression, error) {
 func (p *Parser) parseProperty() (*ast.Property, error) {
 	start := p.current.Pos
 
-	// Check for getter/setter
+	// get, set and async are modifiers only when a key follows them;
+	// otherwise they are the key itself, as in { get: 1 } or { async() {} }
 	kind := "init"
-	if p.current.Type == lexer.GET || p.current.Type == lexer.SET {
+	if p.match(lexer.GET, lexer.SET) && p.canFollowModifier() {
 		kind = p.current.Literal
 		p.nextToken()
 	}
-
-	// Check for async method
-	async := p.consume(lexer.ASYNC)
-
-	// Check for generator method
+	async := kind == "init" && p.consumeModifier(lexer.ASYNC)
 	generator := p.consume(lexer.MUL)
 
 	// Parse key
-	computed := false
-	var key ast.Expression
-	var err error
-
-	if p.consume(lexer.LBRACK) {
-		computed = true
-		key, err = p.parseAssignmentExpression()
-		if err != nil {
-			return nil, err
-		}
-		if err := p.expect(lexer.RBRACK); err != nil {
-			return nil, err
-		}
-	} else if p.current.Type == lexer.IDENT {
-		key = &ast.Identifier{
-			BaseNode: ast.BaseNode{
-				NodeType: ast.NodeTypeIdentifier.String(),
-				Range:    &ast.Range{p.current.Pos, p.current.End},
-			},
-			Name: p.current.Literal,
-		}
-		p.nextToken()
-	} else if p.current.Type == lexer.STRING || p.current.Type == lexer.NUMBER {
-		key = &ast.Literal{
-			BaseNode: ast.BaseNode{
-				NodeType: ast.NodeTypeLiteral.String(),
-				Range:    &ast.Range{p.current.Pos, p.current.End},
-			},
-			Value: p.current.Literal,
-			Raw:   p.current.Literal,
-		}
-		p.nextToken()
-	} else {
-		return nil, p.errorAtCurrent("expected property key")
+	computed := p.current.Type == lexer.LBRACK
+	key, err := p.parsePropertyKey()
+	if err != nil {
+		return nil, err
 	}
 
 	// Check for shorthand property
-	if !computed && p.match(lexer.COMMA, lexer.RBRACE) {
+	if !computed && kind == "init" && !async && !generator && p.match(lexer.COMMA, lexer.RBRACE) {
 		if id, ok := key.(*ast.Identifier); ok {
 			return &ast.Property{
 				BaseNode: ast.BaseNode{
@@ -909,21 +1004,14 @@ func (p *Parser) parseProperty() (*ast.Property, error) {
 		}
 	}
 
-	// Parse type annotation if present (TypeScript)
-	_ = p.consume(lexer.QUESTION) // Optional property marker
-	if p.consume(lexer.COLON) && p.current.Type != lexer.COLON {
-		// Could be type annotation - try to parse it
-		_, _ = p.tryParseTSTypeAnnotation() //nolint:errcheck // Type annotation is optional, errors can be ignored
-	}
-
 	// Check for method
 	method := false
 	var value ast.Expression
 
-	if p.consume(lexer.LPAREN) || async || generator {
-		// Method
-		method = true
-		value, err = p.parseFunctionExpressionBody(async, generator)
+	if p.match(lexer.LPAREN, lexer.LSS) || kind != "init" || async || generator {
+		// Method, getter or setter
+		method = kind == "init"
+		value, err = p.parseFunctionExpressionBody(async, generator, false)
 		if err != nil {
 			return nil, err
 		}
@@ -952,6 +1040,38 @@ func (p *Parser) parseProperty() (*ast.Property, error) {
 	}, nil
 }
 
+// parsePropertyKey parses the key of an object property or class member:
+// an identifier name (reserved words included), a string or numeric literal,
+// or a computed key [expr].
+func (p *Parser) parsePropertyKey() (ast.Expression, error) {
+	switch {
+	case p.consume(lexer.LBRACK):
+		key, err := p.parseAssignmentExpression()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(lexer.RBRACK); err != nil {
+			return nil, err
+		}
+		return key, nil
+	case p.current.Type == lexer.STRING || p.current.Type == lexer.NUMBER:
+		key := &ast.Literal{
+			BaseNode: ast.BaseNode{
+				NodeType: ast.NodeTypeLiteral.String(),
+				Range:    &ast.Range{p.current.Pos, p.current.End},
+			},
+			Value: p.current.Literal,
+			Raw:   p.current.Literal,
+		}
+		p.nextToken()
+		return key, nil
+	case isIdentifierName(p.current.Type):
+		return p.parseIdentifierName()
+	default:
+		return nil, p.errorAtCurrent("expected property key")
+	}
+}
+
 // parseNewExpression parses a new expression.
 func (p *Parser) parseNewExpression() (*ast.NewExpression, error) {
 	start := p.current.Pos
@@ -967,11 +1087,16 @@ func (p *Parser) parseNewExpression() (*ast.NewExpression, error) {
 		return nil, p.errorAtCurrent("new.target not yet implemented")
 	}
 
-	callee, err := p.parseMemberOrCallExpression()
+	callee, err := p.parseNewCallee()
 	if err != nil {
 		return nil, err
 	}
 
+	var typeArgs *ast.TSTypeParameterInstantiation
+	if p.current.Type == lexer.LSS {
+		typeArgs = p.tryParseTypeArgumentsInExpression()
+	}
+
 	var args []ast.Expression
 	if p.consume(lexer.LPAREN) {
 		args, err = p.parseArguments()
@@ -985,11 +1110,36 @@ func (p *Parser) parseNewExpression() (*ast.NewExpression, error) {
 			NodeType: ast.NodeTypeNewExpression.String(),
 			Range:    &ast.Range{start, p.current.Pos},
 		},
-		Callee:    callee,
-		Arguments: args,
+		Callee:         callee,
+		Arguments:      args,
+		TypeArguments:  typeArgs,
+		TypeParameters: typeArgs,
 	}, nil
 }
 
+// parseNewCallee parses the callee of a new expression. Call suffixes are not
+// part of the callee, so the argument list binds to the new expression itself.
+func (p *Parser) parseNewCallee() (ast.Expression, error) {
+	expr, err := p.parsePrimaryExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch p.current.Type {
+		case lexer.PERIOD:
+			expr, err = p.parseDotMemberAccess(expr)
+		case lexer.LBRACK:
+			expr, err = p.parseBracketMemberAccess(expr)
+		default:
+			return expr, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
 // parseImportExpression parses a dynamic import() expression.
 func (p *Parser) parseImportExpression() (*ast.ImportExpression, error) {
 	start := p.current.Pos
@@ -1018,72 +1168,51 @@ func (p *Parser) parseImportExpression() (*ast.ImportExpression, error) {
 }
 
 // parseAsyncExpression parses an async function or arrow function.
+// When `async` is not followed by a function or arrow function head it is an
+// ordinary identifier, e.g. a call to a function named async.
 func (p *Parser) parseAsyncExpression() (ast.Expression, error) {
 	start := p.current.Pos
-	p.nextToken() // consume 'async'
 
-	if p.current.Type == lexer.FUNCTION {
+	if p.peek.Type == lexer.FUNCTION {
 		return p.parseFunctionExpression()
 	}
 
-	// Async arrow function
-	oldAllowAwait := p.allowAwait
-	p.allowAwait = true
-	defer func() { p.allowAwait = oldAllowAwait }()
+	asyncID := &ast.Identifier{
+		BaseNode: ast.BaseNode{
+			NodeType: ast.NodeTypeIdentifier.String(),
+			Range:    &ast.Range{p.current.Pos, p.current.End},
+		},
+		Name: p.current.Literal,
+	}
+	p.nextToken() // consume 'async'
 
-	// Parse parameters
-	var params []ast.Pattern
-	if p.consume(lexer.LPAREN) {
-		var err error
-		params, err = p.parseFunctionParams()
-		if err != nil {
-			return nil, err
-		}
-	} else if p.current.Type == lexer.IDENT {
-		params = []ast.Pattern{
-			&ast.Identifier{
-				BaseNode: ast.BaseNode{
-					NodeType: ast.NodeTypeIdentifier.String(),
-					Range:    &ast.Range{p.current.Pos, p.current.End},
-				},
-				Name: p.current.Literal,
+	// async x => ...
+	if p.current.Type == lexer.IDENT && p.peek.Type == lexer.ARROW {
+		param := &ast.Identifier{
+			BaseNode: ast.BaseNode{
+				NodeType: ast.NodeTypeIdentifier.String(),
+				Range:    &ast.Range{p.current.Pos, p.current.End},
 			},
+			Name: p.current.Literal,
 		}
 		p.nextToken()
-	} else {
-		return nil, p.errorAtCurrent("expected parameters for async arrow function")
-	}
-
-	if err := p.expect(lexer.ARROW); err != nil {
-		return nil, err
+		return p.parseArrowFunctionFromIdentifier(start, param, true)
 	}
 
-	// Parse body
-	var body ast.Node
-	var err error
-	if p.current.Type == lexer.LBRACE {
-		body, err = p.parseBlockStatement()
-	} else {
-		expr, err := p.parseAssignmentExpression()
-		if err != nil {
-			return nil, err
+	// async (x) => ... or async <T>(x: T) => ...
+	if p.match(lexer.LPAREN, lexer.LSS) {
+		arrow, ok, err := p.tryParseArrowFunction(start, true)
+		if ok {
+			return arrow, err
 		}
-		body = expr
 	}
 
-	if err != nil {
-		return nil, err
+	// async => ... uses async as a parameter name
+	if p.current.Type == lexer.ARROW {
+		return p.parseArrowFunctionFromIdentifier(start, asyncID, false)
 	}
 
-	return &ast.ArrowFunctionExpression{
-		BaseNode: ast.BaseNode{
-			NodeType: ast.NodeTypeArrowFunctionExpression.String(),
-			Range:    &ast.Range{start, p.current.Pos},
-		},
-		Params: params,
-		Body:   body,
-		Async:  true,
-	}, nil
+	return asyncID, nil
 }
 
 // parseYieldExpression parses a yield expression.
@@ -1133,3 +1262,25 @@ func isAssignmentOp(typ lexer.TokenType) bool {
 func isLogicalOp(typ lexer.TokenType) bool {
 	return typ == lexer.LAND || typ == lexer.LOR || typ == lexer.NULLISH
 }
+
+// isBinaryOperator reports whether typ is a binary operator, excluding the
+// comma, assignment and conditional operators.
+func isBinaryOperator(typ lexer.TokenType) bool {
+	switch typ {
+	case lexer.AS, lexer.SATISFIES:
+		return true
+	}
+	return precedence(typ) >= precedenceNullishCoalescing
+}
+
+// isContextualKeyword reports whether typ is a keyword that is only reserved
+// in some contexts and can otherwise be used as an identifier.
+func isContextualKeyword(typ lexer.TokenType) bool {
+	return typ >= lexer.AS && typ <= lexer.UNDEFINED
+}
+
+// isIdentifierName reports whether typ can be used as an IdentifierName, such
+// as a property name or a module export name. Reserved words are allowed.
+func isIdentifierName(typ lexer.TokenType) bool {
+	return typ == lexer.IDENT || (typ >= lexer.BREAK && typ <= lexer.UNDEFINED)
+}
@@ -1,8 +1,8 @@
 package parser
 
 import (
-	"github.com/kdy1/go-typescript-eslint/internal/ast"
 	"github.com/kdy1/go-typescript-eslint/internal/lexer"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 )
 
 // parseFunctionDeclaration parses a function declaration.
@@ -57,8 +57,8 @@ func (p *Parser) parseOptionalIdentifier() *ast.Identifier {
 // parseOptionalTypeParameters parses optional TypeScript type parameters.
 func (p *Parser) parseOptionalTypeParameters() *ast.TSTypeParameterDeclaration {
 	if p.current.Type == lexer.LSS {
-		typeParameters, err := p.parseTSTypeParameters()
-		if err != nil {
+		typeParameters, ok := tryParse(p, p.parseTSTypeParameters)
+		if !ok {
 			// Not type parameters, backtrack
 			return nil
 		}
@@ -187,18 +187,13 @@ func (p *Parser) parseFunctionParamsAndBody(async, generator bool) ([]ast.Patter
 }
 
 // parseFunctionExpressionBody parses the body of a function expression (used for object methods).
-func (p *Parser) parseFunctionExpressionBody(async, generator bool) (*ast.FunctionExpression, error) {
+// With allowEmptyBody, as for class methods, a signature without a body, such as an overload,
+// yields a TSEmptyBodyFunctionExpression with a nil Body.
+func (p *Parser) parseFunctionExpressionBody(async, generator, allowEmptyBody bool) (*ast.FunctionExpression, error) {
 	start := p.current.Pos
 
 	// Parse type parameters (TypeScript)
-	var typeParameters *ast.TSTypeParameterDeclaration
-	if p.current.Type == lexer.LSS {
-		var err error
-		typeParameters, err = p.parseTSTypeParameters()
-		if err != nil {
-			typeParameters = nil
-		}
-	}
+	typeParameters := p.parseOptionalTypeParameters()
 
 	// Parse parameters
 	if err := p.expect(lexer.LPAREN); err != nil {
@@ -227,9 +222,16 @@ func (p *Parser) parseFunctionExpressionBody(async, generator bool) (*ast.Functi
 	}
 
 	// Parse body
-	body, err := p.parseBlockStatement()
-	if err != nil {
-		return nil, err
+	nodeType := ast.NodeTypeFunctionExpression
+	var body *ast.BlockStatement
+	if allowEmptyBody && p.current.Type != lexer.LBRACE {
+		nodeType = ast.NodeTypeTSEmptyBodyFunctionExpression
+		p.consume(lexer.SEMICOLON)
+	} else {
+		body, err = p.parseBlockStatement()
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	p.inFunction = oldInFunction
@@ -238,7 +240,7 @@ func (p *Parser) parseFunctionExpressionBody(async, generator bool) (*ast.Functi
 
 	return &ast.FunctionExpression{
 		BaseNode: ast.BaseNode{
-			NodeType: ast.NodeTypeFunctionExpression.String(),
+			NodeType: nodeType.String(),
 			Range:    &ast.Range{start, p.current.Pos},
 		},
 		Params:         params,
@@ -300,13 +302,16 @@ func (p *Parser) parseRestParameter() (ast.Pattern, error) {
 
 // parseSingleFunctionParam parses a single function parameter with optional type and default value.
 func (p *Parser) parseSingleFunctionParam() (ast.Pattern, error) {
-	param, err := p.parseBindingPattern()
+	param, err := p.parseParamBindingPattern()
 	if err != nil {
 		return nil, err
 	}
 
 	// Parse type annotation (TypeScript)
-	param = p.parseParamTypeAnnotation(param)
+	param, err = p.parseParamTypeAnnotation(param)
+	if err != nil {
+		return nil, err
+	}
 
 	// Parse default value
 	if p.consume(lexer.ASSIGN) {
@@ -326,53 +331,122 @@ func (p *Parser) parseSingleFunctionParam() (ast.Pattern, error) {
 	return param, nil
 }
 
+// parseParamBindingPattern parses the binding of a function parameter.
+// Besides ordinary binding patterns, TypeScript allows an explicit this
+// parameter, which is represented as an Identifier named "this".
+func (p *Parser) parseParamBindingPattern() (ast.Pattern, error) {
+	if p.current.Type != lexer.THIS {
+		return p.parseBindingPattern()
+	}
+
+	start := p.current.Pos
+	p.nextToken()
+	return &ast.Identifier{
+		BaseNode: ast.BaseNode{
+			NodeType: ast.NodeTypeIdentifier.String(),
+			Range:    &ast.Range{start, p.current.Pos},
+		},
+		Name: "this",
+	}, nil
+}
+
 // parseParamTypeAnnotation parses optional type annotation for a parameter.
-func (p *Parser) parseParamTypeAnnotation(param ast.Pattern) ast.Pattern {
+func (p *Parser) parseParamTypeAnnotation(param ast.Pattern) (ast.Pattern, error) {
 	if id, ok := param.(*ast.Identifier); ok {
 		if p.consume(lexer.QUESTION) {
 			id.Optional = true
 		}
 		if p.consume(lexer.COLON) {
 			typeAnnotation, err := p.parseTSTypeAnnotation()
-			if err == nil {
-				id.TypeAnnotation = typeAnnotation
+			if err != nil {
+				return nil, err
 			}
+			id.TypeAnnotation = typeAnnotation
 		}
 	}
-	return param
+	return param, nil
 }
 
-// parseParenthesizedOrArrowFunction parses a parenthesized expression or arrow function.
-func (p *Parser) parseParenthesizedOrArrowFunction() (ast.Expression, error) {
-	start := p.current.Pos
-	p.nextToken() // consume '('
+// arrowFunctionHead holds the part of an arrow function before the body.
+type arrowFunctionHead struct {
+	typeParameters *ast.TSTypeParameterDeclaration
+	params         []ast.Pattern
+	returnType     *ast.TSTypeAnnotation
+}
 
-	// Empty parameter list for arrow function
-	if p.match(lexer.RPAREN) {
-		p.nextToken()
-		if p.current.Type == lexer.ARROW {
-			return p.parseArrowFunctionFromParams(start, []ast.Pattern{})
+// parseArrowFunctionHead parses `<T>(params): ReturnType =>` up to, but not
+// including, the arrow. It fails unless the arrow follows, which makes it
+// suitable for speculative parsing with tryParse.
+func (p *Parser) parseArrowFunctionHead() (*arrowFunctionHead, error) {
+	head := &arrowFunctionHead{}
+
+	if p.current.Type == lexer.LSS {
+		typeParameters, err := p.parseTSTypeParameters()
+		if err != nil {
+			return nil, err
 		}
-		return nil, p.errorAtCurrent("unexpected empty parentheses")
+		head.typeParameters = typeParameters
 	}
 
-	// Try to parse as parameters or expression
-	// This is ambiguous - could be (x) => x or (x + 1)
-
-	// Save state for potential backtracking
-	savedPos := p.current.Pos
+	if err := p.expect(lexer.LPAREN); err != nil {
+		return nil, err
+	}
 
-	// Try parsing as parameters first
 	params, err := p.parseFunctionParams()
-	if err == nil && p.current.Type == lexer.ARROW {
-		return p.parseArrowFunctionFromParams(start, params)
+	if err != nil {
+		return nil, err
 	}
+	head.params = params
 
-	// Not arrow function, parse as parenthesized expression
-	// Backtrack
-	p.current.Pos = savedPos
+	if p.consume(lexer.COLON) {
+		head.returnType, err = p.parseTSTypeAnnotation()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.current.Type != lexer.ARROW {
+		return nil, p.errorAtCurrent("expected '=>'")
+	}
+
+	return head, nil
+}
+
+// tryParseArrowFunction attempts to parse an arrow function with a
+// parenthesized parameter list. If the input is not an arrow function head,
+// or is one with a return type in the true branch of a conditional
+// expression that no ':' follows, the parser is rewound and ok is false.
+func (p *Parser) tryParseArrowFunction(start int, async bool) (*ast.ArrowFunctionExpression, bool, error) {
+	headAt := p.current.Pos
+	if p.notArrowHeadAt[headAt] {
+		return nil, false, nil
+	}
+
+	state := p.mark()
+	head, ok := tryParse(p, p.parseArrowFunctionHead)
+	if !ok {
+		p.notArrowHeadAt[headAt] = true
+		return nil, false, nil
+	}
+	arrow, err := p.parseArrowFunctionBody(start, head, async)
+	if start == p.noReturnTypeArrowAt && head.returnType != nil && (err != nil || p.current.Type != lexer.COLON) {
+		p.rewind(state)
+		return nil, false, nil
+	}
+	return arrow, true, err
+}
+
+// parseParenthesizedOrArrowFunction parses a parenthesized expression or arrow function.
+func (p *Parser) parseParenthesizedOrArrowFunction() (ast.Expression, error) {
+	start := p.current.Pos
+
+	arrow, ok, err := p.tryParseArrowFunction(start, false)
+	if ok {
+		return arrow, err
+	}
+
+	p.nextToken() // consume '('
 
-	// Skip opening paren (already consumed)
 	expr, err := p.parseExpression()
 	if err != nil {
 		return nil, err
@@ -382,47 +456,48 @@ func (p *Parser) parseParenthesizedOrArrowFunction() (ast.Expression, error) {
 		return nil, err
 	}
 
-	// Check if it's actually an arrow function
-	if p.current.Type == lexer.ARROW {
-		// Convert expression to params
-		// This is a simplified version - in reality, we'd need more sophisticated conversion
-		if id, ok := expr.(*ast.Identifier); ok {
-			return p.parseArrowFunctionFromParams(start, []ast.Pattern{id})
-		}
-		return nil, p.errorAtCurrent("invalid arrow function parameters")
-	}
-
 	return expr, nil
 }
 
-// parseArrowFunctionFromParams parses an arrow function given the parameters.
-func (p *Parser) parseArrowFunctionFromParams(start int, params []ast.Pattern) (*ast.ArrowFunctionExpression, error) {
+// parseArrowFunctionFromIdentifier parses an arrow function with a single
+// unparenthesized parameter: x => x.
+func (p *Parser) parseArrowFunctionFromIdentifier(start int, param *ast.Identifier, async bool) (*ast.ArrowFunctionExpression, error) {
+	return p.parseArrowFunctionBody(start, &arrowFunctionHead{params: []ast.Pattern{param}}, async)
+}
+
+// parseArrowFunctionBody parses the arrow and the body of an arrow function.
+func (p *Parser) parseArrowFunctionBody(start int, head *arrowFunctionHead, async bool) (*ast.ArrowFunctionExpression, error) {
 	if err := p.expect(lexer.ARROW); err != nil {
 		return nil, err
 	}
 
-	// Parse body
-	var body ast.Node
-	var err error
-
 	oldInFunction := p.inFunction
+	oldAllowYield := p.allowYield
+	oldAllowAwait := p.allowAwait
 	p.inFunction = true
+	p.allowYield = false
+	p.allowAwait = async
+	defer func() {
+		p.inFunction = oldInFunction
+		p.allowYield = oldAllowYield
+		p.allowAwait = oldAllowAwait
+	}()
 
+	var body ast.Node
+	expression := false
 	if p.current.Type == lexer.LBRACE {
-		body, err = p.parseBlockStatement()
+		block, err := p.parseBlockStatement()
+		if err != nil {
+			return nil, err
+		}
+		body = block
 	} else {
-		// Expression body
 		expr, err := p.parseAssignmentExpression()
 		if err != nil {
 			return nil, err
 		}
 		body = expr
-	}
-
-	p.inFunction = oldInFunction
-
-	if err != nil {
-		return nil, err
+		expression = true
 	}
 
 	return &ast.ArrowFunctionExpression{
@@ -430,9 +505,12 @@ func (p *Parser) parseArrowFunctionFromParams(start int, params []ast.Pattern) (
 			NodeType: ast.NodeTypeArrowFunctionExpression.String(),
 			Range:    &ast.Range{start, p.current.Pos},
 		},
-		Params: params,
-		Body:   body,
-		Async:  false,
+		Params:         head.params,
+		Body:           body,
+		Async:          async,
+		Expression:     expression,
+		TypeParameters: head.typeParameters,
+		ReturnType:     head.returnType,
 	}, nil
 }
 
@@ -452,10 +530,7 @@ func (p *Parser) parseClassHeritage() (ast.Expression, *ast.TSTypeParameterInsta
 
 		// Parse type arguments for super class (TypeScript)
 		if p.current.Type == lexer.LSS {
-			superTypeParameters, err = p.parseTSTypeArguments()
-			if err != nil {
-				superTypeParameters = nil
-			}
+			superTypeParameters, _ = tryParse(p, p.parseTSTypeArguments)
 		}
 	}
 
@@ -496,14 +571,7 @@ func (p *Parser) parseClassDeclaration() (*ast.ClassDeclaration, error) {
 	}
 
 	// Parse type parameters (TypeScript)
-	var typeParameters *ast.TSTypeParameterDeclaration
-	if p.current.Type == lexer.LSS {
-		var err error
-		typeParameters, err = p.parseTSTypeParameters()
-		if err != nil {
-			typeParameters = nil
-		}
-	}
+	typeParameters := p.parseOptionalTypeParameters()
 
 	// Parse heritage clauses
 	superClass, superTypeParameters, implements, err := p.parseClassHeritage()
@@ -526,6 +594,7 @@ func (p *Parser) parseClassDeclaration() (*ast.ClassDeclaration, error) {
 		SuperClass:          superClass,
 		Body:                body,
 		TypeParameters:      typeParameters,
+		SuperTypeArguments:  superTypeParameters,
 		SuperTypeParameters: superTypeParameters,
 		Implements:          implements,
 	}, nil
@@ -550,14 +619,7 @@ func (p *Parser) parseClassExpression() (*ast.ClassExpression, error) {
 	}
 
 	// Parse type parameters (TypeScript)
-	var typeParameters *ast.TSTypeParameterDeclaration
-	if p.current.Type == lexer.LSS {
-		var err error
-		typeParameters, err = p.parseTSTypeParameters()
-		if err != nil {
-			typeParameters = nil
-		}
-	}
+	typeParameters := p.parseOptionalTypeParameters()
 
 	// Parse heritage clauses
 	superClass, superTypeParameters, implements, err := p.parseClassHeritage()
@@ -580,6 +642,7 @@ func (p *Parser) parseClassExpression() (*ast.ClassExpression, error) {
 		SuperClass:          superClass,
 		Body:                body,
 		TypeParameters:      typeParameters,
+		SuperTypeArguments:  superTypeParameters,
 		SuperTypeParameters: superTypeParameters,
 		Implements:          implements,
 	}, nil
@@ -650,92 +713,51 @@ func (p *Parser) parseClassElement() (ast.Node, error) {
 	}
 
 	// Parse modifiers
-	isStatic := p.consume(lexer.STATIC)
-	_ = false // isAbstract - not used in PropertyDefinition
-	isReadonly := false
-	isDeclare := false
+	isStatic := p.consumeModifier(lexer.STATIC)
+	isDeclare := p.consumeModifier(lexer.DECLARE)
 
-	if p.current.Type == lexer.READONLY {
-		isReadonly = true
+	var accessibility *string
+	if p.match(lexer.PUBLIC, lexer.PRIVATE, lexer.PROTECTED) && p.canFollowModifier() {
+		value := p.current.Literal
+		accessibility = &value
 		p.nextToken()
 	}
 
-	// Check for async/generator
-	async := p.consume(lexer.ASYNC)
-	generator := false
-	if async && p.consume(lexer.MUL) {
-		generator = true
-	} else if p.consume(lexer.MUL) {
-		generator = true
+	if !isStatic {
+		isStatic = p.consumeModifier(lexer.STATIC)
 	}
+	isReadonly := p.consumeModifier(lexer.READONLY)
+
+	// Check for async/generator
+	async := p.consumeModifier(lexer.ASYNC)
+	generator := p.consume(lexer.MUL)
 
 	// Parse accessor type (get/set)
 	kind := "method"
-	switch p.current.Type {
-	case lexer.GET:
-		kind = "get"
-		p.nextToken()
-	case lexer.SET:
-		kind = "set"
+	if !async && !generator && p.match(lexer.GET, lexer.SET) && p.canFollowModifier() {
+		kind = p.current.Literal
 		p.nextToken()
-	case lexer.CONSTRUCTOR:
-		kind = "constructor"
 	}
 
 	// Parse key
-	computed := false
-	var key ast.Expression
-	var err error
-
-	if p.consume(lexer.LBRACK) {
-		computed = true
-		key, err = p.parseAssignmentExpression()
-		if err != nil {
-			return nil, err
-		}
-		if err := p.expect(lexer.RBRACK); err != nil {
-			return nil, err
-		}
-	} else if p.current.Type == lexer.IDENT {
-		key = &ast.Identifier{
-			BaseNode: ast.BaseNode{
-				NodeType: ast.NodeTypeIdentifier.String(),
-				Range:    &ast.Range{p.current.Pos, p.current.End},
-			},
-			Name: p.current.Literal,
-		}
-		p.nextToken()
-	} else if p.current.Type == lexer.STRING || p.current.Type == lexer.NUMBER {
-		key = &ast.Literal{
-			BaseNode: ast.BaseNode{
-				NodeType: ast.NodeTypeLiteral.String(),
-				Range:    &ast.Range{p.current.Pos, p.current.End},
-			},
-			Value: p.current.Literal,
-			Raw:   p.current.Literal,
-		}
-		p.nextToken()
-	} else {
+	computed := p.current.Type == lexer.LBRACK
+	key, err := p.parsePropertyKey()
+	if err != nil {
 		return nil, p.errorAtCurrent("expected class member key")
 	}
 
-	// Check for property vs method
-	optional := p.consume(lexer.QUESTION)
-
-	// Try to parse type annotation
-	var typeAnnotation *ast.TSTypeAnnotation
-	if p.current.Type == lexer.COLON {
-		p.nextToken()
-		typeAnnotation, err = p.parseTSTypeAnnotation()
-		if err != nil {
-			return nil, err
-		}
+	if id, ok := key.(*ast.Identifier); ok && id.Name == "constructor" && kind == "method" && !isStatic {
+		kind = "constructor"
 	}
 
+	// Check for optional (foo?) or definite (foo!) members
+	optional := p.consume(lexer.QUESTION)
+	definite := !optional && p.consume(lexer.NOT)
+
 	// Check if it's a method (has parameters)
-	if p.current.Type == lexer.LPAREN || kind == "get" || kind == "set" || kind == "constructor" || generator {
+	if p.match(lexer.LPAREN, lexer.LSS) || kind != "method" || generator {
 		// Method
-		value, err := p.parseFunctionExpressionBody(async, generator)
+		value, err := p.parseFunctionExpressionBody(async, generator, true)
 		if err != nil {
 			return nil, err
 		}
@@ -745,14 +767,25 @@ func (p *Parser) parseClassElement() (ast.Node, error) {
 				NodeType: ast.NodeTypeMethodDefinition.String(),
 				Range:    &ast.Range{start, p.current.Pos},
 			},
-			Key:      key,
-			Value:    value,
-			Kind:     kind,
-			Computed: computed,
-			Static:   isStatic,
+			Key:           key,
+			Value:         value,
+			Kind:          kind,
+			Computed:      computed,
+			Static:        isStatic,
+			Optional:      optional,
+			Accessibility: accessibility,
 		}, nil
 	}
 
+	// Try to parse type annotation
+	var typeAnnotation *ast.TSTypeAnnotation
+	if p.consume(lexer.COLON) {
+		typeAnnotation, err = p.parseTSTypeAnnotation()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Property
 	var value ast.Expression
 	if p.consume(lexer.ASSIGN) {
@@ -775,7 +808,31 @@ func (p *Parser) parseClassElement() (ast.Node, error) {
 		Static:         isStatic,
 		TypeAnnotation: typeAnnotation,
 		Optional:       optional,
+		Definite:       definite,
 		Readonly:       isReadonly,
 		Declare:        isDeclare,
+		Accessibility:  accessibility,
 	}, nil
 }
+
+// consumeModifier consumes a member modifier of the given type.
+// A modifier keyword that is itself the member name, as in static() {} or
+// readonly: boolean, is left in place.
+func (p *Parser) consumeModifier(typ lexer.TokenType) bool {
+	if p.current.Type != typ || !p.canFollowModifier() {
+		return false
+	}
+	p.nextToken()
+	return true
+}
+
+// canFollowModifier reports whether the current token is followed by something
+// that can continue a class or type member, so that it acts as a modifier
+// rather than as the member name.
+func (p *Parser) canFollowModifier() bool {
+	switch p.peek.Type {
+	case lexer.LBRACK, lexer.LBRACE, lexer.MUL, lexer.STRING, lexer.NUMBER:
+		return true
+	}
+	return isIdentifierName(p.peek.Type)
+}
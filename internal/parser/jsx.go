@@ -1,16 +1,39 @@
 package parser
 
 import (
-	"github.com/kdy1/go-typescript-eslint/internal/ast"
+	"html"
+	"strconv"
+	"strings"
+
 	"github.com/kdy1/go-typescript-eslint/internal/lexer"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 )
 
+// expectJSX consumes a token of the given type. If JSX children follow it,
+// the next token is rescanned as JSX text first.
+func (p *Parser) expectJSX(typ lexer.TokenType, childrenFollow bool) error {
+	if childrenFollow && p.current.Type == typ {
+		p.reScanPeek(p.scanner.ScanJSXText)
+	}
+	return p.expect(typ)
+}
+
+// parseJSXElementOrFragment parses a JSX element or, if the '<' is directly
+// followed by '>', a JSX fragment. inChildren reports whether it appears among
+// the children of another element, so that the text after it is scanned as JSX.
+func (p *Parser) parseJSXElementOrFragment(inChildren bool) (ast.Expression, error) {
+	if p.peek.Type == lexer.GTR {
+		return p.parseJSXFragment(inChildren)
+	}
+	return p.parseJSXElement(inChildren)
+}
+
 // parseJSXElement parses a JSX element <div>...</div>.
-func (p *Parser) parseJSXElement() (*ast.JSXElement, error) {
+func (p *Parser) parseJSXElement(inChildren bool) (*ast.JSXElement, error) {
 	start := p.current.Pos
 
 	// Parse opening element
-	opening, err := p.parseJSXOpeningElement()
+	opening, err := p.parseJSXOpeningElement(inChildren)
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +69,7 @@ func (p *Parser) parseJSXElement() (*ast.JSXElement, error) {
 	}
 
 	// Parse closing element
-	closing, err := p.parseJSXClosingElement()
+	closing, err := p.parseJSXClosingElement(inChildren)
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +86,7 @@ func (p *Parser) parseJSXElement() (*ast.JSXElement, error) {
 }
 
 // parseJSXOpeningElement parses a JSX opening element <div attr="value">.
-func (p *Parser) parseJSXOpeningElement() (*ast.JSXOpeningElement, error) {
+func (p *Parser) parseJSXOpeningElement(inChildren bool) (*ast.JSXOpeningElement, error) {
 	start := p.current.Pos
 	p.nextToken() // consume '<'
 
@@ -76,16 +99,12 @@ func (p *Parser) parseJSXOpeningElement() (*ast.JSXOpeningElement, error) {
 	// Parse type parameters (TypeScript)
 	var typeParameters *ast.TSTypeParameterInstantiation
 	if p.current.Type == lexer.LSS {
-		typeParameters, err = p.parseTSTypeArguments()
-		if err != nil {
-			// Not type parameters, ignore
-			typeParameters = nil
-		}
+		typeParameters, _ = tryParse(p, p.parseTSTypeArguments)
 	}
 
 	// Parse attributes
 	attributes := []interface{}{}
-	for !p.match(lexer.GTR, lexer.JSXSelfClosingEnd) && !p.isAtEnd() {
+	for !p.match(lexer.GTR, lexer.QUO, lexer.JSXSelfClosingEnd) && !p.isAtEnd() {
 		attr, err := p.parseJSXAttribute()
 		if err != nil {
 			return nil, err
@@ -93,11 +112,13 @@ func (p *Parser) parseJSXOpeningElement() (*ast.JSXOpeningElement, error) {
 		attributes = append(attributes, attr)
 	}
 
+	// Children follow the '>' of an element that is not self-closing
 	selfClosing := false
 	if p.consume(lexer.JSXSelfClosingEnd) {
 		selfClosing = true
 	} else {
-		if err := p.expect(lexer.GTR); err != nil {
+		selfClosing = p.consume(lexer.QUO)
+		if err := p.expectJSX(lexer.GTR, inChildren || !selfClosing); err != nil {
 			return nil, err
 		}
 	}
@@ -110,12 +131,13 @@ func (p *Parser) parseJSXOpeningElement() (*ast.JSXOpeningElement, error) {
 		Name:           name,
 		Attributes:     attributes,
 		SelfClosing:    selfClosing,
+		TypeArguments:  typeParameters,
 		TypeParameters: typeParameters,
 	}, nil
 }
 
 // parseJSXClosingElement parses a JSX closing element </div>.
-func (p *Parser) parseJSXClosingElement() (*ast.JSXClosingElement, error) {
+func (p *Parser) parseJSXClosingElement(inChildren bool) (*ast.JSXClosingElement, error) {
 	start := p.current.Pos
 	p.nextToken() // consume '<'
 
@@ -128,7 +150,7 @@ func (p *Parser) parseJSXClosingElement() (*ast.JSXClosingElement, error) {
 		return nil, err
 	}
 
-	if err := p.expect(lexer.GTR); err != nil {
+	if err := p.expectJSX(lexer.GTR, inChildren); err != nil {
 		return nil, err
 	}
 
@@ -280,7 +302,9 @@ func (p *Parser) parseJSXAttribute() (ast.Node, error) {
 
 	// Parse attribute value
 	var value ast.Node
-	if p.consume(lexer.ASSIGN) {
+	if p.current.Type == lexer.ASSIGN {
+		p.reScanPeek(p.scanner.ScanJSXAttributeValue)
+		p.nextToken()
 		var err error
 		value, err = p.parseJSXAttributeValue()
 		if err != nil {
@@ -301,7 +325,21 @@ func (p *Parser) parseJSXAttribute() (ast.Node, error) {
 // parseJSXAttributeValue parses a JSX attribute value.
 func (p *Parser) parseJSXAttributeValue() (ast.Node, error) {
 	switch p.current.Type {
-	case lexer.STRING, lexer.JSXAttributeString:
+	case lexer.JSXAttributeString:
+		// The literal is the raw source text, quotes included
+		raw := p.current.Literal
+		value := &ast.Literal{
+			BaseNode: ast.BaseNode{
+				NodeType: ast.NodeTypeLiteral.String(),
+				Range:    &ast.Range{p.current.Pos, p.current.End},
+			},
+			Value: decodeJSXEntities(raw[1 : len(raw)-1]),
+			Raw:   raw,
+		}
+		p.nextToken()
+		return value, nil
+
+	case lexer.STRING:
 		value := &ast.Literal{
 			BaseNode: ast.BaseNode{
 				NodeType: ast.NodeTypeLiteral.String(),
@@ -314,10 +352,10 @@ func (p *Parser) parseJSXAttributeValue() (ast.Node, error) {
 		return value, nil
 
 	case lexer.LBRACE:
-		return p.parseJSXExpressionContainer()
+		return p.parseJSXExpressionContainer(false)
 
 	case lexer.LSS:
-		return p.parseJSXElement()
+		return p.parseJSXElementOrFragment(false)
 
 	default:
 		return nil, p.errorAtCurrent("expected JSX attribute value")
@@ -325,13 +363,15 @@ func (p *Parser) parseJSXAttributeValue() (ast.Node, error) {
 }
 
 // parseJSXExpressionContainer parses a JSX expression container {expr}.
-func (p *Parser) parseJSXExpressionContainer() (*ast.JSXExpressionContainer, error) {
+func (p *Parser) parseJSXExpressionContainer(inChildren bool) (*ast.JSXExpressionContainer, error) {
 	start := p.current.Pos
 	p.nextToken() // consume '{'
 
 	// Check for empty expression
 	if p.match(lexer.RBRACE) {
-		p.nextToken()
+		if err := p.expectJSX(lexer.RBRACE, inChildren); err != nil {
+			return nil, err
+		}
 		return &ast.JSXExpressionContainer{
 			BaseNode: ast.BaseNode{
 				NodeType: ast.NodeTypeJSXExpressionContainer.String(),
@@ -352,7 +392,7 @@ func (p *Parser) parseJSXExpressionContainer() (*ast.JSXExpressionContainer, err
 			return nil, err
 		}
 
-		if err := p.expect(lexer.RBRACE); err != nil {
+		if err := p.expectJSX(lexer.RBRACE, inChildren); err != nil {
 			return nil, err
 		}
 
@@ -375,7 +415,7 @@ func (p *Parser) parseJSXExpressionContainer() (*ast.JSXExpressionContainer, err
 		return nil, err
 	}
 
-	if err := p.expect(lexer.RBRACE); err != nil {
+	if err := p.expectJSX(lexer.RBRACE, inChildren); err != nil {
 		return nil, err
 	}
 
@@ -392,14 +432,10 @@ func (p *Parser) parseJSXExpressionContainer() (*ast.JSXExpressionContainer, err
 func (p *Parser) parseJSXChild() (ast.Node, error) {
 	switch p.current.Type {
 	case lexer.LSS:
-		// Check for JSX fragment
-		if p.peek.Type == lexer.GTR {
-			return p.parseJSXFragment()
-		}
-		return p.parseJSXElement()
+		return p.parseJSXElementOrFragment(true)
 
 	case lexer.LBRACE:
-		return p.parseJSXExpressionContainer()
+		return p.parseJSXExpressionContainer(true)
 
 	case lexer.JSXText:
 		text := &ast.JSXText{
@@ -407,19 +443,19 @@ func (p *Parser) parseJSXChild() (ast.Node, error) {
 				NodeType: ast.NodeTypeJSXText.String(),
 				Range:    &ast.Range{p.current.Pos, p.current.End},
 			},
-			Value: p.current.Literal,
+			Value: decodeJSXEntities(p.current.Literal),
 			Raw:   p.current.Literal,
 		}
 		p.nextToken()
 		return text, nil
 
 	default:
-		return nil, nil
+		return nil, p.errorAtCurrent("unexpected token in JSX children")
 	}
 }
 
 // parseJSXFragment parses a JSX fragment <>...</>.
-func (p *Parser) parseJSXFragment() (*ast.JSXFragment, error) {
+func (p *Parser) parseJSXFragment(inChildren bool) (*ast.JSXFragment, error) {
 	start := p.current.Pos
 	p.nextToken() // consume '<'
 
@@ -430,7 +466,7 @@ func (p *Parser) parseJSXFragment() (*ast.JSXFragment, error) {
 		},
 	}
 
-	if err := p.expect(lexer.GTR); err != nil {
+	if err := p.expectJSX(lexer.GTR, true); err != nil {
 		return nil, err
 	}
 
@@ -465,7 +501,7 @@ func (p *Parser) parseJSXFragment() (*ast.JSXFragment, error) {
 		},
 	}
 
-	if err := p.expect(lexer.GTR); err != nil {
+	if err := p.expectJSX(lexer.GTR, inChildren); err != nil {
 		return nil, err
 	}
 
@@ -479,3 +515,83 @@ func (p *Parser) parseJSXFragment() (*ast.JSXFragment, error) {
 		Children:        children,
 	}, nil
 }
+
+// maxJSXEntityLength bounds how far past '&' an entity name is searched for
+// its ';'. The longest HTML5 entity name, CounterClockwiseContourIntegral, has
+// 31 characters.
+const maxJSXEntityLength = 32
+
+// decodeJSXEntities replaces HTML character references in JSX text and
+// attribute strings with the characters they denote. Named references use the
+// HTML5 entity table; numeric references may be decimal (&#8212;) or hex
+// (&#x2014;). Unlike HTML, JSX requires the terminating ';', and anything
+// that is not a valid reference is kept verbatim.
+func decodeJSXEntities(s string) string {
+	if !strings.Contains(s, "&") {
+		return s
+	}
+
+	var sb strings.Builder
+	for {
+		i := strings.IndexByte(s, '&')
+		if i < 0 {
+			sb.WriteString(s)
+			return sb.String()
+		}
+		sb.WriteString(s[:i])
+		s = s[i:]
+
+		end := strings.IndexByte(s, ';')
+		if end < 0 || end > maxJSXEntityLength {
+			sb.WriteByte('&')
+			s = s[1:]
+			continue
+		}
+
+		if decoded, ok := decodeJSXEntity(s[1:end]); ok {
+			sb.WriteString(decoded)
+			s = s[end+1:]
+		} else {
+			sb.WriteByte('&')
+			s = s[1:]
+		}
+	}
+}
+
+// decodeJSXEntity decodes the body of a single character reference, without
+// the surrounding '&' and ';'.
+func decodeJSXEntity(name string) (string, bool) {
+	if strings.HasPrefix(name, "#") {
+		digits, base := name[1:], 10
+		if strings.HasPrefix(digits, "x") || strings.HasPrefix(digits, "X") {
+			digits, base = digits[1:], 16
+		}
+		if digits == "" || strings.ContainsAny(digits, "+-_") {
+			return "", false
+		}
+		code, err := strconv.ParseUint(digits, base, 32)
+		if err != nil || code > 0x10FFFF {
+			return "", false
+		}
+		return string(rune(code)), true
+	}
+
+	for _, ch := range name {
+		if !(ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9') {
+			return "", false
+		}
+	}
+	if name == "" {
+		return "", false
+	}
+
+	// html.UnescapeString also accepts legacy entities without a ';', so
+	// "&ampx;" becomes "&x;". Only a full match consumes the ';' as well;
+	// &semi; is the one entity whose value itself ends in ';'.
+	ref := "&" + name + ";"
+	decoded := html.UnescapeString(ref)
+	if decoded == ref || (strings.HasSuffix(decoded, ";") && name != "semi") {
+		return "", false
+	}
+	return decoded, true
+}
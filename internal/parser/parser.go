@@ -2,9 +2,10 @@ package parser
 
 import (
 	"fmt"
+	"strings"
 
-	"github.com/kdy1/go-typescript-eslint/internal/ast"
 	"github.com/kdy1/go-typescript-eslint/internal/lexer"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 )
 
 // Parser represents a TypeScript parser that implements recursive descent parsing.
@@ -34,6 +35,40 @@ type Parser struct {
 	// All tokens and comments for the AST
 	allTokens   []lexer.Token
 	allComments []ast.Comment
+
+	// Scanner state and comment count from just before peek was scanned,
+	// used to rescan the input that follows the current token.
+	peekScanner      lexer.ScannerState
+	peekCommentCount int
+
+	// Position of the true branch of the innermost conditional expression
+	// being parsed, or -1. An arrow function starting there may only have a
+	// return type if a ':' follows it, as in tsc, so that in a ? (b) : c => d
+	// the ':' separates the branches.
+	noReturnTypeArrowAt int
+
+	// Offsets at which an arrow function head failed to parse. Whether one
+	// parses does not depend on where it is tried from, so, as in tsc, it
+	// is tried once per offset instead of again for every enclosing
+	// expression that is reparsed after its own attempt failed, which is
+	// exponential in the nesting depth of (a = (b = ...)).
+	notArrowHeadAt map[int]bool
+
+	// End of the furthest token scanned so far. Unlike the rest of the
+	// state it is not rewound after speculative parsing, since the input
+	// that was read still influenced the result.
+	furthest int
+}
+
+// scanLookahead bounds how many bytes past the end of a token the scanner
+// may examine before deciding where the token ends.
+const scanLookahead = 4
+
+// StatementSpan describes the source text a top-level statement was parsed from.
+type StatementSpan struct {
+	Start     int // offset of the statement's first token
+	End       int // offset of the token following the statement
+	Lookahead int // offset up to which input was read while parsing it
 }
 
 // ParseError represents a parsing error.
@@ -59,6 +94,9 @@ func New(source string) *Parser {
 		errors:     []ParseError{},
 		sourceType: "module", // Default to module
 		jsxEnabled: false,
+
+		noReturnTypeArrowAt: -1,
+		notArrowHeadAt:      map[int]bool{},
 	}
 
 	// Prime the parser with two tokens
@@ -68,6 +106,23 @@ func New(source string) *Parser {
 	return p
 }
 
+// Seek restarts the parser at byte offset pos of its source, discarding
+// everything read so far. The offset must be the start of a top-level
+// statement or of the whitespace before one, and line and column must give
+// its position, with the column counted in UTF-16 code units.
+func (p *Parser) Seek(pos, line, column int) {
+	p.scanner.Seek(pos, line, column)
+	p.allTokens = nil
+	p.allComments = nil
+	p.errors = []ParseError{}
+	p.peek = lexer.Token{}
+	p.furthest = pos
+	clear(p.notArrowHeadAt)
+
+	p.nextToken()
+	p.nextToken()
+}
+
 // SetSourceType sets the source type ("script" or "module").
 func (p *Parser) SetSourceType(sourceType string) {
 	p.sourceType = sourceType
@@ -87,21 +142,155 @@ func (p *Parser) SetStrictMode(strict bool) {
 // nextToken advances to the next token in the stream.
 func (p *Parser) nextToken() {
 	p.current = p.peek
-	p.peek = p.scanner.Scan()
+	p.scanPeek()
 
 	// Store all tokens for the AST
-	if p.current.Type != lexer.COMMENT {
+	if p.current.Type != lexer.EOF {
 		p.allTokens = append(p.allTokens, p.current)
-	} else {
-		// Store comments separately
-		p.allComments = append(p.allComments, ast.Comment{
-			Type:  "Line", // Will be refined based on comment content
-			Value: p.current.Literal,
-			Range: &ast.Range{p.current.Pos, p.current.End},
-		})
 	}
 }
 
+// scanPeek scans the token after current into peek.
+func (p *Parser) scanPeek() {
+	p.peekScanner = p.scanner.Save()
+	p.peekCommentCount = len(p.allComments)
+	p.peek = p.scanToken()
+	p.furthest = max(p.furthest, p.peek.End)
+}
+
+// reScanTemplateContinuation rescans the current '}' token as the start of a
+// TemplateMiddle or TemplateTail token. The scanner cannot tell on its own
+// whether a '}' closes a block or a template substitution, so the parser asks
+// for the rescan once it reaches the end of a substitution.
+func (p *Parser) reScanTemplateContinuation() {
+	if p.current.Type != lexer.RBRACE {
+		return
+	}
+
+	p.scanner.Restore(p.peekScanner)
+	p.allComments = p.allComments[:p.peekCommentCount]
+	p.current = p.scanner.ScanTemplateContinuation()
+	p.allTokens[len(p.allTokens)-1] = p.current
+	p.scanPeek()
+}
+
+// reScanPeek discards peek and scans the input after the current token again
+// with scan. JSX uses it where text or attribute strings follow a token, since
+// they are lexed differently from regular JavaScript.
+func (p *Parser) reScanPeek(scan func() lexer.Token) {
+	p.scanner.Restore(p.peekScanner)
+	p.allComments = p.allComments[:p.peekCommentCount]
+	p.peek = scan()
+	p.furthest = max(p.furthest, p.peek.End)
+}
+
+// scanToken returns the next significant token from the scanner.
+// Comments are collected into allComments and never reach the parser.
+func (p *Parser) scanToken() lexer.Token {
+	for {
+		tok := p.scanner.Scan()
+		if tok.Type != lexer.COMMENT {
+			return tok
+		}
+		p.allComments = append(p.allComments, newComment(tok))
+	}
+}
+
+// newComment converts a comment token into an ESTree comment.
+// The value excludes the comment delimiters.
+func newComment(tok lexer.Token) ast.Comment {
+	comment := ast.Comment{
+		Type:  "Line",
+		Value: strings.TrimPrefix(tok.Literal, "//"),
+		Range: &ast.Range{tok.Pos, tok.End},
+	}
+	if strings.HasPrefix(tok.Literal, "/*") {
+		comment.Type = "Block"
+		comment.Value = strings.TrimSuffix(strings.TrimPrefix(tok.Literal, "/*"), "*/")
+	}
+	return comment
+}
+
+// parserState is a snapshot of the parser used for speculative parsing.
+// Restoring it rewinds the scanner and discards any tokens, comments and
+// errors recorded after the snapshot was taken.
+type parserState struct {
+	scanner          lexer.ScannerState
+	peekScanner      lexer.ScannerState
+	current          lexer.Token
+	peek             lexer.Token
+	tokenCount       int
+	commentCount     int
+	peekCommentCount int
+	errorCount       int
+	inFunction       bool
+	inLoop           bool
+	inSwitch         bool
+	inClass          bool
+	allowYield       bool
+	allowAwait       bool
+}
+
+// mark captures the current parser state.
+func (p *Parser) mark() parserState {
+	return parserState{
+		scanner:          p.scanner.Save(),
+		peekScanner:      p.peekScanner,
+		peekCommentCount: p.peekCommentCount,
+		current:          p.current,
+		peek:             p.peek,
+		tokenCount:       len(p.allTokens),
+		commentCount:     len(p.allComments),
+		errorCount:       len(p.errors),
+		inFunction:       p.inFunction,
+		inLoop:           p.inLoop,
+		inSwitch:         p.inSwitch,
+		inClass:          p.inClass,
+		allowYield:       p.allowYield,
+		allowAwait:       p.allowAwait,
+	}
+}
+
+// rewind restores a state previously captured with mark.
+func (p *Parser) rewind(state parserState) {
+	p.scanner.Restore(state.scanner)
+	p.peekScanner = state.peekScanner
+	p.peekCommentCount = state.peekCommentCount
+	p.current = state.current
+	p.peek = state.peek
+	p.allTokens = p.allTokens[:state.tokenCount]
+	p.allComments = p.allComments[:state.commentCount]
+	p.errors = p.errors[:state.errorCount]
+	p.inFunction = state.inFunction
+	p.inLoop = state.inLoop
+	p.inSwitch = state.inSwitch
+	p.inClass = state.inClass
+	p.allowYield = state.allowYield
+	p.allowAwait = state.allowAwait
+}
+
+// lookahead runs fn and then rewinds the parser, regardless of the outcome.
+// It is used to answer questions like "is this the head of an arrow function?"
+// without consuming any input.
+func (p *Parser) lookahead(fn func() bool) bool {
+	state := p.mark()
+	defer p.rewind(state)
+	return fn()
+}
+
+// tryParse runs parse speculatively. If parse fails, the parser is rewound to
+// where it was before the call and ok is false; otherwise the consumed input is kept.
+func tryParse[T any](p *Parser, parse func() (T, error)) (result T, ok bool) {
+	state := p.mark()
+	result, err := parse()
+	if err != nil {
+		p.rewind(state)
+		var zero T
+		return zero, false
+	}
+	return result, true
+}
+
 // expect checks if the current token is of the expected type and advances.
 // Returns an error if the token doesn't match.
 func (p *Parser) expect(typ lexer.TokenType) error {
@@ -112,6 +301,51 @@ func (p *Parser) expect(typ lexer.TokenType) error {
 	return nil
 }
 
+// expectGreaterThan consumes a single '>' that closes a type parameter or type
+// argument list. The scanner greedily produces compound tokens such as '>>' and
+// '>=', so when one of those is current it is split: the leading '>' is consumed
+// and the remainder becomes the current token.
+func (p *Parser) expectGreaterThan() error {
+	var rest lexer.TokenType
+	switch p.current.Type {
+	case lexer.GTR:
+		p.nextToken()
+		return nil
+	case lexer.SHR:
+		rest = lexer.GTR
+	case lexer.SHRUnsigned:
+		rest = lexer.SHR
+	case lexer.GEQ:
+		rest = lexer.ASSIGN
+	case lexer.ShrAssign:
+		rest = lexer.GEQ
+	case lexer.ShrUnsignedAssign:
+		rest = lexer.ShrAssign
+	default:
+		return p.errorAtCurrent(fmt.Sprintf("expected %v, got %v", lexer.GTR, p.current.Type))
+	}
+
+	tok := p.current
+	p.allTokens[len(p.allTokens)-1] = lexer.Token{
+		Type:    lexer.GTR,
+		Literal: ">",
+		Pos:     tok.Pos,
+		End:     tok.Pos + 1,
+		Line:    tok.Line,
+		Column:  tok.Column,
+	}
+	p.current = lexer.Token{
+		Type:    rest,
+		Literal: tok.Literal[1:],
+		Pos:     tok.Pos + 1,
+		End:     tok.End,
+		Line:    tok.Line,
+		Column:  tok.Column + 1,
+	}
+	p.allTokens = append(p.allTokens, p.current)
+	return nil
+}
+
 // match checks if the current token matches any of the given types.
 func (p *Parser) match(types ...lexer.TokenType) bool {
 	for _, typ := range types {
@@ -152,6 +386,17 @@ func (p *Parser) isAtEnd() bool {
 //
 //nolint:ireturn // This returns an interface by design as it's the base node type for the AST
 func (p *Parser) Parse() (ast.Node, error) {
+	program, _, err := p.ParseUntil(nil)
+	return program, err
+}
+
+// ParseUntil parses top-level statements like Parse, but if stop is not nil
+// it is called with the offset of each statement's first token and parsing
+// ends before the first statement for which it returns true. The program
+// then holds only the statements, tokens and comments that precede that
+// offset. The spans record where each statement came from and how much input
+// it depended on, which lets an incremental parser decide what an edit affects.
+func (p *Parser) ParseUntil(stop func(offset int) bool) (*ast.Program, []StatementSpan, error) {
 	program := &ast.Program{
 		BaseNode: ast.BaseNode{
 			NodeType: ast.NodeTypeProgram.String(),
@@ -159,9 +404,16 @@ func (p *Parser) Parse() (ast.Node, error) {
 		SourceType: p.sourceType,
 		Body:       []ast.Statement{},
 	}
+	var spans []StatementSpan
 
 	// Parse all top-level statements
 	for !p.isAtEnd() {
+		start := p.current.Pos
+		if stop != nil && stop(start) {
+			p.truncate(start)
+			break
+		}
+
 		stmt, err := p.parseStatementListItem()
 		if err != nil {
 			// Try to recover by synchronizing to the next statement
@@ -169,10 +421,18 @@ func (p *Parser) Parse() (ast.Node, error) {
 			continue
 		}
 		program.Body = append(program.Body, stmt)
+		spans = append(spans, StatementSpan{
+			Start:     start,
+			End:       p.current.Pos,
+			Lookahead: p.furthest + scanLookahead,
+		})
 	}
 
-	// Attach all comments and tokens
-	program.Comments = p.allComments
+	// Attach all comments and tokens. Comments scanned ahead and rewound
+	// over can leave allComments empty but not nil.
+	if len(p.allComments) > 0 {
+		program.Comments = p.allComments
+	}
 
 	// Convert lexer tokens to AST tokens
 	for _, tok := range p.allTokens {
@@ -184,10 +444,21 @@ func (p *Parser) Parse() (ast.Node, error) {
 	}
 
 	if len(p.errors) > 0 {
-		return program, p.errors[0]
+		return program, spans, p.errors[0]
 	}
 
-	return program, nil
+	return program, spans, nil
+}
+
+// truncate drops the tokens and comments that start at or after offset,
+// which the parser has read ahead but will not parse.
+func (p *Parser) truncate(offset int) {
+	for len(p.allTokens) > 0 && p.allTokens[len(p.allTokens)-1].Pos >= offset {
+		p.allTokens = p.allTokens[:len(p.allTokens)-1]
+	}
+	for len(p.allComments) > 0 && p.allComments[len(p.allComments)-1].Range[0] >= offset {
+		p.allComments = p.allComments[:len(p.allComments)-1]
+	}
 }
 
 // synchronize attempts to recover from a parse error by advancing to the next statement.
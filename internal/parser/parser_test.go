@@ -1,9 +1,13 @@
 package parser
 
 import (
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
-	"github.com/kdy1/go-typescript-eslint/internal/ast"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 )
 
 func TestParserBasic(t *testing.T) {
@@ -125,6 +129,11 @@ func TestParserExpressions(t *testing.T) {
 			input:   "!x;",
 			wantErr: false,
 		},
+		{
+			name:    "contextual keyword as identifier",
+			input:   "const fs = require('fs'); x = from + of;",
+			wantErr: false,
+		},
 		{
 			name:    "update expression",
 			input:   "x++;",
@@ -157,7 +166,12 @@ func TestParserExpressions(t *testing.T) {
 		},
 		{
 			name:    "object expression",
-			input:   "{ a: 1, b: 2 };",
+			input:   "({ a: 1, b: 2 });",
+			wantErr: false,
+		},
+		{
+			name:    "object expression with methods and accessors",
+			input:   "x = { a, get: 1, async() {}, get b() { return 1; }, set b(v) {}, async *c() {}, [d]: 2 };",
 			wantErr: false,
 		},
 		{
@@ -424,6 +438,74 @@ func TestParserDestructuring(t *testing.T) {
 	}
 }
 
+func TestParserForStatement(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantInit string
+		wantTest bool
+		wantErr  bool
+	}{
+		{
+			name:     "declaration init",
+			input:    "for (let i = 0; i < 3; i++) {}",
+			wantInit: ast.NodeTypeVariableDeclaration.String(),
+			wantTest: true,
+		},
+		{
+			name:     "declaration init without test",
+			input:    "for (var i = 0; ; ) {}",
+			wantInit: ast.NodeTypeVariableDeclaration.String(),
+		},
+		{
+			name:     "expression init",
+			input:    "for (i = 0; i < 3; i++) {}",
+			wantInit: ast.NodeTypeAssignmentExpression.String(),
+			wantTest: true,
+		},
+		{
+			name:     "empty init",
+			input:    "for (; i < 3; ) {}",
+			wantTest: true,
+		},
+		{
+			name:    "missing semicolon after declaration",
+			input:   "for (let i = 0 i < 3; i++) {}",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := New(tt.input).Parse()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			stmt, ok := node.(*ast.Program).Body[0].(*ast.ForStatement)
+			if !ok {
+				t.Fatalf("expected ForStatement, got %T", node.(*ast.Program).Body[0])
+			}
+			var gotInit string
+			if init, ok := stmt.Init.(ast.Node); ok {
+				gotInit = init.Type()
+			}
+			if gotInit != tt.wantInit {
+				t.Errorf("expected init %q, got %q", tt.wantInit, gotInit)
+			}
+			if (stmt.Test != nil) != tt.wantTest {
+				t.Errorf("expected test %v, got %v", tt.wantTest, stmt.Test)
+			}
+		})
+	}
+}
+
 func TestParserAsync(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -468,3 +550,1094 @@ func TestParserAsync(t *testing.T) {
 		})
 	}
 }
+
+func TestParserSpeculative(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantType string
+	}{
+		{
+			name:     "parenthesized arrow function",
+			input:    "(a, b) => a + b;",
+			wantType: ast.NodeTypeArrowFunctionExpression.String(),
+		},
+		{
+			name:     "parenthesized expression",
+			input:    "(a + b) * c;",
+			wantType: ast.NodeTypeBinaryExpression.String(),
+		},
+		{
+			name:     "generic arrow function",
+			input:    "<T>(x: T): T => x;",
+			wantType: ast.NodeTypeArrowFunctionExpression.String(),
+		},
+		{
+			name:     "arrow function with return type",
+			input:    "(x: number): number => x;",
+			wantType: ast.NodeTypeArrowFunctionExpression.String(),
+		},
+		{
+			name:     "single parameter arrow function",
+			input:    "x => x;",
+			wantType: ast.NodeTypeArrowFunctionExpression.String(),
+		},
+		{
+			name:     "async single parameter arrow function",
+			input:    "async x => x;",
+			wantType: ast.NodeTypeArrowFunctionExpression.String(),
+		},
+		{
+			name:     "async parenthesized arrow function",
+			input:    "async (x) => x;",
+			wantType: ast.NodeTypeArrowFunctionExpression.String(),
+		},
+		{
+			name:     "call to function named async",
+			input:    "async(1);",
+			wantType: ast.NodeTypeCallExpression.String(),
+		},
+		{
+			name:     "less than comparison",
+			input:    "a < b;",
+			wantType: ast.NodeTypeBinaryExpression.String(),
+		},
+		{
+			name:     "less than number",
+			input:    "i < 10;",
+			wantType: ast.NodeTypeBinaryExpression.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr := parseSingleExpression(t, tt.input)
+			if got := expr.Type(); got != tt.wantType {
+				t.Errorf("expected %s, got %s", tt.wantType, got)
+			}
+		})
+	}
+}
+
+func TestParserDeeplyNestedParentheses(t *testing.T) {
+	const depth = 40
+	nested := strings.Repeat("(a = ", depth) + "1" + strings.Repeat(")", depth)
+
+	tests := []struct {
+		name     string
+		input    string
+		wantType string
+	}{
+		{
+			name:     "parenthesized assignments",
+			input:    "x = " + nested + ";",
+			wantType: ast.NodeTypeAssignmentExpression.String(),
+		},
+		{
+			name:     "arrow function with nested default",
+			input:    "(b = " + nested + ") => b;",
+			wantType: ast.NodeTypeArrowFunctionExpression.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Each level first tries, and fails, to parse an arrow function
+			// head, which must not make the time exponential in the depth
+			done := make(chan error, 1)
+			go func() {
+				_, err := New(tt.input).Parse()
+				done <- err
+			}()
+
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("parsing %d nested parentheses took over a second", depth)
+			}
+
+			if got := parseSingleExpression(t, tt.input).Type(); got != tt.wantType {
+				t.Errorf("expected %s, got %s", tt.wantType, got)
+			}
+		})
+	}
+}
+
+func TestParserConditionalArrowFunction(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		wantConsequent string
+		wantAlternate  string
+	}{
+		{
+			name:           "parenthesized true branch",
+			input:          "a ? (b) : c => d;",
+			wantConsequent: ast.NodeTypeIdentifier.String(),
+			wantAlternate:  ast.NodeTypeArrowFunctionExpression.String(),
+		},
+		{
+			name:           "arrow function with return type in the true branch",
+			input:          "a ? (b): c => d : e;",
+			wantConsequent: ast.NodeTypeArrowFunctionExpression.String(),
+			wantAlternate:  ast.NodeTypeIdentifier.String(),
+		},
+		{
+			name:           "arrow function with return type in parentheses",
+			input:          "a ? ((b): c => d) : e;",
+			wantConsequent: ast.NodeTypeArrowFunctionExpression.String(),
+			wantAlternate:  ast.NodeTypeIdentifier.String(),
+		},
+		{
+			name:           "arrow function with return type in the false branch",
+			input:          "a ? b : (c): d => e;",
+			wantConsequent: ast.NodeTypeIdentifier.String(),
+			wantAlternate:  ast.NodeTypeArrowFunctionExpression.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, ok := parseSingleExpression(t, tt.input).(*ast.ConditionalExpression)
+			if !ok {
+				t.Fatalf("expected a conditional expression")
+			}
+			if got := expr.Consequent.Type(); got != tt.wantConsequent {
+				t.Errorf("expected consequent %s, got %s", tt.wantConsequent, got)
+			}
+			if got := expr.Alternate.Type(); got != tt.wantAlternate {
+				t.Errorf("expected alternate %s, got %s", tt.wantAlternate, got)
+			}
+		})
+	}
+}
+
+func TestParserNestedTypeArguments(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "nested generic",
+			input: "function f(x: Array<Array<number>>) {}",
+		},
+		{
+			name:  "triple nested generic",
+			input: "function f(x: A<B<C<number>>>) {}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := New(tt.input)
+			if _, err := parser.Parse(); err != nil {
+				t.Errorf("Parse() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestParserTypeArgumentsAreVisited(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		jsx   bool
+	}{
+		{name: "type reference", input: "let x: Array<number>;"},
+		{name: "import type", input: "let x: import('./a').B<number>;"},
+		{name: "interface extends", input: "interface A extends B<number> {}"},
+		{name: "class extends", input: "class A extends B<number> {}"},
+		{name: "class implements", input: "class A implements B<number> {}"},
+		{name: "class expression extends", input: "(class extends B<number> {});"},
+		{name: "jsx element", input: "<A<number> />;", jsx: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := New(tt.input)
+			parser.SetJSXEnabled(tt.jsx)
+			node, err := parser.Parse()
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			// Walk visits typeArguments, not the deprecated typeParameters
+			found := ast.FindByType(node, ast.NodeTypeTSTypeParameterInstantiation.String())
+			if len(found) != 1 {
+				t.Errorf("expected 1 visited type argument list, got %d", len(found))
+			}
+		})
+	}
+}
+
+// parseSingleExpression parses input and returns the expression of its only statement.
+func parseSingleExpression(t *testing.T, input string) ast.Expression {
+	t.Helper()
+
+	node, err := New(input).Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	program, ok := node.(*ast.Program)
+	if !ok {
+		t.Fatalf("Parse() did not return a Program node")
+	}
+	if len(program.Body) != 1 {
+		t.Fatalf("expected 1 statement, got %d", len(program.Body))
+	}
+	stmt, ok := program.Body[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("expected ExpressionStatement, got %T", program.Body[0])
+	}
+	return stmt.Expression
+}
+
+func TestParserTypeArgumentsInExpression(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantType     string
+		wantTypeArgs bool
+	}{
+		{
+			name:         "instantiation expression",
+			input:        "createBox<string>;",
+			wantType:     ast.NodeTypeTSInstantiationExpression.String(),
+			wantTypeArgs: true,
+		},
+		{
+			name:         "call with type arguments",
+			input:        "foo<string, number>(a, b);",
+			wantType:     ast.NodeTypeCallExpression.String(),
+			wantTypeArgs: true,
+		},
+		{
+			name:         "new with type arguments",
+			input:        "new Map<string, number>();",
+			wantType:     ast.NodeTypeNewExpression.String(),
+			wantTypeArgs: true,
+		},
+		{
+			name:         "new with type arguments and no arguments",
+			input:        "new Set<string>;",
+			wantType:     ast.NodeTypeNewExpression.String(),
+			wantTypeArgs: true,
+		},
+		{
+			name:         "tagged template with type arguments",
+			input:        "sql<Row>`select`;",
+			wantType:     ast.NodeTypeTaggedTemplateExpression.String(),
+			wantTypeArgs: true,
+		},
+		{
+			name:         "tagged template with substitution",
+			input:        "sql<Row>`select ${id} from t`;",
+			wantType:     ast.NodeTypeTaggedTemplateExpression.String(),
+			wantTypeArgs: true,
+		},
+		{
+			name:         "optional call with type arguments",
+			input:        "a?.b<T>();",
+			wantType:     ast.NodeTypeChainExpression.String(),
+			wantTypeArgs: true,
+		},
+		{
+			name:         "optional call with type arguments after ?.",
+			input:        "a?.<T>();",
+			wantType:     ast.NodeTypeChainExpression.String(),
+			wantTypeArgs: true,
+		},
+		{
+			name:     "relational operators",
+			input:    "a < b > c;",
+			wantType: ast.NodeTypeBinaryExpression.String(),
+		},
+		{
+			name:     "less than followed by unary plus",
+			input:    "a < b > +c;",
+			wantType: ast.NodeTypeBinaryExpression.String(),
+		},
+		{
+			name:     "shift after comparison",
+			input:    "a < b >> c;",
+			wantType: ast.NodeTypeBinaryExpression.String(),
+		},
+		{
+			name:     "new without type arguments",
+			input:    "new Foo(a).bar;",
+			wantType: ast.NodeTypeMemberExpression.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr := parseSingleExpression(t, tt.input)
+			if got := expr.Type(); got != tt.wantType {
+				t.Fatalf("expected %s, got %s", tt.wantType, got)
+			}
+
+			var typeArgs *ast.TSTypeParameterInstantiation
+			switch n := expr.(type) {
+			case *ast.TSInstantiationExpression:
+				typeArgs = n.TypeArguments
+			case *ast.CallExpression:
+				typeArgs = n.TypeArguments
+			case *ast.NewExpression:
+				typeArgs = n.TypeArguments
+			case *ast.TaggedTemplateExpression:
+				typeArgs = n.TypeArguments
+			case *ast.ChainExpression:
+				if call, ok := n.Expression.(*ast.CallExpression); ok {
+					typeArgs = call.TypeArguments
+				}
+			}
+			if (typeArgs != nil) != tt.wantTypeArgs {
+				t.Errorf("expected type arguments: %v, got %v", tt.wantTypeArgs, typeArgs)
+			}
+		})
+	}
+}
+
+// chainShape renders the spine of a member or call chain, following each
+// node's object, callee or inner expression down to its head.
+func chainShape(expr ast.Expression) string {
+	var inner ast.Expression
+	switch n := expr.(type) {
+	case *ast.ChainExpression:
+		inner = n.Expression
+	case *ast.CallExpression:
+		inner = n.Callee
+	case *ast.MemberExpression:
+		inner = n.Object
+	case *ast.TSNonNullExpression:
+		inner = n.Expression
+	default:
+		return expr.Type()
+	}
+	return expr.Type() + "(" + chainShape(inner) + ")"
+}
+
+func TestParserOptionalChain(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "optional member",
+			input: "a?.b;",
+			want:  "ChainExpression(MemberExpression(Identifier))",
+		},
+		{
+			name:  "optional call",
+			input: "a?.();",
+			want:  "ChainExpression(CallExpression(Identifier))",
+		},
+		{
+			name:  "call on optional member",
+			input: "a?.b();",
+			want:  "ChainExpression(CallExpression(MemberExpression(Identifier)))",
+		},
+		{
+			name:  "call with type arguments on optional member",
+			input: "a?.b<T>();",
+			want:  "ChainExpression(CallExpression(MemberExpression(Identifier)))",
+		},
+		{
+			name:  "optional call with type arguments",
+			input: "a?.<T>();",
+			want:  "ChainExpression(CallExpression(Identifier))",
+		},
+		{
+			name:  "members after optional member",
+			input: "a?.b.c[d];",
+			want:  "ChainExpression(MemberExpression(MemberExpression(MemberExpression(Identifier))))",
+		},
+		{
+			name:  "several optional links",
+			input: "a?.b?.c();",
+			want:  "ChainExpression(CallExpression(MemberExpression(MemberExpression(Identifier))))",
+		},
+		{
+			name:  "non-null assertion inside chain",
+			input: "a?.b!.c;",
+			want:  "ChainExpression(MemberExpression(TSNonNullExpression(MemberExpression(Identifier))))",
+		},
+		{
+			name:  "parentheses end the chain",
+			input: "(a?.b).c;",
+			want:  "MemberExpression(ChainExpression(MemberExpression(Identifier)))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr := parseSingleExpression(t, tt.input)
+			if got := chainShape(expr); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("tagged template in chain", func(t *testing.T) {
+		if _, err := New("a?.b`c`;").Parse(); err == nil {
+			t.Error("expected an error for a tagged template in an optional chain")
+		}
+	})
+}
+
+func TestParserTypeOnlyImportsExports(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		wantKind       string
+		wantSpecKinds  []string
+		wantSpecifiers []string
+	}{
+		{
+			name:           "type modifier on import specifier",
+			input:          "import { type Foo, bar } from 'x';",
+			wantKind:       "value",
+			wantSpecKinds:  []string{"type", "value"},
+			wantSpecifiers: []string{"Foo", "bar"},
+		},
+		{
+			name:           "import type declaration",
+			input:          "import type { Foo } from 'x';",
+			wantKind:       "type",
+			wantSpecKinds:  []string{"value"},
+			wantSpecifiers: []string{"Foo"},
+		},
+		{
+			name:           "import type namespace",
+			input:          "import type * as ns from 'x';",
+			wantKind:       "type",
+			wantSpecifiers: []string{"ns"},
+		},
+		{
+			name:           "import type default",
+			input:          "import type Foo from 'x';",
+			wantKind:       "type",
+			wantSpecifiers: []string{"Foo"},
+		},
+		{
+			name:           "default import named type",
+			input:          "import type from 'x';",
+			wantKind:       "value",
+			wantSpecifiers: []string{"type"},
+		},
+		{
+			name:           "default import named type with named imports",
+			input:          "import type, { a } from 'x';",
+			wantKind:       "value",
+			wantSpecKinds:  []string{"value"},
+			wantSpecifiers: []string{"type", "a"},
+		},
+		{
+			name:           "import specifier named type",
+			input:          "import { type } from 'x';",
+			wantKind:       "value",
+			wantSpecKinds:  []string{"value"},
+			wantSpecifiers: []string{"type"},
+		},
+		{
+			name:           "import specifier named type with alias",
+			input:          "import { type as t } from 'x';",
+			wantKind:       "value",
+			wantSpecKinds:  []string{"value"},
+			wantSpecifiers: []string{"t"},
+		},
+		{
+			name:           "type-only import specifier named as",
+			input:          "import { type as } from 'x';",
+			wantKind:       "value",
+			wantSpecKinds:  []string{"type"},
+			wantSpecifiers: []string{"as"},
+		},
+		{
+			name:           "import specifier named type with alias as",
+			input:          "import { type as as } from 'x';",
+			wantKind:       "value",
+			wantSpecKinds:  []string{"value"},
+			wantSpecifiers: []string{"as"},
+		},
+		{
+			name:           "type-only import specifier named as with alias as",
+			input:          "import { type as as as } from 'x';",
+			wantKind:       "value",
+			wantSpecKinds:  []string{"type"},
+			wantSpecifiers: []string{"as"},
+		},
+		{
+			name:           "type-only import specifier named as with alias",
+			input:          "import { type as as t } from 'x';",
+			wantKind:       "value",
+			wantSpecKinds:  []string{"type"},
+			wantSpecifiers: []string{"t"},
+		},
+		{
+			name:           "type modifier on export specifier",
+			input:          "export { type Foo, bar };",
+			wantKind:       "value",
+			wantSpecKinds:  []string{"type", "value"},
+			wantSpecifiers: []string{"Foo", "bar"},
+		},
+		{
+			name:           "export type declaration",
+			input:          "export type { Foo } from 'x';",
+			wantKind:       "type",
+			wantSpecKinds:  []string{"value"},
+			wantSpecifiers: []string{"Foo"},
+		},
+		{
+			name:     "export type all",
+			input:    "export type * from 'x';",
+			wantKind: "type",
+		},
+		{
+			name:     "export type all as namespace",
+			input:    "export type * as ns from 'x';",
+			wantKind: "type",
+		},
+		{
+			name:     "export type alias",
+			input:    "export type Foo = string;",
+			wantKind: "type",
+		},
+		{
+			name:     "export interface",
+			input:    "export interface Foo {}",
+			wantKind: "type",
+		},
+		{
+			name:     "export const",
+			input:    "export const foo = 1;",
+			wantKind: "value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := New(tt.input).Parse()
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			program, ok := node.(*ast.Program)
+			if !ok || len(program.Body) != 1 {
+				t.Fatalf("expected a program with 1 statement")
+			}
+
+			var kind *string
+			var specKinds, specifiers []string
+			switch n := program.Body[0].(type) {
+			case *ast.ImportDeclaration:
+				kind = n.ImportKind
+				for _, spec := range n.Specifiers {
+					switch s := spec.(type) {
+					case *ast.ImportSpecifier:
+						specKinds = append(specKinds, *s.ImportKind)
+						specifiers = append(specifiers, s.Local.Name)
+					case *ast.ImportDefaultSpecifier:
+						specifiers = append(specifiers, s.Local.Name)
+					case *ast.ImportNamespaceSpecifier:
+						specifiers = append(specifiers, s.Local.Name)
+					}
+				}
+			case *ast.ExportNamedDeclaration:
+				kind = n.ExportKind
+				for _, spec := range n.Specifiers {
+					specKinds = append(specKinds, *spec.ExportKind)
+					if id, ok := spec.Exported.(*ast.Identifier); ok {
+						specifiers = append(specifiers, id.Name)
+					}
+				}
+			case *ast.ExportAllDeclaration:
+				kind = n.ExportKind
+			default:
+				t.Fatalf("unexpected statement %T", n)
+			}
+
+			if kind == nil || *kind != tt.wantKind {
+				t.Errorf("expected kind %q, got %v", tt.wantKind, kind)
+			}
+			if !slices.Equal(specKinds, tt.wantSpecKinds) {
+				t.Errorf("expected specifier kinds %v, got %v", tt.wantSpecKinds, specKinds)
+			}
+			if !slices.Equal(specifiers, tt.wantSpecifiers) {
+				t.Errorf("expected specifiers %v, got %v", tt.wantSpecifiers, specifiers)
+			}
+		})
+	}
+}
+
+func TestParserTupleAndIndexedAccessTypes(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "named tuple members",
+			input: "type T = [start: number, end?: number];",
+			want:  "[start: number, end?: number]",
+		},
+		{
+			name:  "optional tuple element",
+			input: "type T = [string, number?];",
+			want:  "[string, number?]",
+		},
+		{
+			name:  "rest tuple element",
+			input: "type T = [string, ...number[]];",
+			want:  "[string, ...number[]]",
+		},
+		{
+			name:  "named rest tuple element",
+			input: "type T = [first: string, ...rest: number[]];",
+			want:  "[first: string, ...rest: number[]]",
+		},
+		{
+			name:  "variadic tuple",
+			input: "type T = [...A, ...B];",
+			want:  "[...A, ...B]",
+		},
+		{
+			name:  "indexed access",
+			input: "type T = Foo['key'];",
+			want:  `Foo["key"]`,
+		},
+		{
+			name:  "nested indexed access",
+			input: "type T = Foo['a']['b'];",
+			want:  `Foo["a"]["b"]`,
+		},
+		{
+			name:  "typeof indexed access",
+			input: "type T = typeof x[number];",
+			want:  "typeof x[number]",
+		},
+		{
+			name:  "unique symbol",
+			input: "type T = unique symbol;",
+			want:  "unique symbol",
+		},
+		{
+			name:  "keyof",
+			input: "type T = keyof Foo;",
+			want:  "keyof Foo",
+		},
+		{
+			name:  "readonly array",
+			input: "type T = readonly string[];",
+			want:  "readonly string[]",
+		},
+		{
+			name:  "keyof in union",
+			input: "type T = keyof A | keyof B;",
+			want:  "keyof A | keyof B",
+		},
+		{
+			name:  "parenthesized array element",
+			input: "type T = (A | B)[];",
+			want:  "A | B[]",
+		},
+		{
+			name:  "function type",
+			input: "type T = (x: number) => string;",
+			want:  "(1) => string",
+		},
+		{
+			name:  "abstract constructor type",
+			input: "type T = abstract new () => Foo;",
+			want:  "abstract new (0) => Foo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := New(tt.input).Parse()
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			program, ok := node.(*ast.Program)
+			if !ok || len(program.Body) != 1 {
+				t.Fatalf("expected a program with 1 statement")
+			}
+			alias, ok := program.Body[0].(*ast.TSTypeAliasDeclaration)
+			if !ok {
+				t.Fatalf("expected TSTypeAliasDeclaration, got %T", program.Body[0])
+			}
+			if got := describeType(alias.TypeAnnotation); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+// describeType renders a type node in a compact TypeScript-like syntax.
+// Function and constructor types render their parameter count only.
+func describeType(node ast.Node) string {
+	switch n := node.(type) {
+	case *ast.TSStringKeyword:
+		return "string"
+	case *ast.TSNumberKeyword:
+		return "number"
+	case *ast.TSSymbolKeyword:
+		return "symbol"
+	case *ast.TSTypeReference:
+		return describeType(n.TypeName.(ast.Node))
+	case *ast.Identifier:
+		return n.Name
+	case *ast.TSLiteralType:
+		return fmt.Sprintf("%q", n.Literal.(*ast.Literal).Value)
+	case *ast.TSTypeQuery:
+		return "typeof " + describeType(n.ExprName.(ast.Node))
+	case *ast.TSArrayType:
+		return describeType(n.ElementType) + "[]"
+	case *ast.TSIndexedAccessType:
+		return describeType(n.ObjectType) + "[" + describeType(n.IndexType) + "]"
+	case *ast.TSTypeOperator:
+		return n.Operator + " " + describeType(n.TypeAnnotation)
+	case *ast.TSUnionType:
+		parts := make([]string, len(n.Types))
+		for i, typ := range n.Types {
+			parts[i] = describeType(typ)
+		}
+		return strings.Join(parts, " | ")
+	case *ast.TSTupleType:
+		parts := make([]string, len(n.ElementTypes))
+		for i, typ := range n.ElementTypes {
+			parts[i] = describeType(typ)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case *ast.TSNamedTupleMember:
+		if n.Optional {
+			return n.Label.Name + "?: " + describeType(n.ElementType)
+		}
+		return n.Label.Name + ": " + describeType(n.ElementType)
+	case *ast.TSOptionalType:
+		return describeType(n.TypeAnnotation) + "?"
+	case *ast.TSRestType:
+		return "..." + describeType(n.TypeAnnotation)
+	case *ast.TSFunctionType:
+		return fmt.Sprintf("(%d) => %s", len(n.Params), describeType(n.ReturnType.TypeAnnotation))
+	case *ast.TSConstructorType:
+		prefix := "new"
+		if n.Abstract {
+			prefix = "abstract new"
+		}
+		return fmt.Sprintf("%s (%d) => %s", prefix, len(n.Params), describeType(n.ReturnType.TypeAnnotation))
+	default:
+		return fmt.Sprintf("%T", node)
+	}
+}
+
+func TestParserMemberModifiers(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "definite and optional class properties",
+			input: "class C { foo!: string; bar?: number; baz = 1; }",
+			want:  []string{"property foo!", "property bar?", "property baz"},
+		},
+		{
+			name:  "optional method",
+			input: "class C { m?(): void {} }",
+			want:  []string{"method m?"},
+		},
+		{
+			name:  "bodyless optional method",
+			input: "class A { m?(): void; }",
+			want:  []string{"method m?"},
+		},
+		{
+			name:  "method overloads",
+			input: "class A { m(): void; m(x: number): void\n m(x?: number) {} }",
+			want:  []string{"method m", "method m", "method m"},
+		},
+		{
+			name:  "accessors and constructor",
+			input: "class C { constructor() {} get x() { return 1; } set x(v) {} static s() {} }",
+			want:  []string{"constructor constructor", "get x", "set x", "method s"},
+		},
+		{
+			name:  "modifier keywords as member names",
+			input: "class C { get() {} set = 1; static() {} readonly: boolean; }",
+			want:  []string{"method get", "property set", "method static", "property readonly"},
+		},
+		{
+			name:  "accessor signatures in interface",
+			input: "interface I { get x(): number; set x(v: number); m?(): void; get: string; }",
+			want:  []string{"get x", "set x", "method m?", "property get"},
+		},
+		{
+			name:  "accessor signatures in type literal",
+			input: "type T = { get x(): number; readonly y: string };",
+			want:  []string{"get x", "property y"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := New(tt.input).Parse()
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			program, ok := node.(*ast.Program)
+			if !ok || len(program.Body) != 1 {
+				t.Fatalf("expected a program with 1 statement")
+			}
+
+			var members []interface{}
+			switch n := program.Body[0].(type) {
+			case *ast.ClassDeclaration:
+				members = n.Body.Body
+			case *ast.TSInterfaceDeclaration:
+				members = n.Body.Body
+			case *ast.TSTypeAliasDeclaration:
+				members = n.TypeAnnotation.(*ast.TSTypeLiteral).Members
+			default:
+				t.Fatalf("unexpected statement %T", n)
+			}
+
+			got := make([]string, len(members))
+			for i, member := range members {
+				got[i] = describeMember(member)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParserEmptyBodyMethods(t *testing.T) {
+	input := "class A { m?(): void; m(): void; m(x?: number) {} }"
+	want := []string{
+		ast.NodeTypeTSEmptyBodyFunctionExpression.String(),
+		ast.NodeTypeTSEmptyBodyFunctionExpression.String(),
+		ast.NodeTypeFunctionExpression.String(),
+	}
+
+	node, err := New(input).Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	class := node.(*ast.Program).Body[0].(*ast.ClassDeclaration)
+	if len(class.Body.Body) != len(want) {
+		t.Fatalf("expected %d members, got %d", len(want), len(class.Body.Body))
+	}
+	for i, member := range class.Body.Body {
+		method, ok := member.(*ast.MethodDefinition)
+		if !ok {
+			t.Fatalf("member %d: expected *ast.MethodDefinition, got %T", i, member)
+		}
+		if got := method.Value.Type(); got != want[i] {
+			t.Errorf("member %d: expected value of type %s, got %s", i, want[i], got)
+		}
+		if (method.Value.Body == nil) != (want[i] != ast.NodeTypeFunctionExpression.String()) {
+			t.Errorf("member %d: unexpected body %v", i, method.Value.Body)
+		}
+	}
+}
+
+// describeMember renders a class or type member as "<kind> <name><marker>",
+// where the marker is "?" for optional and "!" for definite members.
+func describeMember(member interface{}) string {
+	describe := func(kind string, key ast.Expression, optional, definite bool) string {
+		name := fmt.Sprintf("%T", key)
+		if id, ok := key.(*ast.Identifier); ok {
+			name = id.Name
+		}
+		switch {
+		case optional:
+			name += "?"
+		case definite:
+			name += "!"
+		}
+		return kind + " " + name
+	}
+
+	switch n := member.(type) {
+	case *ast.PropertyDefinition:
+		return describe("property", n.Key, n.Optional, n.Definite)
+	case *ast.MethodDefinition:
+		return describe(n.Kind, n.Key, n.Optional, false)
+	case *ast.TSPropertySignature:
+		return describe("property", n.Key, n.Optional, false)
+	case *ast.TSMethodSignature:
+		return describe(n.Kind, n.Key, n.Optional, false)
+	default:
+		return fmt.Sprintf("%T", member)
+	}
+}
+
+func TestParserDefiniteAndThisParameters(t *testing.T) {
+	node, err := New("let x!: number; function f(this: Window, y?: string) {}").Parse()
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	program := node.(*ast.Program)
+
+	decl := program.Body[0].(*ast.VariableDeclaration).Declarations[0]
+	if !decl.Definite {
+		t.Errorf("expected definite declarator")
+	}
+	if id := decl.ID.(*ast.Identifier); id.TypeAnnotation == nil {
+		t.Errorf("expected type annotation on x")
+	}
+
+	fn := program.Body[1].(*ast.FunctionDeclaration)
+	if len(fn.Params) != 2 {
+		t.Fatalf("expected 2 params, got %d", len(fn.Params))
+	}
+	this := fn.Params[0].(*ast.Identifier)
+	if this.Name != "this" || this.TypeAnnotation == nil {
+		t.Errorf("expected typed this parameter, got %q", this.Name)
+	}
+	if y := fn.Params[1].(*ast.Identifier); !y.Optional {
+		t.Errorf("expected optional parameter y")
+	}
+}
+
+func TestDecodeJSXEntities(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"plain text", "plain text"},
+		{"a &amp; b", "a & b"},
+		{"&lt;div&gt;", "<div>"},
+		{"&nbsp;", "\u00a0"},
+		{"&#x2014;&#X2014;", "——"},
+		{"&#8212;", "—"},
+		{"&CounterClockwiseContourIntegral;", "∳"},
+		{"&NotNestedGreaterGreater;", "⪢̸"},
+		{"&semi;", ";"},
+		{"&amp", "&amp"},
+		{"&ampx;", "&ampx;"},
+		{"&unknown;", "&unknown;"},
+		{"&#xZZ;", "&#xZZ;"},
+		{"&#;", "&#;"},
+		{"& &amp;", "& &"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := decodeJSXEntities(tt.input); got != tt.want {
+				t.Errorf("decodeJSXEntities(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParserJSXTextAndAttributes(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "text with entities",
+			input: `<p>Tom &amp; Jerry&nbsp;&#x2014;</p>;`,
+			want:  []string{`text "Tom & Jerry\u00a0—" raw "Tom &amp; Jerry&nbsp;&#x2014;"`},
+		},
+		{
+			name:  "attribute strings keep raw and skip escapes",
+			input: `<a title="&quot;hi&quot;" path='C:\new' />;`,
+			want: []string{
+				`attr "\"hi\"" raw "\"&quot;hi&quot;\""`,
+				`attr "C:\\new" raw "'C:\\new'"`,
+			},
+		},
+		{
+			name:  "text around children",
+			input: "<ul>\n  <li>a &lt; b</li>{x} &gt;\n</ul>;",
+			want: []string{
+				`text "\n  " raw "\n  "`,
+				`text "a < b" raw "a &lt; b"`,
+				`text " >\n" raw " &gt;\n"`,
+			},
+		},
+		{
+			name:  "fragment",
+			input: `<>&copy; 2024</>;`,
+			want:  []string{`text "© 2024" raw "&copy; 2024"`},
+		},
+		{
+			name:  "nested element in expression",
+			input: `<a>{ok && <b>&hearts;</b>}</a>; next();`,
+			want:  []string{`text "♥" raw "&hearts;"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(tt.input)
+			p.SetJSXEnabled(true)
+			node, err := p.Parse()
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			var got []string
+			ast.Walk(node, ast.VisitorFunc(func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.JSXText:
+					got = append(got, fmt.Sprintf("text %q raw %q", n.Value, n.Raw))
+				case *ast.JSXAttribute:
+					if lit, ok := n.Value.(*ast.Literal); ok {
+						got = append(got, fmt.Sprintf("attr %q raw %q", lit.Value, lit.Raw))
+					}
+				}
+				return true
+			}))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParserParseUntil(t *testing.T) {
+	source := "let a = 1;\n/* c */ let b = 2;\nlet c = 3;"
+
+	p := New(source)
+	program, spans, err := p.ParseUntil(func(offset int) bool {
+		return offset == strings.Index(source, "let c")
+	})
+	if err != nil {
+		t.Fatalf("ParseUntil() error = %v", err)
+	}
+
+	if len(program.Body) != 2 || len(spans) != 2 {
+		t.Fatalf("expected 2 statements and spans, got %d and %d", len(program.Body), len(spans))
+	}
+	if want := (StatementSpan{Start: 19, End: 30, Lookahead: 39}); spans[1] != want {
+		t.Errorf("expected span %+v, got %+v", want, spans[1])
+	}
+	if last := program.Tokens[len(program.Tokens)-1]; last.Value != ";" || last.Range[0] != 28 {
+		t.Errorf("expected tokens to end with the ';' at 28, got %+v", last)
+	}
+
+	// Restart at the second statement, after its leading comment.
+	p = New(source)
+	p.Seek(11, 2, 0)
+	program, spans, err = p.ParseUntil(nil)
+	if err != nil {
+		t.Fatalf("ParseUntil() error = %v", err)
+	}
+	if len(program.Body) != 2 || spans[0].Start != 19 || len(program.Comments) != 1 {
+		t.Errorf("expected 2 statements from 19 and 1 comment, got %d from %v and %d", len(program.Body), spans, len(program.Comments))
+	}
+	if tok := p.allTokens[0]; tok.Line != 2 || tok.Column != 8 {
+		t.Errorf("expected first token at 2:8, got %d:%d", tok.Line, tok.Column)
+	}
+}
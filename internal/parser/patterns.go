@@ -1,8 +1,8 @@
 package parser
 
 import (
-	"github.com/kdy1/go-typescript-eslint/internal/ast"
 	"github.com/kdy1/go-typescript-eslint/internal/lexer"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 )
 
 // parseBindingPattern parses a binding pattern (identifier, array pattern, or object pattern).
@@ -341,6 +341,7 @@ func (p *Parser) parseTemplateLiteral() (*ast.TemplateLiteral, error) {
 			expressions = append(expressions, expr)
 
 			// Parse template middle or tail
+			p.reScanTemplateContinuation()
 			if p.current.Type == lexer.TemplateMiddle {
 				cooked := p.current.Literal // TODO: Unescape
 				quasis = append(quasis, ast.TemplateElement{
@@ -3,8 +3,8 @@ package parser
 import (
 	"fmt"
 
-	"github.com/kdy1/go-typescript-eslint/internal/ast"
 	"github.com/kdy1/go-typescript-eslint/internal/lexer"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 )
 
 // parseStatementListItem parses a statement or declaration at the top level or in a block.
@@ -211,6 +211,22 @@ func (p *Parser) parseVariableDeclarator() (*ast.VariableDeclarator, error) {
 		return nil, err
 	}
 
+	// Parse definite assignment assertion (let x!: number)
+	_, isIdentifier := id.(*ast.Identifier)
+	definite := isIdentifier && p.current.Type == lexer.NOT && p.peek.Type == lexer.COLON
+	if definite {
+		p.nextToken()
+	}
+
+	// Parse type annotation (TypeScript)
+	if p.consume(lexer.COLON) {
+		typeAnnotation, err := p.parseTSTypeAnnotation()
+		if err != nil {
+			return nil, err
+		}
+		setPatternTypeAnnotation(id, typeAnnotation)
+	}
+
 	var init ast.Expression
 	if p.consume(lexer.ASSIGN) {
 		init, err = p.parseAssignmentExpression()
@@ -224,11 +240,24 @@ func (p *Parser) parseVariableDeclarator() (*ast.VariableDeclarator, error) {
 			NodeType: ast.NodeTypeVariableDeclarator.String(),
 			Range:    &ast.Range{start, p.current.Pos},
 		},
-		ID:   id,
-		Init: init,
+		ID:       id,
+		Init:     init,
+		Definite: definite,
 	}, nil
 }
 
+// setPatternTypeAnnotation attaches a type annotation to a binding pattern.
+func setPatternTypeAnnotation(pattern ast.Pattern, typeAnnotation *ast.TSTypeAnnotation) {
+	switch n := pattern.(type) {
+	case *ast.Identifier:
+		n.TypeAnnotation = typeAnnotation
+	case *ast.ArrayPattern:
+		n.TypeAnnotation = typeAnnotation
+	case *ast.ObjectPattern:
+		n.TypeAnnotation = typeAnnotation
+	}
+}
+
 // parseIfStatement parses an if statement.
 func (p *Parser) parseIfStatement() (*ast.IfStatement, error) {
 	start := p.current.Pos
@@ -383,7 +412,7 @@ func (p *Parser) parseForInit(start int, await bool) (ast.Node, bool, error) {
 		return p.parseForVarInit(start, await)
 	}
 
-	if p.consume(lexer.SEMICOLON) {
+	if p.match(lexer.SEMICOLON) {
 		return nil, false, nil
 	}
 
@@ -399,7 +428,6 @@ func (p *Parser) parseForInit(start int, await bool) (ast.Node, bool, error) {
 		return stmt, true, err
 	}
 
-	p.consume(lexer.SEMICOLON)
 	return expr, false, nil
 }
 
@@ -475,6 +503,12 @@ func (p *Parser) createForVarDeclaration(start int, kind string, idStart int, id
 
 // parseRegularForLoop parses the test, update, and body of a regular for loop.
 func (p *Parser) parseRegularForLoop(start int, init ast.Node) (*ast.ForStatement, error) {
+	// The init, whether empty, an expression or a declaration, ends at the
+	// first ';'
+	if err := p.expect(lexer.SEMICOLON); err != nil {
+		return nil, err
+	}
+
 	var test ast.Expression
 	var err error
 	if !p.match(lexer.SEMICOLON) {
@@ -1,8 +1,8 @@
 package parser
 
 import (
-	"github.com/kdy1/go-typescript-eslint/internal/ast"
 	"github.com/kdy1/go-typescript-eslint/internal/lexer"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 )
 
 // parseTSTypeAnnotation parses a TypeScript type annotation (: Type).
@@ -37,7 +37,7 @@ func (p *Parser) parseTSType() (ast.TSNode, error) {
 // parseTSUnionOrIntersectionType parses union or intersection types (A | B or A & B).
 func (p *Parser) parseTSUnionOrIntersectionType() (ast.TSNode, error) {
 	// Parse first type
-	typ, err := p.parseTSPrimaryType()
+	typ, err := p.parseTSTypeOperatorOrHigher()
 	if err != nil {
 		return nil, err
 	}
@@ -48,7 +48,7 @@ func (p *Parser) parseTSUnionOrIntersectionType() (ast.TSNode, error) {
 		// Union type
 		types := []ast.TSNode{typ}
 		for p.consume(lexer.OR) {
-			t, err := p.parseTSPrimaryType()
+			t, err := p.parseTSTypeOperatorOrHigher()
 			if err != nil {
 				return nil, err
 			}
@@ -64,7 +64,7 @@ func (p *Parser) parseTSUnionOrIntersectionType() (ast.TSNode, error) {
 		// Intersection type
 		types := []ast.TSNode{typ}
 		for p.consume(lexer.AND) {
-			t, err := p.parseTSPrimaryType()
+			t, err := p.parseTSTypeOperatorOrHigher()
 			if err != nil {
 				return nil, err
 			}
@@ -81,6 +81,82 @@ func (p *Parser) parseTSUnionOrIntersectionType() (ast.TSNode, error) {
 	return typ, nil
 }
 
+// parseTSTypeOperatorOrHigher parses a type prefixed by a type operator
+// (keyof T, unique symbol, readonly T[]) or a postfix type.
+func (p *Parser) parseTSTypeOperatorOrHigher() (ast.TSNode, error) {
+	start := p.current.Pos
+
+	var operator string
+	switch {
+	case p.current.Type == lexer.READONLY:
+		operator = "readonly"
+	case p.current.Type == lexer.IDENT && (p.current.Literal == "keyof" || p.current.Literal == "unique"):
+		operator = p.current.Literal
+	default:
+		return p.parseTSPostfixType()
+	}
+	p.nextToken()
+
+	typeAnnotation, err := p.parseTSTypeOperatorOrHigher()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.TSTypeOperator{
+		BaseNode: ast.BaseNode{
+			NodeType: ast.NodeTypeTSTypeOperator.String(),
+			Range:    &ast.Range{start, p.current.Pos},
+		},
+		Operator:       operator,
+		TypeAnnotation: typeAnnotation,
+	}, nil
+}
+
+// parseTSPostfixType parses array types (T[]) and indexed access types (T['key'])
+// following a primary type. A '[' on a new line does not continue the type.
+func (p *Parser) parseTSPostfixType() (ast.TSNode, error) {
+	start := p.current.Pos
+
+	typ, err := p.parseTSPrimaryType()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.current.Type == lexer.LBRACK && !p.hasPrecedingLineBreak() {
+		p.nextToken() // consume '['
+
+		if p.consume(lexer.RBRACK) {
+			typ = &ast.TSArrayType{
+				BaseNode: ast.BaseNode{
+					NodeType: ast.NodeTypeTSArrayType.String(),
+					Range:    &ast.Range{start, p.current.Pos},
+				},
+				ElementType: typ,
+			}
+			continue
+		}
+
+		indexType, err := p.parseTSType()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(lexer.RBRACK); err != nil {
+			return nil, err
+		}
+
+		typ = &ast.TSIndexedAccessType{
+			BaseNode: ast.BaseNode{
+				NodeType: ast.NodeTypeTSIndexedAccessType.String(),
+				Range:    &ast.Range{start, p.current.Pos},
+			},
+			ObjectType: typ,
+			IndexType:  indexType,
+		}
+	}
+
+	return typ, nil
+}
+
 // parseTSPrimaryType parses a primary TypeScript type.
 func (p *Parser) parseTSPrimaryType() (ast.TSNode, error) {
 	start := p.current.Pos
@@ -186,6 +262,9 @@ func (p *Parser) parseTSPrimaryType() (ast.TSNode, error) {
 		}, nil
 
 	case lexer.IDENT:
+		if p.current.Literal == "abstract" && p.peek.Type == lexer.NEW {
+			return p.parseTSConstructorType()
+		}
 		return p.parseTSTypeReference()
 
 	case lexer.LBRACE:
@@ -194,9 +273,12 @@ func (p *Parser) parseTSPrimaryType() (ast.TSNode, error) {
 	case lexer.LBRACK:
 		return p.parseTSTupleType()
 
-	case lexer.LPAREN:
+	case lexer.LSS:
 		return p.parseTSFunctionType()
 
+	case lexer.LPAREN:
+		return p.parseTSFunctionOrParenthesizedType()
+
 	case lexer.NEW:
 		return p.parseTSConstructorType()
 
@@ -252,6 +334,7 @@ func (p *Parser) parseTSTypeReference() (*ast.TSTypeReference, error) {
 			Range:    &ast.Range{start, p.current.Pos},
 		},
 		TypeName:       typeName,
+		TypeArguments:  typeParameters,
 		TypeParameters: typeParameters,
 	}, nil
 }
@@ -353,39 +436,26 @@ func (p *Parser) parseTSTypeElement() (ast.Node, error) {
 	}
 
 	// Parse property or method signature
-	readonly := p.consume(lexer.READONLY)
-
-	// Parse key
-	computed := false
-	var key ast.Expression
-	var err error
+	readonly := p.consumeModifier(lexer.READONLY)
 
-	if p.consume(lexer.LBRACK) {
-		computed = true
-		key, err = p.parseExpression()
-		if err != nil {
-			return nil, err
-		}
-		if err := p.expect(lexer.RBRACK); err != nil {
-			return nil, err
-		}
-	} else if p.current.Type == lexer.IDENT {
-		key = &ast.Identifier{
-			BaseNode: ast.BaseNode{
-				NodeType: ast.NodeTypeIdentifier.String(),
-				Range:    &ast.Range{p.current.Pos, p.current.End},
-			},
-			Name: p.current.Literal,
-		}
+	// Parse accessor signature (get/set)
+	kind := "method"
+	if !readonly && p.match(lexer.GET, lexer.SET) && p.canFollowModifier() {
+		kind = p.current.Literal
 		p.nextToken()
-	} else {
-		return nil, p.errorAtCurrent("expected property name")
+	}
+
+	// Parse key
+	computed := p.current.Type == lexer.LBRACK
+	key, err := p.parsePropertyKey()
+	if err != nil {
+		return nil, err
 	}
 
 	optional := p.consume(lexer.QUESTION)
 
 	// Check for method signature
-	if p.current.Type == lexer.LPAREN || p.current.Type == lexer.LSS {
+	if p.match(lexer.LPAREN, lexer.LSS) || kind != "method" {
 		// Method signature
 		var typeParameters *ast.TSTypeParameterDeclaration
 		if p.current.Type == lexer.LSS {
@@ -423,6 +493,7 @@ func (p *Parser) parseTSTypeElement() (ast.Node, error) {
 			Params:         params,
 			ReturnType:     returnType,
 			TypeParameters: typeParameters,
+			Kind:           kind,
 		}, nil
 	}
 
@@ -594,36 +665,10 @@ func (p *Parser) parseTSTupleType() (*ast.TSTupleType, error) {
 	elementTypes := []ast.TSNode{}
 
 	for !p.match(lexer.RBRACK) && !p.isAtEnd() {
-		// Handle rest element
-		if p.consume(lexer.ELLIPSIS) {
-			elemType, err := p.parseTSType()
-			if err != nil {
-				return nil, err
-			}
-			elementTypes = append(elementTypes, &ast.TSRestType{
-				BaseNode: ast.BaseNode{
-					NodeType: ast.NodeTypeTSRestType.String(),
-				},
-				TypeAnnotation: elemType,
-			})
-			break
-		}
-
-		elemType, err := p.parseTSType()
+		elemType, err := p.parseTSTupleElement()
 		if err != nil {
 			return nil, err
 		}
-
-		// Check for optional element
-		if p.consume(lexer.QUESTION) {
-			elemType = &ast.TSOptionalType{
-				BaseNode: ast.BaseNode{
-					NodeType: ast.NodeTypeTSOptionalType.String(),
-				},
-				TypeAnnotation: elemType,
-			}
-		}
-
 		elementTypes = append(elementTypes, elemType)
 
 		if !p.consume(lexer.COMMA) {
@@ -644,6 +689,114 @@ func (p *Parser) parseTSTupleType() (*ast.TSTupleType, error) {
 	}, nil
 }
 
+// parseTSTupleElement parses a single tuple element. Elements may be named
+// (start: number), optional (T? or end?: number) or rest elements (...T[] or
+// ...rest: T[]). Rest elements may appear anywhere, which allows variadic
+// tuple types such as [...T, ...U].
+func (p *Parser) parseTSTupleElement() (ast.TSNode, error) {
+	start := p.current.Pos
+	rest := p.consume(lexer.ELLIPSIS)
+
+	var elemType ast.TSNode
+	if p.isStartOfNamedTupleMember() {
+		label := &ast.Identifier{
+			BaseNode: ast.BaseNode{
+				NodeType: ast.NodeTypeIdentifier.String(),
+				Range:    &ast.Range{p.current.Pos, p.current.End},
+			},
+			Name: p.current.Literal,
+		}
+		p.nextToken()
+
+		optional := p.consume(lexer.QUESTION)
+		if err := p.expect(lexer.COLON); err != nil {
+			return nil, err
+		}
+
+		typ, err := p.parseTSType()
+		if err != nil {
+			return nil, err
+		}
+
+		elemType = &ast.TSNamedTupleMember{
+			BaseNode: ast.BaseNode{
+				NodeType: ast.NodeTypeTSNamedTupleMember.String(),
+				Range:    &ast.Range{start, p.current.Pos},
+			},
+			Label:       label,
+			ElementType: typ,
+			Optional:    optional,
+		}
+	} else {
+		typ, err := p.parseTSType()
+		if err != nil {
+			return nil, err
+		}
+		elemType = typ
+
+		// Check for optional element
+		if !rest && p.consume(lexer.QUESTION) {
+			elemType = &ast.TSOptionalType{
+				BaseNode: ast.BaseNode{
+					NodeType: ast.NodeTypeTSOptionalType.String(),
+					Range:    &ast.Range{start, p.current.Pos},
+				},
+				TypeAnnotation: typ,
+			}
+		}
+	}
+
+	if rest {
+		elemType = &ast.TSRestType{
+			BaseNode: ast.BaseNode{
+				NodeType: ast.NodeTypeTSRestType.String(),
+				Range:    &ast.Range{start, p.current.Pos},
+			},
+			TypeAnnotation: elemType,
+		}
+	}
+
+	return elemType, nil
+}
+
+// isStartOfNamedTupleMember reports whether the current token is the label of
+// a named tuple member, i.e. an identifier followed by ':' or '?:'.
+func (p *Parser) isStartOfNamedTupleMember() bool {
+	if !isIdentifierName(p.current.Type) {
+		return false
+	}
+	if p.peek.Type == lexer.COLON {
+		return true
+	}
+	if p.peek.Type != lexer.QUESTION {
+		return false
+	}
+	return p.lookahead(func() bool {
+		p.nextToken() // consume label
+		p.nextToken() // consume '?'
+		return p.current.Type == lexer.COLON
+	})
+}
+
+// parseTSFunctionOrParenthesizedType parses a type starting with '(', which is
+// either a function type (x: string) => void or a parenthesized type (A | B).
+// Like typescript-estree, parenthesized types are not kept in the AST.
+func (p *Parser) parseTSFunctionOrParenthesizedType() (ast.TSNode, error) {
+	if fn, ok := tryParse(p, p.parseTSFunctionType); ok {
+		return fn, nil
+	}
+
+	p.nextToken() // consume '('
+	typ, err := p.parseTSType()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(lexer.RPAREN); err != nil {
+		return nil, err
+	}
+	return typ, nil
+}
+
 // parseTSFunctionType parses a function type (x: string) => string.
 func (p *Parser) parseTSFunctionType() (*ast.TSFunctionType, error) {
 	start := p.current.Pos
@@ -687,10 +840,19 @@ func (p *Parser) parseTSFunctionType() (*ast.TSFunctionType, error) {
 	}, nil
 }
 
-// parseTSConstructorType parses a constructor type new (x: string) => Type.
+// parseTSConstructorType parses a constructor type new (x: string) => Type
+// or an abstract constructor type abstract new () => Type.
 func (p *Parser) parseTSConstructorType() (*ast.TSConstructorType, error) {
 	start := p.current.Pos
-	p.nextToken() // consume 'new'
+
+	abstract := p.current.Type == lexer.IDENT && p.current.Literal == "abstract"
+	if abstract {
+		p.nextToken() // consume 'abstract'
+	}
+
+	if err := p.expect(lexer.NEW); err != nil {
+		return nil, err
+	}
 
 	var typeParameters *ast.TSTypeParameterDeclaration
 	var err error
@@ -728,6 +890,7 @@ func (p *Parser) parseTSConstructorType() (*ast.TSConstructorType, error) {
 		Params:         params,
 		ReturnType:     returnType,
 		TypeParameters: typeParameters,
+		Abstract:       abstract,
 	}, nil
 }
 
@@ -807,6 +970,7 @@ func (p *Parser) parseTSImportType() (*ast.TSImportType, error) {
 		},
 		Argument:       argument,
 		Qualifier:      qualifier,
+		TypeArguments:  typeParameters,
 		TypeParameters: typeParameters,
 	}, nil
 }
@@ -867,7 +1031,7 @@ func (p *Parser) parseTSRestParameter() (ast.Pattern, error) {
 
 // parseTSRegularParameter parses a regular function parameter with TypeScript features
 func (p *Parser) parseTSRegularParameter() (ast.Pattern, error) {
-	param, err := p.parseBindingPattern()
+	param, err := p.parseParamBindingPattern()
 	if err != nil {
 		return nil, err
 	}
@@ -945,7 +1109,7 @@ func (p *Parser) parseTSTypeParameters() (*ast.TSTypeParameterDeclaration, error
 		}
 	}
 
-	if err := p.expect(lexer.GTR); err != nil {
+	if err := p.expectGreaterThan(); err != nil {
 		return nil, err
 	}
 
@@ -1025,7 +1189,7 @@ func (p *Parser) parseTSTypeArguments() (*ast.TSTypeParameterInstantiation, erro
 		}
 	}
 
-	if err := p.expect(lexer.GTR); err != nil {
+	if err := p.expectGreaterThan(); err != nil {
 		return nil, err
 	}
 
@@ -1155,6 +1319,7 @@ func (p *Parser) parseTSInterfaceHeritage() (*ast.TSInterfaceHeritage, error) {
 			Range:    &ast.Range{start, p.current.Pos},
 		},
 		Expression:     expression,
+		TypeArguments:  typeParameters,
 		TypeParameters: typeParameters,
 	}, nil
 }
@@ -1449,6 +1614,7 @@ func (p *Parser) parseTSClassImplements() (*ast.TSClassImplements, error) {
 			Range:    &ast.Range{start, p.current.Pos},
 		},
 		Expression:     expression,
+		TypeArguments:  typeParameters,
 		TypeParameters: typeParameters,
 	}, nil
 }
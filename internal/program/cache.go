@@ -2,25 +2,58 @@ package program
 
 import (
 	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 const (
 	// DefaultCacheExpiration is the default expiration time for cached programs.
 	DefaultCacheExpiration = 5 * time.Minute
+
+	// DefaultPollInterval is the default minimum time between two checks of
+	// the paths a cached program depends on.
+	DefaultPollInterval = time.Second
 )
 
 // ProgramCache provides caching of TypeScript programs for performance optimization.
+// Programs are cached by tsconfig and by the options they were created with,
+// so that programs created from one tsconfig with different options, such as
+// AllowJS, are cached apart.
+//
+// A cached program is dropped when it expires, and when any of the files and
+// directories it depends on, as reported by Program.WatchedPaths, changes:
+// when a tsconfig it reads or one of its root files is edited, or files are
+// added to or removed from the directories its root files were found in. Without a watcher, Get checks
+// these paths, at most once every poll interval for each program; see
+// SetWatcher and SetPollInterval.
 type ProgramCache struct {
-	// programs maps tsconfig paths to cached programs
-	programs map[string]*CachedProgram
+	// programs maps the keys of the options programs were created with to
+	// the cached programs
+	programs map[cacheKey]*CachedProgram
+
+	// creating maps the keys of options to the programs being created with
+	// them by GetOrCreate
+	creating map[cacheKey]*creation
+
+	// watcher, if not nil, reports changes to the paths cached programs
+	// depend on
+	watcher Watcher
+
+	// watched counts the cached programs depending on each path added to
+	// the watcher
+	watched map[string]int
 
 	// mu protects concurrent access
 	mu sync.RWMutex
 
 	// maxAge is the maximum age of cached programs before they expire
 	maxAge time.Duration
+
+	// pollInterval is the minimum time between two checks of the polled
+	// paths of a cached program
+	pollInterval time.Duration
 }
 
 // CachedProgram wraps a program with cache metadata.
@@ -28,56 +61,179 @@ type CachedProgram struct {
 	Program      *Program
 	CachedAt     time.Time
 	TSConfigPath string
+
+	// stamps holds the state of each path the program depends on, taken
+	// at stampedAt, just before it was cached
+	stamps    map[string]fileStamp
+	stampedAt time.Time
+
+	// polled lists the paths checked by Get: all of them without a watcher,
+	// and those the watcher could not watch with one
+	polled []string
+
+	// polledAt is when the polled paths were last checked, in Unix
+	// nanoseconds
+	polledAt atomic.Int64
+}
+
+// cacheKey identifies the options of the programs a ProgramCache holds:
+// programs created with the same tsconfig and options are interchangeable.
+// Lists are joined, as slices cannot be compared.
+type cacheKey struct {
+	tsconfigPath        string
+	rootDir             string
+	sourceFiles         string
+	allowJS             bool
+	extraFileExtensions string
+}
+
+// newCacheKey returns the key of programs created with opts.
+func newCacheKey(opts *ProgramOptions) (cacheKey, error) {
+	tsconfigPath, err := filepath.Abs(opts.TSConfigPath)
+	if err != nil {
+		return cacheKey{}, err
+	}
+	return cacheKey{
+		tsconfigPath:        tsconfigPath,
+		rootDir:             opts.RootDir,
+		sourceFiles:         strings.Join(opts.SourceFiles, "\x00"),
+		allowJS:             opts.AllowJS,
+		extraFileExtensions: strings.Join(opts.ExtraFileExtensions, "\x00"),
+	}, nil
+}
+
+// creation is a program being created by GetOrCreate, which other calls with
+// the same options wait for.
+type creation struct {
+	done    chan struct{}
+	program *Program
+	err     error
 }
 
 // NewProgramCache creates a new program cache with the specified max age.
 // If maxAge is 0, programs are cached indefinitely.
 func NewProgramCache(maxAge time.Duration) *ProgramCache {
 	return &ProgramCache{
-		programs: make(map[string]*CachedProgram),
-		maxAge:   maxAge,
+		programs:     make(map[cacheKey]*CachedProgram),
+		creating:     make(map[cacheKey]*creation),
+		watched:      make(map[string]int),
+		maxAge:       maxAge,
+		pollInterval: DefaultPollInterval,
 	}
 }
 
-// Get retrieves a cached program for the given tsconfig path.
-// Returns nil if the program is not in cache or has expired.
-func (c *ProgramCache) Get(tsconfigPath string) *Program {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// SetPollInterval sets the minimum time between two checks of the paths a
+// cached program depends on, when they are polled rather than watched. A
+// change is noticed by the first Get after the interval has passed since
+// the last check; with an interval of 0, every Get checks.
+func (c *ProgramCache) SetPollInterval(interval time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pollInterval = interval
+}
 
-	absPath, err := filepath.Abs(tsconfigPath)
+// SetWatcher makes the cache rely on w to learn of changes to the paths its
+// programs depend on, rather than checking them on every Get. The paths of
+// programs already cached are added to w. The cache stops receiving from w
+// when its Events channel is closed.
+func (c *ProgramCache) SetWatcher(w Watcher) {
+	c.mu.Lock()
+	c.watcher = w
+	c.watched = make(map[string]int)
+	for _, cached := range c.programs {
+		cached.polled = c.watch(cached.Program.WatchedPaths())
+	}
+	c.mu.Unlock()
+
+	go func() {
+		for path := range w.Events() {
+			c.Invalidate(path)
+		}
+	}()
+}
+
+// Get retrieves the program cached for the tsconfig and options of opts.
+// Returns nil if the program is not in cache, has expired, or depends on a
+// file or directory found to have changed since it was cached; such programs
+// are removed.
+func (c *ProgramCache) Get(opts *ProgramOptions) *Program {
+	key, err := newCacheKey(opts)
 	if err != nil {
 		return nil
 	}
 
-	cached, ok := c.programs[absPath]
+	c.mu.RLock()
+	cached, ok := c.programs[key]
+	interval := c.pollInterval
+	c.mu.RUnlock()
 	if !ok {
 		return nil
 	}
 
-	// Check if expired
-	if c.maxAge > 0 && time.Since(cached.CachedAt) > c.maxAge {
-		// Expired, will be cleaned up later
+	if c.expired(cached) || (cached.pollDue(interval) && cached.changed()) {
+		c.mu.Lock()
+		if c.programs[key] == cached {
+			c.remove(key)
+		}
+		c.mu.Unlock()
 		return nil
 	}
 
 	return cached.Program
 }
 
-// Set caches a program for the given tsconfig path.
-func (c *ProgramCache) Set(tsconfigPath string, program *Program) {
+// Set caches a program created with opts, replacing any program cached for
+// the same tsconfig and options, and removes expired programs.
+func (c *ProgramCache) Set(opts *ProgramOptions, program *Program) {
+	key, err := newCacheKey(opts)
+	if err != nil {
+		return
+	}
+
+	// Stamp the paths before taking the lock, as stamping reads files
+	paths := program.WatchedPaths()
+	stampedAt := time.Now()
+	stamps := make(map[string]fileStamp, len(paths))
+	for _, path := range paths {
+		stamps[path] = stampPath(path)
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	absPath, err := filepath.Abs(tsconfigPath)
+	c.remove(key)
+	c.cleanExpired()
+	cached := &CachedProgram{
+		Program:      program,
+		CachedAt:     time.Now(),
+		TSConfigPath: key.tsconfigPath,
+		stamps:       stamps,
+		stampedAt:    stampedAt,
+		polled:       c.watch(paths),
+	}
+	cached.polledAt.Store(stampedAt.UnixNano())
+	c.programs[key] = cached
+}
+
+// Invalidate removes the cached programs that depend on the file or
+// directory at path, or on the directory containing it.
+func (c *ProgramCache) Invalidate(path string) {
+	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return
 	}
+	dir := filepath.Dir(absPath)
 
-	c.programs[absPath] = &CachedProgram{
-		Program:      program,
-		CachedAt:     time.Now(),
-		TSConfigPath: absPath,
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, cached := range c.programs {
+		_, dependsOnPath := cached.stamps[absPath]
+		_, dependsOnDir := cached.stamps[dir]
+		if dependsOnPath || dependsOnDir {
+			c.remove(key)
+		}
 	}
 }
 
@@ -86,25 +242,107 @@ func (c *ProgramCache) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.programs = make(map[string]*CachedProgram)
+	for key := range c.programs {
+		c.remove(key)
+	}
 }
 
 // CleanExpired removes expired programs from the cache.
 func (c *ProgramCache) CleanExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cleanExpired()
+}
+
+// cleanExpired removes expired programs. The caller must hold c.mu.
+func (c *ProgramCache) cleanExpired() {
 	if c.maxAge == 0 {
 		// No expiration
 		return
 	}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	for key, cached := range c.programs {
+		if c.expired(cached) {
+			c.remove(key)
+		}
+	}
+}
+
+// expired reports whether cached is older than the maximum age.
+func (c *ProgramCache) expired(cached *CachedProgram) bool {
+	return c.maxAge > 0 && time.Since(cached.CachedAt) > c.maxAge
+}
+
+// pollDue reports whether the polled paths are due to be checked, interval
+// after they last were, and if so records the check. Of concurrent callers,
+// one checks and the others use the program meanwhile.
+func (cached *CachedProgram) pollDue(interval time.Duration) bool {
+	if len(cached.polled) == 0 {
+		return false
+	}
+	now := time.Now().UnixNano()
+	last := cached.polledAt.Load()
+	if now-last < int64(interval) {
+		return false
+	}
+	return cached.polledAt.CompareAndSwap(last, now)
+}
+
+// changed reports whether any of the polled paths has changed since the
+// program was cached.
+func (cached *CachedProgram) changed() bool {
+	for _, path := range cached.polled {
+		if cached.stamps[path].changed(path, cached.stampedAt) {
+			return true
+		}
+	}
+	return false
+}
+
+// remove removes the program cached with key, if any, and stops watching
+// the paths no other cached program depends on. The caller must hold c.mu.
+func (c *ProgramCache) remove(key cacheKey) {
+	cached, ok := c.programs[key]
+	if !ok {
+		return
+	}
+	delete(c.programs, key)
+
+	if c.watcher == nil {
+		return
+	}
+	for path := range cached.stamps {
+		if c.watched[path] == 0 {
+			continue
+		}
+		c.watched[path]--
+		if c.watched[path] == 0 {
+			delete(c.watched, path)
+			_ = c.watcher.Remove(path) //nolint:errcheck // The path is no longer of interest either way
+		}
+	}
+}
 
-	now := time.Now()
-	for path, cached := range c.programs {
-		if now.Sub(cached.CachedAt) > c.maxAge {
-			delete(c.programs, path)
+// watch adds paths to the watcher, if any, and returns those that must be
+// polled instead: all of them without a watcher, and those it failed to add
+// with one. The caller must hold c.mu.
+func (c *ProgramCache) watch(paths []string) []string {
+	if c.watcher == nil {
+		return paths
+	}
+
+	var polled []string
+	for _, path := range paths {
+		if c.watched[path] == 0 {
+			if err := c.watcher.Add(path); err != nil {
+				polled = append(polled, path)
+				continue
+			}
 		}
+		c.watched[path]++
 	}
+	return polled
 }
 
 // Size returns the number of cached programs.
@@ -116,26 +354,50 @@ func (c *ProgramCache) Size() int {
 }
 
 // GetOrCreate retrieves a cached program or creates a new one if not found.
+// Concurrent calls with the same tsconfig and options create a single
+// program, which they all return.
 func (c *ProgramCache) GetOrCreate(opts *ProgramOptions) (*Program, error) {
-	// Try to get from cache first
-	if opts.TSConfigPath != "" {
-		if cached := c.Get(opts.TSConfigPath); cached != nil {
-			return cached, nil
-		}
+	if opts.TSConfigPath == "" {
+		return CreateProgram(opts)
 	}
 
-	// Create new program
-	program, err := CreateProgram(opts)
+	// Try to get from cache first
+	if cached := c.Get(opts); cached != nil {
+		return cached, nil
+	}
+	key, err := newCacheKey(opts)
 	if err != nil {
-		return nil, err
+		return CreateProgram(opts)
 	}
 
-	// Cache it
-	if opts.TSConfigPath != "" {
-		c.Set(opts.TSConfigPath, program)
+	c.mu.Lock()
+	if cached, ok := c.programs[key]; ok && !c.expired(cached) {
+		// Cached by a call that finished since Get
+		c.mu.Unlock()
+		return cached.Program, nil
+	}
+	if pending, ok := c.creating[key]; ok {
+		c.mu.Unlock()
+		<-pending.done
+		return pending.program, pending.err
 	}
+	pending := &creation{done: make(chan struct{})}
+	c.creating[key] = pending
+	c.mu.Unlock()
+
+	// Create new program
+	pending.program, pending.err = CreateProgram(opts)
+	if pending.err == nil {
+		// Cache it
+		c.Set(opts, pending.program)
+	}
+
+	c.mu.Lock()
+	delete(c.creating, key)
+	c.mu.Unlock()
+	close(pending.done)
 
-	return program, nil
+	return pending.program, pending.err
 }
 
 // GlobalCache is the default global program cache with default expiration.
@@ -1,8 +1,11 @@
 package program
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 )
@@ -20,7 +23,7 @@ func TestProgramCache(t *testing.T) {
 	cache := NewProgramCache(5 * time.Minute)
 
 	// Initially empty
-	if cached := cache.Get(tsconfigPath); cached != nil {
+	if cached := cache.Get(&ProgramOptions{TSConfigPath: tsconfigPath}); cached != nil {
 		t.Error("Expected cache to be empty initially")
 	}
 
@@ -35,10 +38,10 @@ func TestProgramCache(t *testing.T) {
 		t.Fatalf("Failed to create program: %v", err)
 	}
 
-	cache.Set(tsconfigPath, program)
+	cache.Set(&ProgramOptions{TSConfigPath: tsconfigPath}, program)
 
 	// Should now retrieve it
-	cached := cache.Get(tsconfigPath)
+	cached := cache.Get(&ProgramOptions{TSConfigPath: tsconfigPath})
 	if cached == nil {
 		t.Error("Expected to retrieve cached program")
 	}
@@ -73,10 +76,10 @@ func TestProgramCacheExpiration(t *testing.T) {
 		t.Fatalf("Failed to create program: %v", err)
 	}
 
-	cache.Set(tsconfigPath, program)
+	cache.Set(&ProgramOptions{TSConfigPath: tsconfigPath}, program)
 
 	// Should retrieve immediately
-	if cached := cache.Get(tsconfigPath); cached == nil {
+	if cached := cache.Get(&ProgramOptions{TSConfigPath: tsconfigPath}); cached == nil {
 		t.Error("Expected to retrieve cached program immediately")
 	}
 
@@ -84,7 +87,7 @@ func TestProgramCacheExpiration(t *testing.T) {
 	time.Sleep(150 * time.Millisecond)
 
 	// Should now be expired
-	if cached := cache.Get(tsconfigPath); cached != nil {
+	if cached := cache.Get(&ProgramOptions{TSConfigPath: tsconfigPath}); cached != nil {
 		t.Error("Expected cached program to be expired")
 	}
 }
@@ -108,7 +111,7 @@ func TestProgramCacheClear(t *testing.T) {
 		t.Fatalf("Failed to create program: %v", err)
 	}
 
-	cache.Set(tsconfigPath, program)
+	cache.Set(&ProgramOptions{TSConfigPath: tsconfigPath}, program)
 
 	if cache.Size() != 1 {
 		t.Error("Expected cache to have 1 entry")
@@ -121,7 +124,7 @@ func TestProgramCacheClear(t *testing.T) {
 		t.Error("Expected cache to be empty after clear")
 	}
 
-	if cached := cache.Get(tsconfigPath); cached != nil {
+	if cached := cache.Get(&ProgramOptions{TSConfigPath: tsconfigPath}); cached != nil {
 		t.Error("Expected no cached program after clear")
 	}
 }
@@ -161,6 +164,67 @@ func TestProgramCacheGetOrCreate(t *testing.T) {
 	}
 }
 
+func TestProgramCacheGetOrCreateOptions(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"tsconfig.json": `{"include": ["src"]}`,
+		"src/a.ts":      "export const a = 1;\n",
+		"src/b.js":      "export const b = 1;\n",
+		"src/App.vue":   "<template></template>\n",
+	})
+	tsconfigPath := filepath.Join(tmpDir, "tsconfig.json")
+
+	cache := NewProgramCache(0)
+	tests := []struct {
+		name string
+		opts *ProgramOptions
+		want []string
+	}{
+		{
+			name: "default",
+			opts: &ProgramOptions{TSConfigPath: tsconfigPath},
+			want: []string{"a.ts"},
+		},
+		{
+			name: "allowJS",
+			opts: &ProgramOptions{TSConfigPath: tsconfigPath, AllowJS: true},
+			want: []string{"a.ts", "b.js"},
+		},
+		{
+			name: "extra file extensions",
+			opts: &ProgramOptions{TSConfigPath: tsconfigPath, ExtraFileExtensions: []string{".vue"}},
+			want: []string{"App.vue", "a.ts"},
+		},
+	}
+
+	// Programs of one tsconfig created with different options are cached
+	// apart, each under its own options
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := cache.GetOrCreate(tt.opts)
+			if err != nil {
+				t.Fatalf("Failed to get or create program: %v", err)
+			}
+
+			var got []string
+			for _, name := range program.RootNames {
+				got = append(got, filepath.Base(name))
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Expected root files %v, got %v", tt.want, got)
+			}
+
+			if cache.Get(tt.opts) != program {
+				t.Error("Expected the program to be cached under its options")
+			}
+		})
+	}
+	if cache.Size() != len(tests) {
+		t.Errorf("Expected %d cached programs, got %d", len(tests), cache.Size())
+	}
+}
+
 func TestCleanExpired(t *testing.T) {
 	tmpDir := t.TempDir()
 	tsconfigPath := filepath.Join(tmpDir, "tsconfig.json")
@@ -180,7 +244,7 @@ func TestCleanExpired(t *testing.T) {
 		t.Fatalf("Failed to create program: %v", err)
 	}
 
-	cache.Set(tsconfigPath, program)
+	cache.Set(&ProgramOptions{TSConfigPath: tsconfigPath}, program)
 
 	if cache.Size() != 1 {
 		t.Error("Expected cache to have 1 entry")
@@ -196,3 +260,280 @@ func TestCleanExpired(t *testing.T) {
 		t.Error("Expected cache to be empty after cleaning expired entries")
 	}
 }
+
+func TestProgramCacheInvalidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(t *testing.T, root string)
+		stale  bool
+	}{
+		{
+			name:   "unchanged",
+			change: func(t *testing.T, root string) {},
+		},
+		{
+			name: "tsconfig rewritten with the same contents",
+			change: func(t *testing.T, root string) {
+				writeFiles(t, root, map[string]string{"tsconfig.json": tsconfigForInvalidation})
+			},
+		},
+		{
+			name: "tsconfig edited",
+			change: func(t *testing.T, root string) {
+				writeFiles(t, root, map[string]string{"tsconfig.json": `{"extends": "./tsconfig.base.json", "include": ["src", "lib"]}`})
+			},
+			stale: true,
+		},
+		{
+			name: "extended config edited",
+			change: func(t *testing.T, root string) {
+				writeFiles(t, root, map[string]string{"tsconfig.base.json": `{"compilerOptions": {"target": "ES2022"}}`})
+			},
+			stale: true,
+		},
+		{
+			name: "root file edited",
+			change: func(t *testing.T, root string) {
+				writeFiles(t, root, map[string]string{"src/index.ts": "export const edited = 1;\n"})
+			},
+			stale: true,
+		},
+		{
+			name: "root file rewritten with the same contents",
+			change: func(t *testing.T, root string) {
+				writeFiles(t, root, map[string]string{"src/index.ts": "export {};\n"})
+			},
+		},
+		{
+			name: "file added",
+			change: func(t *testing.T, root string) {
+				writeFiles(t, root, map[string]string{"src/added.ts": "export {};\n"})
+			},
+			stale: true,
+		},
+		{
+			name: "file added in a new directory",
+			change: func(t *testing.T, root string) {
+				writeFiles(t, root, map[string]string{"src/feature/added.ts": "export {};\n"})
+			},
+			stale: true,
+		},
+		{
+			name: "file removed",
+			change: func(t *testing.T, root string) {
+				if err := os.Remove(filepath.Join(root, "src", "nested", "util.ts")); err != nil {
+					t.Fatalf("Failed to remove file: %v", err)
+				}
+			},
+			stale: true,
+		},
+		{
+			name: "missing include directory created",
+			change: func(t *testing.T, root string) {
+				writeFiles(t, root, map[string]string{"generated/types.ts": "export {};\n"})
+			},
+			stale: true,
+		},
+		{
+			name: "file outside the project added",
+			change: func(t *testing.T, root string) {
+				writeFiles(t, root, map[string]string{"scripts/build.ts": "export {};\n"})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			writeFiles(t, tmpDir, map[string]string{
+				"tsconfig.json":      tsconfigForInvalidation,
+				"tsconfig.base.json": `{"compilerOptions": {"target": "ES2020"}}`,
+				"src/index.ts":       "export {};\n",
+				"src/nested/util.ts": "export {};\n",
+			})
+
+			// Check the paths on every Get, as the test is about what a check
+			// notices rather than when it is made
+			cache := NewProgramCache(0)
+			cache.SetPollInterval(0)
+			opts := &ProgramOptions{TSConfigPath: filepath.Join(tmpDir, "tsconfig.json")}
+			program1, err := cache.GetOrCreate(opts)
+			if err != nil {
+				t.Fatalf("Failed to get or create program: %v", err)
+			}
+
+			tt.change(t, tmpDir)
+
+			program2, err := cache.GetOrCreate(opts)
+			if err != nil {
+				t.Fatalf("Failed to get or create program: %v", err)
+			}
+			if stale := program1 != program2; stale != tt.stale {
+				t.Errorf("Expected a new program: %v, got a new program: %v", tt.stale, stale)
+			}
+		})
+	}
+}
+
+// tsconfigForInvalidation is the tsconfig of TestProgramCacheInvalidation.
+const tsconfigForInvalidation = `{"extends": "./tsconfig.base.json", "include": ["src", "generated"]}`
+
+func TestFileStampChanged(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "a.ts")
+	write := func(contents string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	// A file modified long before it was stamped is checked by a stat
+	write("export const a = 1;\n")
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatalf("Failed to set modification time: %v", err)
+	}
+	stampedAt := time.Now()
+	stamp := stampPath(path)
+	if stamp.changed(path, stampedAt) {
+		t.Error("Expected an untouched file to be unchanged")
+	}
+
+	// Otherwise by its contents
+	write("export const a = 1;\n")
+	if stamp.changed(path, stampedAt) {
+		t.Error("Expected a file rewritten with the same contents to be unchanged")
+	}
+	write("export const a = 2;\n")
+	if !stamp.changed(path, stampedAt) {
+		t.Error("Expected an edited file to be changed")
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Failed to remove file: %v", err)
+	}
+	if !stamp.changed(path, stampedAt) {
+		t.Error("Expected a removed file to be changed")
+	}
+}
+
+func TestProgramCachePollInterval(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"tsconfig.json": `{"include": ["src"]}`,
+		"src/index.ts":  "export {};\n",
+	})
+	tsconfigPath := filepath.Join(tmpDir, "tsconfig.json")
+
+	cache := NewProgramCache(0)
+	cache.SetPollInterval(time.Hour)
+	program, err := cache.GetOrCreate(&ProgramOptions{TSConfigPath: tsconfigPath})
+	if err != nil {
+		t.Fatalf("Failed to get or create program: %v", err)
+	}
+
+	writeFiles(t, tmpDir, map[string]string{"src/added.ts": "export {};\n"})
+	if cache.Get(&ProgramOptions{TSConfigPath: tsconfigPath}) != program {
+		t.Error("Expected the program to be kept until the poll interval has passed")
+	}
+
+	cache.SetPollInterval(0)
+	if cache.Get(&ProgramOptions{TSConfigPath: tsconfigPath}) != nil {
+		t.Error("Expected the program to be dropped once its paths are checked")
+	}
+}
+
+func TestProgramCacheWatcher(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"tsconfig.json": `{"include": ["src"]}`,
+		"src/index.ts":  "export {};\n",
+	})
+	tsconfigPath := filepath.Join(tmpDir, "tsconfig.json")
+
+	watcher := NewPollingWatcher(10 * time.Millisecond)
+	defer watcher.Close()
+	cache := NewProgramCache(0)
+	cache.SetWatcher(watcher)
+
+	if _, err := cache.GetOrCreate(&ProgramOptions{TSConfigPath: tsconfigPath}); err != nil {
+		t.Fatalf("Failed to get or create program: %v", err)
+	}
+
+	writeFiles(t, tmpDir, map[string]string{"src/added.ts": "export {};\n"})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for cache.Size() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the watcher to invalidate the program")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	program, err := cache.GetOrCreate(&ProgramOptions{TSConfigPath: tsconfigPath})
+	if err != nil {
+		t.Fatalf("Failed to get or create program: %v", err)
+	}
+	if len(program.RootNames) != 2 {
+		t.Errorf("Expected the new program to have 2 root files, got %v", program.RootNames)
+	}
+}
+
+func TestProgramCacheGetOrCreateConcurrent(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"tsconfig.json": `{"include": ["src"]}`,
+		"src/index.ts":  "export {};\n",
+	})
+
+	cache := NewProgramCache(0)
+	opts := &ProgramOptions{TSConfigPath: filepath.Join(tmpDir, "tsconfig.json")}
+
+	programs := make([]*Program, 8)
+	var wg sync.WaitGroup
+	for i := range programs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			program, err := cache.GetOrCreate(opts)
+			if err != nil {
+				t.Errorf("Failed to get or create program: %v", err)
+			}
+			programs[i] = program
+		}(i)
+	}
+	wg.Wait()
+
+	for _, program := range programs[1:] {
+		if program != programs[0] {
+			t.Fatal("Expected concurrent calls to share one program")
+		}
+	}
+}
+
+func BenchmarkProgramCacheGet(b *testing.B) {
+	tmpDir := b.TempDir()
+	files := map[string]string{"tsconfig.json": `{"include": ["src"]}`}
+	for i := 0; i < 500; i++ {
+		files[fmt.Sprintf("src/dir%d/index.ts", i)] = "export {};\n"
+	}
+	writeFiles(b, tmpDir, files)
+	tsconfigPath := filepath.Join(tmpDir, "tsconfig.json")
+
+	for _, interval := range []time.Duration{0, DefaultPollInterval} {
+		b.Run(fmt.Sprintf("interval=%v", interval), func(b *testing.B) {
+			cache := NewProgramCache(0)
+			cache.SetPollInterval(interval)
+			if _, err := cache.GetOrCreate(&ProgramOptions{TSConfigPath: tsconfigPath}); err != nil {
+				b.Fatalf("Failed to get or create program: %v", err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if cache.Get(&ProgramOptions{TSConfigPath: tsconfigPath}) == nil {
+					b.Fatal("Expected the program to stay cached")
+				}
+			}
+		})
+	}
+}
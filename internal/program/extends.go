@@ -0,0 +1,140 @@
+package program
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// configDirTemplate is the template that, at the start of a path in a
+// configuration, stands for the directory of the configuration being
+// resolved, rather than that of the file the path is written in.
+const configDirTemplate = "${configDir}"
+
+// parseExtends decodes raw, the "extends" field of the tsconfig file at
+// path with contents data. The field may be a single string or, since
+// TypeScript 5.0, an array of strings.
+func parseExtends(path string, data []byte, raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		return []string{name}, nil
+	}
+
+	// Decode the whole file again, so that an error has the position of the
+	// field.
+	var list struct {
+		Extends []string `json:"extends"`
+	}
+	if err := unmarshalJSONC(path, data, &list); err != nil {
+		return nil, err
+	}
+	return list.Extends, nil
+}
+
+// extendsConditions are the conditions of package.json "exports" that tsc
+// matches when it looks up an extended configuration, besides "default".
+var extendsConditions = map[string]bool{
+	"node":    true,
+	"require": true,
+	"types":   true,
+}
+
+// resolveExtendsPath returns the path of the configuration named by an
+// extends entry of the configuration at configPath, as tsc resolves it.
+// Entries starting with "./" or "../", and absolute paths, are files relative
+// to the configuration, with ".json" added if needed. Any other entry names
+// a package, or a file in one, found in the nearest node_modules directory
+// that has it.
+func resolveExtendsPath(configPath, name string) (string, error) {
+	dir := filepath.Dir(configPath)
+
+	slashed := filepath.ToSlash(name)
+	if filepath.IsAbs(name) || strings.HasPrefix(slashed, "./") || strings.HasPrefix(slashed, "../") {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if resolved, ok := resolveJSONFile(path); ok {
+			return resolved, nil
+		}
+		return "", fmt.Errorf("file %s not found", path)
+	}
+
+	packageName, subpath := splitPackageName(slashed)
+	for {
+		if filepath.Base(dir) != "node_modules" {
+			packageDir := filepath.Join(dir, "node_modules", filepath.FromSlash(packageName))
+			if resolved, ok := resolveInPackage(packageDir, subpath); ok {
+				return resolved, nil
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("cannot find %s in any node_modules directory", name)
+		}
+		dir = parent
+	}
+}
+
+// resolveInPackage resolves subpath, which is empty for the package itself,
+// in the package at packageDir. A package with "exports" only exposes the
+// files it exports. Otherwise the package itself resolves to the file named
+// by the "tsconfig" field of its package.json, or else its tsconfig.json.
+func resolveInPackage(packageDir, subpath string) (string, bool) {
+	if info, err := os.Stat(packageDir); err != nil || !info.IsDir() {
+		return "", false
+	}
+
+	pkg := readPackageJSON(packageDir)
+	if pkg != nil && pkg.Exports != nil {
+		target, ok := resolveExports(pkg.Exports, subpath, extendsConditions)
+		if !ok {
+			return "", false
+		}
+		return resolveJSONFile(filepath.Join(packageDir, filepath.FromSlash(target)))
+	}
+
+	if subpath == "" {
+		return resolveDirectory(packageDir, pkg)
+	}
+
+	path := filepath.Join(packageDir, filepath.FromSlash(subpath))
+	if resolved, ok := resolveJSONFile(path); ok {
+		return resolved, true
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return resolveDirectory(path, readPackageJSON(path))
+	}
+	return "", false
+}
+
+// resolveDirectory resolves a directory to the configuration named by the
+// "tsconfig" field of its package.json, which may be nil, or its
+// tsconfig.json.
+func resolveDirectory(dir string, pkg *packageJSON) (string, bool) {
+	if pkg != nil && pkg.TSConfig != "" {
+		if resolved, ok := resolveJSONFile(filepath.Join(dir, filepath.FromSlash(pkg.TSConfig))); ok {
+			return resolved, true
+		}
+	}
+	return resolveJSONFile(filepath.Join(dir, "tsconfig.json"))
+}
+
+// resolveJSONFile returns path if it is a file, or else path with ".json"
+// added if that is.
+func resolveJSONFile(path string) (string, bool) {
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		return path, true
+	}
+	if !strings.HasSuffix(path, ".json") {
+		if info, err := os.Stat(path + ".json"); err == nil && !info.IsDir() {
+			return path + ".json", true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,305 @@
+package program
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeFiles writes files, by slash-separated path relative to root.
+func writeFiles(t testing.TB, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestResolveTSConfig_ExtendsArray(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"strict.json":   `{ "compilerOptions": { "target": "ES2015", "strict": true } }`,
+		"modern.json":   `{ "compilerOptions": { "target": "ES2022" }, "include": ["lib"] }`,
+		"tsconfig.json": `{ "extends": ["./strict.json", "./modern"], "compilerOptions": { "module": "esnext" } }`,
+	})
+
+	config, err := ResolveTSConfig(filepath.Join(tmpDir, "tsconfig.json"))
+	if err != nil {
+		t.Fatalf("Failed to resolve tsconfig: %v", err)
+	}
+
+	if config.CompilerOptions.Target != "ES2022" {
+		t.Errorf("Expected target ES2022 from the later base, got %s", config.CompilerOptions.Target)
+	}
+	if config.CompilerOptions.Strict == nil || !*config.CompilerOptions.Strict {
+		t.Error("Expected strict true from the earlier base")
+	}
+	if config.CompilerOptions.Module != "esnext" {
+		t.Errorf("Expected module esnext, got %s", config.CompilerOptions.Module)
+	}
+	if !slices.Equal(config.Include, []string{"lib"}) {
+		t.Errorf("Expected include [lib], got %v", config.Include)
+	}
+}
+
+func TestResolveTSConfig_ExtendsPackages(t *testing.T) {
+	// Each configuration sets the target to its own name, to show which one
+	// was found.
+	tests := []struct {
+		name    string
+		files   map[string]string
+		extends string
+		want    string
+	}{
+		{
+			name:    "package tsconfig.json",
+			files:   map[string]string{"node_modules/base/tsconfig.json": `{ "compilerOptions": { "target": "base" } }`},
+			extends: "base",
+			want:    "base",
+		},
+		{
+			name:    "scoped package subpath",
+			files:   map[string]string{"node_modules/@tsconfig/node18/tsconfig.json": `{ "compilerOptions": { "target": "node18" } }`},
+			extends: "@tsconfig/node18/tsconfig.json",
+			want:    "node18",
+		},
+		{
+			name:    "subpath without extension",
+			files:   map[string]string{"node_modules/@tsconfig/node18/tsconfig.json": `{ "compilerOptions": { "target": "node18" } }`},
+			extends: "@tsconfig/node18/tsconfig",
+			want:    "node18",
+		},
+		{
+			name: "subpath directory",
+			files: map[string]string{
+				"node_modules/configs/strict/tsconfig.json": `{ "compilerOptions": { "target": "strict" } }`,
+			},
+			extends: "configs/strict",
+			want:    "strict",
+		},
+		{
+			name: "package.json tsconfig field",
+			files: map[string]string{
+				"node_modules/base/package.json":          `{ "name": "base", "tsconfig": "./configs/base" }`,
+				"node_modules/base/configs/base.json":     `{ "compilerOptions": { "target": "field" } }`,
+				"node_modules/base/tsconfig.json":         `{ "compilerOptions": { "target": "root" } }`,
+				"node_modules/base/configs/unused.json":   `{}`,
+				"node_modules/base/configs/tsconfig.json": `{}`,
+			},
+			extends: "base",
+			want:    "field",
+		},
+		{
+			name: "exports conditions",
+			files: map[string]string{
+				"node_modules/base/package.json": `{ "exports": { ".": { "import": "./esm.json", "require": "./cjs.json" } } }`,
+				"node_modules/base/esm.json":     `{ "compilerOptions": { "target": "esm" } }`,
+				"node_modules/base/cjs.json":     `{ "compilerOptions": { "target": "cjs" } }`,
+			},
+			extends: "base",
+			want:    "cjs",
+		},
+		{
+			name: "exports subpath",
+			files: map[string]string{
+				"node_modules/base/package.json":        `{ "exports": { ".": "./index.json", "./strict": "./configs/strict.json" } }`,
+				"node_modules/base/configs/strict.json": `{ "compilerOptions": { "target": "strict" } }`,
+			},
+			extends: "base/strict",
+			want:    "strict",
+		},
+		{
+			name: "exports pattern",
+			files: map[string]string{
+				"node_modules/base/package.json":      `{ "exports": { "./*": "./configs/*.json", "./lib/*": "./lib/*.json" } }`,
+				"node_modules/base/configs/node.json": `{ "compilerOptions": { "target": "pattern" } }`,
+				"node_modules/base/lib/node.json":     `{ "compilerOptions": { "target": "longest" } }`,
+			},
+			extends: "base/lib/node",
+			want:    "longest",
+		},
+		{
+			name: "parent node_modules",
+			files: map[string]string{
+				"../node_modules/base/tsconfig.json": `{ "compilerOptions": { "target": "parent" } }`,
+			},
+			extends: "base",
+			want:    "parent",
+		},
+		{
+			name: "nearest node_modules",
+			files: map[string]string{
+				"../node_modules/base/tsconfig.json": `{ "compilerOptions": { "target": "parent" } }`,
+				"node_modules/base/tsconfig.json":    `{ "compilerOptions": { "target": "nearest" } }`,
+			},
+			extends: "base",
+			want:    "nearest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			project := filepath.Join(root, "project")
+			writeFiles(t, project, tt.files)
+			writeFiles(t, project, map[string]string{
+				"tsconfig.json": `{ "extends": "` + tt.extends + `" }`,
+			})
+
+			config, err := ResolveTSConfig(filepath.Join(project, "tsconfig.json"))
+			if err != nil {
+				t.Fatalf("Failed to resolve tsconfig: %v", err)
+			}
+			if config.CompilerOptions.Target != tt.want {
+				t.Errorf("Expected target %s, got %s", tt.want, config.CompilerOptions.Target)
+			}
+		})
+	}
+}
+
+func TestResolveTSConfig_RebasesInheritedPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"configs/base.json": `{
+			"compilerOptions": {
+				"outDir": "../dist",
+				"typeRoots": ["./types"],
+				"paths": { "@/*": ["./src/*"] }
+			},
+			"include": ["../src/**/*"],
+			"exclude": ["/abs/**"],
+			"references": [{ "path": "../other" }]
+		}`,
+		"tsconfig.json": `{ "extends": "./configs/base.json", "compilerOptions": { "rootDir": "src" } }`,
+	})
+
+	config, err := ResolveTSConfig(filepath.Join(tmpDir, "tsconfig.json"))
+	if err != nil {
+		t.Fatalf("Failed to resolve tsconfig: %v", err)
+	}
+
+	options := config.CompilerOptions
+	if want := filepath.Join(tmpDir, "dist"); options.OutDir != want {
+		t.Errorf("Expected outDir %s, got %s", want, options.OutDir)
+	}
+	if want := filepath.Join(tmpDir, "src"); options.RootDir != want {
+		t.Errorf("Expected rootDir %s, got %s", want, options.RootDir)
+	}
+	if want := []string{filepath.Join(tmpDir, "configs", "types")}; !slices.Equal(options.TypeRoots, want) {
+		t.Errorf("Expected typeRoots %v, got %v", want, options.TypeRoots)
+	}
+	if want := filepath.Join(tmpDir, "configs"); options.PathsBasePath != want {
+		t.Errorf("Expected paths base path %s, got %s", want, options.PathsBasePath)
+	}
+	if !slices.Equal(config.Include, []string{"src/**/*"}) {
+		t.Errorf("Expected include [src/**/*], got %v", config.Include)
+	}
+	if !slices.Equal(config.Exclude, []string{"/abs/**"}) {
+		t.Errorf("Expected absolute exclude to be kept, got %v", config.Exclude)
+	}
+	if len(config.References) != 0 {
+		t.Errorf("Expected references not to be inherited, got %v", config.References)
+	}
+}
+
+func TestResolveTSConfig_ConfigDirTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"node_modules/shared/tsconfig.json": `{
+			"compilerOptions": {
+				"outDir": "${configDir}/dist",
+				"paths": { "~/*": ["${configDir}/src/*"] }
+			},
+			"include": ["${configDir}/src"]
+		}`,
+		"app/tsconfig.json": `{ "extends": "shared" }`,
+	})
+
+	config, err := ResolveTSConfig(filepath.Join(tmpDir, "app", "tsconfig.json"))
+	if err != nil {
+		t.Fatalf("Failed to resolve tsconfig: %v", err)
+	}
+
+	app := filepath.Join(tmpDir, "app")
+	if want := filepath.Join(app, "dist"); config.CompilerOptions.OutDir != want {
+		t.Errorf("Expected outDir %s, got %s", want, config.CompilerOptions.OutDir)
+	}
+	if want := []string{filepath.Join(app, "src")}; !slices.Equal(config.Include, want) {
+		t.Errorf("Expected include %v, got %v", want, config.Include)
+	}
+	if want := []string{filepath.Join(app, "src", "*")}; !slices.Equal(config.CompilerOptions.Paths["~/*"], want) {
+		t.Errorf("Expected paths %v, got %v", want, config.CompilerOptions.Paths["~/*"])
+	}
+}
+
+func TestResolveTSConfig_ExtendsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{
+			name: "cycle",
+			files: map[string]string{
+				"tsconfig.json": `{ "extends": "./a.json" }`,
+				"a.json":        `{ "extends": "./b.json" }`,
+				"b.json":        `{ "extends": ["./c.json", "./a.json"] }`,
+				"c.json":        `{}`,
+			},
+			want: []string{"circularity detected", "a.json -> ", "b.json -> ", "a.json"},
+		},
+		{
+			name:  "self",
+			files: map[string]string{"tsconfig.json": `{ "extends": "./tsconfig.json" }`},
+			want:  []string{"circularity detected", "tsconfig.json -> "},
+		},
+		{
+			name:  "missing file",
+			files: map[string]string{"tsconfig.json": `{ "extends": "./missing" }`},
+			want:  []string{"./missing", "not found"},
+		},
+		{
+			name:  "missing package",
+			files: map[string]string{"tsconfig.json": `{ "extends": "@tsconfig/missing" }`},
+			want:  []string{"@tsconfig/missing", "node_modules"},
+		},
+		{
+			name: "not exported",
+			files: map[string]string{
+				"tsconfig.json":                   `{ "extends": "base/tsconfig.json" }`,
+				"node_modules/base/package.json":  `{ "exports": { ".": "./tsconfig.json" } }`,
+				"node_modules/base/tsconfig.json": `{}`,
+			},
+			want: []string{"base/tsconfig.json"},
+		},
+		{
+			name:  "wrong type",
+			files: map[string]string{"tsconfig.json": `{ "extends": 1 }`},
+			want:  []string{"tsconfig.json:1:14"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			writeFiles(t, tmpDir, tt.files)
+
+			_, err := ResolveTSConfig(filepath.Join(tmpDir, "tsconfig.json"))
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Expected error to contain %q, got %v", want, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,438 @@
+package program
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
+)
+
+// ImportKind is the syntax by which a source file imports a module.
+type ImportKind int
+
+const (
+	// ImportKindStatic is an import declaration, or an
+	// import x = require("...") declaration.
+	ImportKindStatic ImportKind = iota
+
+	// ImportKindReExport is an export ... from "..." declaration.
+	ImportKindReExport
+
+	// ImportKindDynamic is an import("...") expression.
+	ImportKindDynamic
+
+	// ImportKindRequire is a require("...") call.
+	ImportKindRequire
+
+	// ImportKindReference is a /// <reference path="..." /> directive.
+	ImportKindReference
+)
+
+// String returns the name of the kind.
+func (k ImportKind) String() string {
+	switch k {
+	case ImportKindStatic:
+		return "import"
+	case ImportKindReExport:
+		return "re-export"
+	case ImportKindDynamic:
+		return "dynamic import"
+	case ImportKindRequire:
+		return "require"
+	case ImportKindReference:
+		return "reference"
+	default:
+		return "unknown"
+	}
+}
+
+// Import is a module that a source file imports, and the file it resolves to.
+type Import struct {
+	// Specifier is the module specifier, or the path of a reference directive.
+	Specifier string
+
+	// Kind is the syntax of the import.
+	Kind ImportKind
+
+	// TypeOnly reports whether the import is an import type or export type
+	// declaration, which is erased from the compiled JavaScript.
+	TypeOnly bool
+
+	// Node is the declaration, expression or call of the import, or nil for
+	// a reference directive.
+	Node ast.Node
+
+	// Range is the range of Node, or of the comment of a reference directive.
+	Range ast.Range
+
+	// ResolvedFileName is the file the import resolves to, or empty if it
+	// cannot be resolved. It is the name of a source file of the program if
+	// the file is one, and the source file of a referenced project if the
+	// file is one of its outputs.
+	ResolvedFileName string
+}
+
+// ModuleGraph is the graph of the source files of a program, with an edge
+// from each file to each source file it imports. Imports of files outside
+// the program, such as packages in node_modules, are recorded with the
+// imports of a file but are not part of the graph. A ModuleGraph is not
+// updated when the program changes; Program.ModuleGraph returns a new one.
+type ModuleGraph struct {
+	files        []string
+	imports      map[string][]Import
+	dependencies map[string][]string
+	dependents   map[string][]string
+}
+
+// ModuleGraph returns the module graph of the program's source files,
+// resolving their imports on first use.
+func (p *Program) ModuleGraph() *ModuleGraph {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.graph == nil {
+		p.graph = p.buildModuleGraph()
+	}
+	return p.graph
+}
+
+// buildModuleGraph collects and resolves the imports of every source file.
+// The caller must hold p.mu.
+func (p *Program) buildModuleGraph() *ModuleGraph {
+	if p.resolver == nil {
+		config := p.Config
+		if config == nil {
+			config = &TSConfig{}
+		}
+		p.resolver = NewModuleResolver(config)
+	}
+
+	g := &ModuleGraph{
+		files:        p.sourceFileNames(),
+		imports:      make(map[string][]Import),
+		dependencies: make(map[string][]string),
+		dependents:   make(map[string][]string),
+	}
+
+	// Resolved file names are absolute, with symbolic links resolved; find
+	// the source file of each under any of its names.
+	names := make(map[string]string, len(g.files))
+	paths := make(map[string]string, len(g.files))
+	for _, name := range g.files {
+		path := p.absolutePath(name)
+		paths[name] = path
+		names[path] = name
+		if real, err := filepath.EvalSymlinks(path); err == nil {
+			names[real] = name
+		}
+	}
+
+	for _, name := range g.files {
+		imports := collectImports(p.SourceFiles[name])
+		for i := range imports {
+			imports[i].ResolvedFileName = p.resolveImport(imports[i], paths[name], names)
+			target, ok := names[imports[i].ResolvedFileName]
+			if !ok {
+				continue
+			}
+			imports[i].ResolvedFileName = target
+			if !slices.Contains(g.dependencies[name], target) {
+				g.dependencies[name] = append(g.dependencies[name], target)
+				g.dependents[target] = append(g.dependents[target], name)
+			}
+		}
+		g.imports[name] = imports
+	}
+	return g
+}
+
+// absolutePath returns the absolute path of the source file name.
+func (p *Program) absolutePath(name string) string {
+	if !filepath.IsAbs(name) && p.rootDir != "" {
+		name = filepath.Join(p.rootDir, name)
+	}
+	if abs, err := filepath.Abs(name); err == nil {
+		return abs
+	}
+	return name
+}
+
+// resolveImport returns the absolute path of the file imp resolves to, from
+// the file at path, or "" if it cannot be resolved. The path is the key of
+// a source file in names if the file is one.
+func (p *Program) resolveImport(imp Import, path string, names map[string]string) string {
+	if imp.Kind == ImportKindReference {
+		return resolveReferencePath(imp.Specifier, filepath.Dir(path))
+	}
+
+	mode := ResolutionModeDefault
+	switch {
+	case imp.Kind == ImportKindRequire:
+		mode = ResolutionModeRequire
+	case imp.Kind == ImportKindDynamic:
+		mode = ResolutionModeImport
+	case imp.Node != nil && imp.Node.Type() == ast.NodeTypeTSImportEqualsDeclaration.String():
+		mode = ResolutionModeRequire
+	}
+
+	resolved, err := p.resolver.ResolveModule(imp.Specifier, path, mode)
+	if err != nil {
+		return ""
+	}
+	if source, ok := p.sourceOfProjectReferenceOutput(resolved.ResolvedFileName); ok {
+		return source
+	}
+	if _, ok := names[resolved.ResolvedFileName]; !ok && resolved.OriginalPath != "" {
+		return resolved.OriginalPath
+	}
+	return resolved.ResolvedFileName
+}
+
+// resolveReferencePath resolves the path of a reference directive in a file
+// in dir: the file it names, or that file with a TypeScript extension added.
+func resolveReferencePath(reference, dir string) string {
+	path := filepath.FromSlash(reference)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	for _, ext := range []string{"", ".ts", ".tsx", ".d.ts"} {
+		if info, err := os.Stat(path + ext); err == nil && !info.IsDir() {
+			return path + ext
+		}
+	}
+	return ""
+}
+
+// referencePathPattern matches the text of a /// <reference path="..." />
+// comment, after the "//".
+var referencePathPattern = regexp.MustCompile(`^/\s*<reference\s+path\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+
+// collectImports returns the imports of a file in source order. Reference
+// directives count only in the comments before the first statement, as in
+// tsc.
+func collectImports(file *ast.Program) []Import {
+	if file == nil {
+		return nil
+	}
+
+	var imports []Import
+	firstStatement := -1
+	if len(file.Body) > 0 {
+		if r := nodeRange(file.Body[0]); r != (ast.Range{}) {
+			firstStatement = r[0]
+		}
+	}
+	for _, comment := range file.Comments {
+		if comment.Type != "Line" || comment.Range == nil ||
+			(firstStatement >= 0 && comment.Range[0] > firstStatement) {
+			continue
+		}
+		if m := referencePathPattern.FindStringSubmatch(comment.Value); m != nil {
+			imports = append(imports, Import{Specifier: m[1] + m[2], Kind: ImportKindReference, Range: *comment.Range})
+		}
+	}
+
+	ast.Traverse(file, func(node ast.Node) bool {
+		if imp, ok := importOf(node); ok {
+			imp.Node = node
+			imp.Range = nodeRange(node)
+			imports = append(imports, imp)
+		}
+		return true
+	})
+
+	sort.SliceStable(imports, func(i, j int) bool { return imports[i].Range[0] < imports[j].Range[0] })
+	return imports
+}
+
+// importOf returns the import that node makes, if any.
+func importOf(node ast.Node) (Import, bool) {
+	switch n := node.(type) {
+	case *ast.ImportDeclaration:
+		if specifier, ok := stringLiteral(n.Source); ok {
+			return Import{Specifier: specifier, Kind: ImportKindStatic, TypeOnly: isTypeKind(n.ImportKind)}, true
+		}
+	case *ast.ExportNamedDeclaration:
+		if specifier, ok := stringLiteral(n.Source); ok {
+			return Import{Specifier: specifier, Kind: ImportKindReExport, TypeOnly: isTypeKind(n.ExportKind)}, true
+		}
+	case *ast.ExportAllDeclaration:
+		if specifier, ok := stringLiteral(n.Source); ok {
+			return Import{Specifier: specifier, Kind: ImportKindReExport, TypeOnly: isTypeKind(n.ExportKind)}, true
+		}
+	case *ast.TSImportEqualsDeclaration:
+		if ref, ok := n.ModuleReference.(*ast.TSExternalModuleReference); ok {
+			if specifier, ok := stringLiteral(ref.Expression); ok {
+				return Import{Specifier: specifier, Kind: ImportKindStatic, TypeOnly: n.ImportKind == "type"}, true
+			}
+		}
+	case *ast.ImportExpression:
+		if specifier, ok := stringLiteral(n.Source); ok {
+			return Import{Specifier: specifier, Kind: ImportKindDynamic}, true
+		}
+	case *ast.CallExpression:
+		if callee, ok := n.Callee.(*ast.Identifier); ok && callee.Name == "require" && len(n.Arguments) == 1 {
+			if specifier, ok := stringLiteral(n.Arguments[0]); ok {
+				return Import{Specifier: specifier, Kind: ImportKindRequire}, true
+			}
+		}
+	}
+	return Import{}, false
+}
+
+// stringLiteral returns the value of node if it is a string literal.
+func stringLiteral(node any) (string, bool) {
+	literal, ok := node.(*ast.Literal)
+	if !ok || literal == nil {
+		return "", false
+	}
+	value, ok := literal.Value.(string)
+	return value, ok
+}
+
+func isTypeKind(kind *string) bool {
+	return kind != nil && *kind == "type"
+}
+
+// nodeRange returns the range of node, or the zero range if it has none.
+func nodeRange(node ast.Node) ast.Range {
+	if n, ok := node.(interface{ Base() *ast.BaseNode }); ok && n.Base().Range != nil {
+		return *n.Base().Range
+	}
+	return ast.Range{}
+}
+
+// Files returns the source files of the graph, in the order of
+// Program.SourceFileNames.
+func (g *ModuleGraph) Files() []string {
+	return slices.Clone(g.files)
+}
+
+// Imports returns the imports of a source file, in source order.
+func (g *ModuleGraph) Imports(file string) []Import {
+	return slices.Clone(g.imports[file])
+}
+
+// Dependencies returns the source files that a source file imports, in the
+// order of their first import.
+func (g *ModuleGraph) Dependencies(file string) []string {
+	return slices.Clone(g.dependencies[file])
+}
+
+// Dependents returns the source files that import a source file, in the
+// order of Files.
+func (g *ModuleGraph) Dependents(file string) []string {
+	return slices.Clone(g.dependents[file])
+}
+
+// AffectedFiles returns the given source files and every source file that
+// imports one of them, directly or indirectly: the files whose results may
+// change when the given files change. They are returned in the order of
+// Files.
+func (g *ModuleGraph) AffectedFiles(changed ...string) []string {
+	affected := make(map[string]bool)
+	queue := slices.Clone(changed)
+	for len(queue) > 0 {
+		file := queue[0]
+		queue = queue[1:]
+		if affected[file] {
+			continue
+		}
+		affected[file] = true
+		queue = append(queue, g.dependents[file]...)
+	}
+
+	var files []string
+	for _, file := range g.files {
+		if affected[file] {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// StronglyConnectedComponents returns the strongly connected components of
+// the graph: the largest sets of files that all import each other, directly
+// or indirectly. A component with more than one file, or whose file imports
+// itself, is an import cycle. Components come in topological order, each
+// after the components it imports; files within a component are in the
+// order of Files.
+func (g *ModuleGraph) StronglyConnectedComponents() [][]string {
+	// Tarjan's algorithm, which finds components in the required order.
+	index := make(map[string]int, len(g.files))
+	lowLink := make(map[string]int, len(g.files))
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	var connect func(file string)
+	connect = func(file string) {
+		index[file] = len(index)
+		lowLink[file] = index[file]
+		stack = append(stack, file)
+		onStack[file] = true
+
+		for _, dep := range g.dependencies[file] {
+			if _, visited := index[dep]; !visited {
+				connect(dep)
+				lowLink[file] = min(lowLink[file], lowLink[dep])
+			} else if onStack[dep] {
+				lowLink[file] = min(lowLink[file], index[dep])
+			}
+		}
+
+		if lowLink[file] == index[file] {
+			var component []string
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == file {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	order := make(map[string]int, len(g.files))
+	for i, file := range g.files {
+		order[file] = i
+	}
+	for _, file := range g.files {
+		if _, visited := index[file]; !visited {
+			connect(file)
+		}
+	}
+	for _, component := range components {
+		sort.Slice(component, func(i, j int) bool { return order[component[i]] < order[component[j]] })
+	}
+	return components
+}
+
+// Cycles returns the import cycles of the graph: its strongly connected
+// components with more than one file, or one file that imports itself.
+func (g *ModuleGraph) Cycles() [][]string {
+	var cycles [][]string
+	for _, component := range g.StronglyConnectedComponents() {
+		if len(component) > 1 || slices.Contains(g.dependencies[component[0]], component[0]) {
+			cycles = append(cycles, component)
+		}
+	}
+	return cycles
+}
+
+// TopologicalOrder returns the source files in an order where each file
+// comes after the files it imports. Files in a cycle cannot be ordered so;
+// they are adjacent, in the order of Files.
+func (g *ModuleGraph) TopologicalOrder() []string {
+	files := make([]string, 0, len(g.files))
+	for _, component := range g.StronglyConnectedComponents() {
+		files = append(files, component...)
+	}
+	return files
+}
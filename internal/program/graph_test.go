@@ -0,0 +1,123 @@
+package program
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestProgram_ModuleGraph(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"tsconfig.json":    `{ "include": ["src"] }`,
+		"src/a.ts":         "import { b } from './b';\nimport type { T } from './types';\nimport lib from 'lib';\nexport const a = b;\n",
+		"src/b.ts":         "export * from './c';\nexport { d } from './d.js';\nexport const b = 1;\n",
+		"src/c.ts":         "const a = require('./a');\nexport const c = a;\n",
+		"src/d.ts":         "export const d = () => import('./b');\n",
+		"src/e.ts":         "/// <reference path=\"./globals.d.ts\" />\nimport './missing';\n",
+		"src/globals.d.ts": "declare const VERSION: string;\n",
+		"src/types.ts":     "export type T = string;\n",
+
+		"node_modules/lib/index.d.ts": "export default 1;\n",
+	})
+
+	program, err := CreateProgram(&ProgramOptions{TSConfigPath: filepath.Join(tmpDir, "tsconfig.json")})
+	if err != nil {
+		t.Fatalf("Failed to create program: %v", err)
+	}
+	file := func(name string) string { return filepath.Join(tmpDir, "src", name) }
+	files := func(names ...string) []string {
+		paths := make([]string, len(names))
+		for i, name := range names {
+			paths[i] = file(name)
+		}
+		return paths
+	}
+	graph := program.ModuleGraph()
+
+	imports := graph.Imports(file("a.ts"))
+	if len(imports) != 3 {
+		t.Fatalf("Expected 3 imports of a.ts, got %+v", imports)
+	}
+	if imports[0].Specifier != "./b" || imports[0].Kind != ImportKindStatic || imports[0].ResolvedFileName != file("b.ts") {
+		t.Errorf("Unexpected first import %+v", imports[0])
+	}
+	if !imports[1].TypeOnly {
+		t.Errorf("Expected import type to be type-only, got %+v", imports[1])
+	}
+	if want := filepath.Join(tmpDir, "node_modules", "lib", "index.d.ts"); imports[2].ResolvedFileName != want {
+		t.Errorf("Expected lib to resolve to %s, got %s", want, imports[2].ResolvedFileName)
+	}
+
+	kinds := func(name string) []ImportKind {
+		var kinds []ImportKind
+		for _, imp := range graph.Imports(file(name)) {
+			kinds = append(kinds, imp.Kind)
+		}
+		return kinds
+	}
+	for name, want := range map[string][]ImportKind{
+		"b.ts": {ImportKindReExport, ImportKindReExport},
+		"c.ts": {ImportKindRequire},
+		"d.ts": {ImportKindDynamic},
+		"e.ts": {ImportKindReference, ImportKindStatic},
+	} {
+		if got := kinds(name); !slices.Equal(got, want) {
+			t.Errorf("Expected import kinds %v in %s, got %v", want, name, got)
+		}
+	}
+	if imp := graph.Imports(file("e.ts"))[1]; imp.ResolvedFileName != "" {
+		t.Errorf("Expected ./missing to be unresolved, got %s", imp.ResolvedFileName)
+	}
+
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{name: "dependencies of a.ts", got: graph.Dependencies(file("a.ts")), want: files("b.ts", "types.ts")},
+		{name: "dependencies of b.ts", got: graph.Dependencies(file("b.ts")), want: files("c.ts", "d.ts")},
+		{name: "dependencies of e.ts", got: graph.Dependencies(file("e.ts")), want: files("globals.d.ts")},
+		{name: "dependents of b.ts", got: graph.Dependents(file("b.ts")), want: files("a.ts", "d.ts")},
+		{name: "dependents of e.ts", got: graph.Dependents(file("e.ts")), want: nil},
+		{name: "affected by types.ts", got: graph.AffectedFiles(file("types.ts")), want: files("a.ts", "b.ts", "c.ts", "d.ts", "types.ts")},
+		{name: "affected by globals.d.ts", got: graph.AffectedFiles(file("globals.d.ts")), want: files("e.ts", "globals.d.ts")},
+		{
+			name: "topological order",
+			got:  graph.TopologicalOrder(),
+			want: files("types.ts", "a.ts", "b.ts", "c.ts", "d.ts", "globals.d.ts", "e.ts"),
+		},
+	}
+	for _, tt := range tests {
+		if !slices.Equal(tt.got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, tt.got)
+		}
+	}
+
+	cycles := graph.Cycles()
+	if len(cycles) != 1 || !slices.Equal(cycles[0], files("a.ts", "b.ts", "c.ts", "d.ts")) {
+		t.Errorf("Expected one cycle of a.ts, b.ts, c.ts and d.ts, got %v", cycles)
+	}
+	if components := graph.StronglyConnectedComponents(); len(components) != 4 {
+		t.Errorf("Expected 4 strongly connected components, got %v", components)
+	}
+}
+
+func TestModuleGraph_SelfImport(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"tsconfig.json": `{}`,
+		"self.ts":       "import './self';\n",
+		"other.ts":      "export {};\n",
+	})
+
+	program, err := CreateProgram(&ProgramOptions{TSConfigPath: filepath.Join(tmpDir, "tsconfig.json")})
+	if err != nil {
+		t.Fatalf("Failed to create program: %v", err)
+	}
+
+	cycles := program.ModuleGraph().Cycles()
+	if len(cycles) != 1 || !slices.Equal(cycles[0], []string{filepath.Join(tmpDir, "self.ts")}) {
+		t.Errorf("Expected a cycle of self.ts, got %v", cycles)
+	}
+}
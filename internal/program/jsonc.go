@@ -0,0 +1,374 @@
+package program
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ConfigError is an error in a configuration file, at the position where it
+// was found. Lines and columns are 1-based; columns count characters.
+type ConfigError struct {
+	Path    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// Error implements the error interface.
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.Path, e.Line, e.Column, e.Message)
+}
+
+// unmarshalJSONC decodes a JSON document that may contain comments and
+// trailing commas, as tsconfig.json files may, into each of vs. Errors are
+// returned as *ConfigError, with path used as the file name.
+func unmarshalJSONC(path string, data []byte, vs ...any) error {
+	p := &jsoncParser{
+		path:    path,
+		data:    data,
+		out:     append([]byte(nil), data...),
+		offsets: make(map[string]int),
+	}
+	if err := p.parseDocument(); err != nil {
+		return err
+	}
+
+	for _, v := range vs {
+		if err := json.Unmarshal(p.out, v); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) {
+				return p.typeError(typeErr)
+			}
+			return p.errorAt(len(data), err.Error())
+		}
+	}
+	return nil
+}
+
+// jsoncParser checks the syntax of a JSONC document and blanks out its
+// comments and trailing commas, so that what remains is standard JSON with
+// every value at its original offset.
+type jsoncParser struct {
+	path string
+	data []byte
+	out  []byte
+	pos  int
+
+	// offsets holds the offset of each value by its path, as
+	// json.UnmarshalTypeError reports it: keys and indexes joined by dots.
+	offsets map[string]int
+}
+
+func (p *jsoncParser) parseDocument() error {
+	if strings.HasPrefix(string(p.data), "\xef\xbb\xbf") {
+		p.blank(0, 3)
+		p.pos = 3
+	}
+
+	if err := p.skipTrivia(); err != nil {
+		return err
+	}
+	if p.pos == len(p.data) {
+		// A file with nothing but comments is an empty configuration.
+		p.out = append(p.out, "{}"...)
+		return nil
+	}
+
+	if err := p.parseValue(""); err != nil {
+		return err
+	}
+	if err := p.skipTrivia(); err != nil {
+		return err
+	}
+	if p.pos < len(p.data) {
+		return p.errorf("unexpected %s after the end of the document", p.describe())
+	}
+	return nil
+}
+
+func (p *jsoncParser) parseValue(path string) error {
+	p.offsets[path] = p.pos
+
+	switch c := p.data[p.pos]; {
+	case c == '{':
+		return p.parseObject(path)
+	case c == '[':
+		return p.parseArray(path)
+	case c == '"':
+		_, err := p.parseString()
+		return err
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	default:
+		for _, word := range []string{"true", "false", "null"} {
+			if strings.HasPrefix(string(p.data[p.pos:]), word) {
+				p.pos += len(word)
+				return nil
+			}
+		}
+		return p.errorf("expected a value, found %s", p.describe())
+	}
+}
+
+func (p *jsoncParser) parseObject(path string) error {
+	p.pos++ // '{'
+	for {
+		if err := p.skipTrivia(); err != nil {
+			return err
+		}
+		if p.consume('}') {
+			return nil
+		}
+
+		if p.pos == len(p.data) || p.data[p.pos] != '"' {
+			return p.errorf("expected a property name or '}', found %s", p.describe())
+		}
+		key, err := p.parseString()
+		if err != nil {
+			return err
+		}
+
+		if err := p.skipTrivia(); err != nil {
+			return err
+		}
+		if !p.consume(':') {
+			return p.errorf("expected ':' after property name, found %s", p.describe())
+		}
+		if err := p.skipTrivia(); err != nil {
+			return err
+		}
+		if p.pos == len(p.data) {
+			return p.errorf("expected a value, found %s", p.describe())
+		}
+		if err := p.parseValue(joinPath(path, key)); err != nil {
+			return err
+		}
+
+		done, err := p.parseSeparator('}')
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+func (p *jsoncParser) parseArray(path string) error {
+	p.pos++ // '['
+	for i := 0; ; i++ {
+		if err := p.skipTrivia(); err != nil {
+			return err
+		}
+		if p.consume(']') {
+			return nil
+		}
+		if p.pos == len(p.data) {
+			return p.errorf("expected a value or ']', found %s", p.describe())
+		}
+		if err := p.parseValue(joinPath(path, strconv.Itoa(i))); err != nil {
+			return err
+		}
+
+		done, err := p.parseSeparator(']')
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+// parseSeparator consumes the ',' or closing bracket after a member of an
+// object or array, and reports whether the bracket was reached. A comma
+// before the closing bracket is removed.
+func (p *jsoncParser) parseSeparator(closing byte) (bool, error) {
+	if err := p.skipTrivia(); err != nil {
+		return false, err
+	}
+	if p.consume(closing) {
+		return true, nil
+	}
+	if !p.consume(',') {
+		return false, p.errorf("expected ',' or '%c', found %s", closing, p.describe())
+	}
+
+	comma := p.pos - 1
+	if err := p.skipTrivia(); err != nil {
+		return false, err
+	}
+	if p.consume(closing) {
+		p.blank(comma, comma+1)
+		return true, nil
+	}
+	return false, nil
+}
+
+func (p *jsoncParser) parseString() (string, error) {
+	start := p.pos
+	p.pos++ // '"'
+	for p.pos < len(p.data) {
+		switch c := p.data[p.pos]; {
+		case c == '"':
+			p.pos++
+			var s string
+			if err := json.Unmarshal(p.data[start:p.pos], &s); err != nil {
+				p.pos = start
+				return "", p.errorf("invalid string literal")
+			}
+			return s, nil
+		case c == '\\':
+			p.pos += 2
+		case c == '\n' || c == '\r':
+			p.pos = start
+			return "", p.errorf("unterminated string literal")
+		default:
+			p.pos++
+		}
+	}
+	p.pos = start
+	return "", p.errorf("unterminated string literal")
+}
+
+func (p *jsoncParser) parseNumber() error {
+	start := p.pos
+	for p.pos < len(p.data) && strings.IndexByte("+-.0123456789eE", p.data[p.pos]) >= 0 {
+		p.pos++
+	}
+	if !json.Valid(p.data[start:p.pos]) {
+		p.pos = start
+		return p.errorf("invalid number")
+	}
+	return nil
+}
+
+// skipTrivia skips whitespace and comments, blanking the comments.
+func (p *jsoncParser) skipTrivia() error {
+	for p.pos < len(p.data) {
+		switch c := p.data[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			p.pos++
+		case c == '/' && p.pos+1 < len(p.data) && p.data[p.pos+1] == '/':
+			start := p.pos
+			for p.pos < len(p.data) && p.data[p.pos] != '\n' && p.data[p.pos] != '\r' {
+				p.pos++
+			}
+			p.blank(start, p.pos)
+		case c == '/' && p.pos+1 < len(p.data) && p.data[p.pos+1] == '*':
+			start := p.pos
+			end := strings.Index(string(p.data[p.pos+2:]), "*/")
+			if end < 0 {
+				return p.errorf("unterminated comment")
+			}
+			p.pos += 2 + end + 2
+			p.blank(start, p.pos)
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (p *jsoncParser) consume(c byte) bool {
+	if p.pos < len(p.data) && p.data[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// blank replaces the bytes in [start, end) of the output with spaces,
+// keeping line breaks so that line numbers are unchanged.
+func (p *jsoncParser) blank(start, end int) {
+	for i := start; i < end; i++ {
+		if p.out[i] != '\n' && p.out[i] != '\r' {
+			p.out[i] = ' '
+		}
+	}
+}
+
+// describe describes the input at the current position for error messages.
+func (p *jsoncParser) describe() string {
+	if p.pos >= len(p.data) {
+		return "end of file"
+	}
+	r, _ := utf8.DecodeRune(p.data[p.pos:])
+	return strconv.QuoteRune(r)
+}
+
+func (p *jsoncParser) errorf(format string, args ...any) error {
+	return p.errorAt(p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *jsoncParser) errorAt(offset int, message string) *ConfigError {
+	offset = min(offset, len(p.data))
+	line, lineStart := 1, 0
+	if strings.HasPrefix(string(p.data), "\xef\xbb\xbf") {
+		lineStart = 3 // the byte order mark is not a column
+	}
+	for i := 0; i < offset; i++ {
+		if p.data[i] == '\n' {
+			line++
+			lineStart = i + 1
+		}
+	}
+	return &ConfigError{
+		Path:    p.path,
+		Line:    line,
+		Column:  utf8.RuneCount(p.data[lineStart:offset]) + 1,
+		Message: message,
+	}
+}
+
+// typeError converts an error decoding a value into a Go type into a
+// ConfigError at the start of the value.
+func (p *jsoncParser) typeError(err *json.UnmarshalTypeError) *ConfigError {
+	// Older versions of encoding/json report a shorter path, without list
+	// indexes, so fall back to the closest recorded ancestor.
+	offset := int(err.Offset)
+	for path := err.Field; ; {
+		if o, ok := p.offsets[path]; ok {
+			offset = o
+			break
+		}
+		i := strings.LastIndexByte(path, '.')
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+
+	message := fmt.Sprintf("expected %s, found %s", jsonTypeName(err.Type), err.Value)
+	if err.Field != "" {
+		message = fmt.Sprintf("%s: %s", err.Field, message)
+	}
+	return p.errorAt(offset, message)
+}
+
+// jsonTypeName returns the JSON name for the values a Go type decodes.
+func jsonTypeName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	//nolint:exhaustive // Other kinds are not used by configuration types
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int64, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
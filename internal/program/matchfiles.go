@@ -0,0 +1,483 @@
+package program
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// commonPackageFolders are the directories that "**" does not descend into,
+// and that are excluded when a configuration has no "exclude".
+var commonPackageFolders = []string{"node_modules", "bower_components", "jspm_packages"}
+
+// FileMatcher decides which files belong to a project, from the "files",
+// "include" and "exclude" of its configuration, as tsc does:
+//
+//   - Files are included whatever their extension and the exclude patterns.
+//   - Include patterns are relative to the configuration directory. "*"
+//     matches any part of a name and "?" any one character, but neither
+//     matches a leading "." nor "*" the ".min.js" ending of a file name;
+//     "**/" matches any number of directories, other than hidden ones and
+//     node_modules, bower_components and jspm_packages. A pattern whose last
+//     component has no "." or wildcard is a directory, and includes
+//     everything in it. Without files or include, everything is included.
+//   - Exclude patterns exclude what they match and everything under it.
+//     Without exclude, node_modules, bower_components, jspm_packages,
+//     outDir and declarationDir are excluded.
+//   - Only files with a supported extension are included: those of
+//     TypeScript, JavaScript when allowJs is set, and any extra extensions.
+//     Of files differing only in the extension, such as a.ts, a.d.ts and
+//     a.js, only the one with the highest priority is included.
+//
+// File names are compared case-insensitively on Windows and macOS, whose
+// file systems usually are.
+type FileMatcher struct {
+	dir      string
+	files    []string
+	include  []*globPattern
+	exclude  []*globPattern
+	extGroup [][]string // supported extensions, by priority within groups
+
+	caseSensitive bool
+}
+
+// NewFileMatcher returns a FileMatcher for the project configured by config,
+// which must have been resolved by ResolveTSConfig. allowJS adds the
+// JavaScript extensions even if the configuration does not set allowJs, and
+// extraExtensions, such as ".vue", are added in order after the others.
+func NewFileMatcher(config *TSConfig, allowJS bool, extraExtensions []string) *FileMatcher {
+	caseSensitive := runtime.GOOS != "windows" && runtime.GOOS != "darwin"
+	return newFileMatcher(config, allowJS, extraExtensions, caseSensitive)
+}
+
+func newFileMatcher(config *TSConfig, allowJS bool, extraExtensions []string, caseSensitive bool) *FileMatcher {
+	m := &FileMatcher{
+		dir:           filepath.ToSlash(config.GetConfigDir()),
+		caseSensitive: caseSensitive,
+	}
+
+	for _, file := range config.Files {
+		m.files = append(m.files, m.absolute(file))
+	}
+
+	includes := config.Include
+	if config.Files == nil && config.Include == nil {
+		includes = []string{"**/*"}
+	}
+	for _, spec := range includes {
+		if pattern := m.newPattern(spec, false); pattern != nil {
+			m.include = append(m.include, pattern)
+		}
+	}
+
+	excludes := config.Exclude
+	if excludes == nil {
+		excludes = slices.Clone(commonPackageFolders)
+		for _, dir := range []string{config.CompilerOptions.OutDir, config.CompilerOptions.DeclarationDir} {
+			if dir != "" {
+				excludes = append(excludes, dir)
+			}
+		}
+	}
+	for _, spec := range excludes {
+		if pattern := m.newPattern(spec, true); pattern != nil {
+			m.exclude = append(m.exclude, pattern)
+		}
+	}
+
+	m.extGroup = [][]string{{".ts", ".tsx", ".d.ts"}, {".cts", ".d.cts"}, {".mts", ".d.mts"}}
+	if allowJS || (config.CompilerOptions.AllowJs != nil && *config.CompilerOptions.AllowJs) {
+		m.extGroup[0] = append(m.extGroup[0], ".js", ".jsx")
+		m.extGroup[1] = append(m.extGroup[1], ".cjs")
+		m.extGroup[2] = append(m.extGroup[2], ".mjs")
+	}
+	for _, ext := range extraExtensions {
+		if !slices.ContainsFunc(m.extGroup, func(group []string) bool { return slices.Contains(group, ext) }) {
+			m.extGroup = append(m.extGroup, []string{ext})
+		}
+	}
+
+	return m
+}
+
+// FileNames returns the files of the project: the files of the configuration
+// in order, then, for each include pattern in order, the files it matches
+// that no earlier pattern does. Directories are walked in lexical order,
+// files before subdirectories. Paths are absolute.
+func (m *FileMatcher) FileNames() []string {
+	names, _ := m.fileNames()
+	return names
+}
+
+// fileNames returns the files of the project, as FileNames does, and the
+// directories walked to find them, on whose entries the files depend.
+func (m *FileMatcher) fileNames() (names, dirs []string) {
+	literal := make(map[string]bool, len(m.files))
+	result := make([]string, 0, len(m.files))
+	for _, file := range m.files {
+		if key := m.key(file); !literal[key] {
+			literal[key] = true
+			result = append(result, filepath.FromSlash(file))
+		}
+	}
+
+	byPattern := make([][]string, len(m.include))
+	seen := make(map[string]bool)
+	visited := make(map[string]bool)
+	for _, base := range m.basePaths() {
+		m.walk(base, visited, func(dir string) {
+			dirs = append(dirs, filepath.FromSlash(dir))
+		}, func(path string) {
+			key := m.key(path)
+			if literal[key] || seen[key] {
+				return
+			}
+			if i := m.includedBy(path); i >= 0 {
+				seen[key] = true
+				byPattern[i] = append(byPattern[i], path)
+			}
+		})
+	}
+
+	// Leave out files for which a file with the same name and an extension
+	// of higher priority is included.
+	var wildcard []string
+	for _, files := range byPattern {
+		wildcard = append(wildcard, files...)
+	}
+	best := make(map[string]int)
+	for _, file := range append(slices.Clone(m.files), wildcard...) {
+		if key, index, ok := m.extensionKey(file); ok {
+			if i, found := best[key]; !found || index < i {
+				best[key] = index
+			}
+		}
+	}
+	for _, file := range wildcard {
+		if key, index, ok := m.extensionKey(file); ok && best[key] == index {
+			result = append(result, filepath.FromSlash(file))
+		}
+	}
+	return result, dirs
+}
+
+// Match reports whether the file at path, which is made absolute relative
+// to the working directory, is part of the project. It does not check that
+// the file exists, but does check for files that would take precedence over
+// it.
+func (m *FileMatcher) Match(path string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	path = filepath.ToSlash(abs)
+
+	if slices.ContainsFunc(m.files, func(file string) bool { return m.key(file) == m.key(path) }) {
+		return true
+	}
+	if m.includedBy(path) < 0 {
+		return false
+	}
+
+	// A file with the same name and a higher priority extension that is part
+	// of the project takes the place of this one.
+	name, group, index, _ := m.splitExtension(path)
+	for _, ext := range m.extGroup[group][:index] {
+		other := name + ext
+		if slices.ContainsFunc(m.files, func(file string) bool { return m.key(file) == m.key(other) }) {
+			return false
+		}
+		if info, err := os.Stat(filepath.FromSlash(other)); err == nil && !info.IsDir() && m.includedBy(other) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// includedBy returns the index of the first include pattern that matches the
+// file at the absolute, slash-separated path, or -1 if none does, the file
+// is excluded, or its extension is not supported.
+func (m *FileMatcher) includedBy(path string) int {
+	if _, _, _, ok := m.splitExtension(path); !ok {
+		return -1
+	}
+	segments := m.segments(path)
+	for _, pattern := range m.exclude {
+		if pattern.match(segments, false) {
+			return -1
+		}
+	}
+	for i, pattern := range m.include {
+		if pattern.match(segments, false) {
+			return i
+		}
+	}
+	return -1
+}
+
+// splitExtension splits path into the name and the longest supported
+// extension it ends with, given by its group and its index in the group. It
+// reports whether there is such an extension.
+func (m *FileMatcher) splitExtension(path string) (name string, group, index int, ok bool) {
+	length := 0
+	for g, extensions := range m.extGroup {
+		for i, ext := range extensions {
+			if len(ext) > length && strings.HasSuffix(path, ext) {
+				length, group, index, ok = len(ext), g, i, true
+			}
+		}
+	}
+	return path[:len(path)-length], group, index, ok
+}
+
+// extensionKey returns the key under which files that differ only in an
+// extension of the same group are compared, and the index of the extension
+// of path in its group.
+func (m *FileMatcher) extensionKey(path string) (key string, index int, ok bool) {
+	name, group, index, ok := m.splitExtension(path)
+	return m.key(name) + "\x00" + strconv.Itoa(group), index, ok
+}
+
+// basePaths returns the directories to walk for the include patterns: the
+// directory part of each pattern before its first wildcard, leaving out those
+// inside others.
+func (m *FileMatcher) basePaths() []string {
+	var bases []string
+	for _, pattern := range m.include {
+		base := pattern.base()
+		if i := slices.IndexFunc(bases, func(b string) bool { return m.contains(b, base) }); i >= 0 {
+			continue
+		}
+		bases = slices.DeleteFunc(bases, func(b string) bool { return m.contains(base, b) })
+		bases = append(bases, base)
+	}
+	return bases
+}
+
+// contains reports whether the directory dir is or contains path.
+func (m *FileMatcher) contains(dir, path string) bool {
+	dir, path = m.key(dir), m.key(path)
+	return path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, "/")+"/")
+}
+
+// walk calls fn for the files under dir, and dirFn for dir and the
+// directories under it, even if dir does not exist, skipping directories
+// that are excluded or that no include pattern can match anything in.
+// Directories already in visited, by their real path, are skipped, so that
+// symbolic links cannot cause cycles.
+func (m *FileMatcher) walk(dir string, visited map[string]bool, dirFn, fn func(path string)) {
+	dirFn(dir)
+	real, err := filepath.EvalSymlinks(filepath.FromSlash(dir))
+	if err != nil || visited[m.key(real)] {
+		return
+	}
+	visited[m.key(real)] = true
+
+	entries, err := os.ReadDir(filepath.FromSlash(dir))
+	if err != nil {
+		return
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		path := strings.TrimSuffix(dir, "/") + "/" + entry.Name()
+		isDir := entry.IsDir()
+		if entry.Type()&os.ModeSymlink != 0 {
+			info, err := os.Stat(filepath.FromSlash(path))
+			if err != nil {
+				continue
+			}
+			isDir = info.IsDir()
+		}
+
+		if isDir {
+			dirs = append(dirs, path)
+		} else {
+			fn(path)
+		}
+	}
+
+	for _, path := range dirs {
+		if m.mayContainMatches(path) {
+			m.walk(path, visited, dirFn, fn)
+		}
+	}
+}
+
+// mayContainMatches reports whether the directory at path is not excluded
+// and some include pattern can match files in it.
+func (m *FileMatcher) mayContainMatches(path string) bool {
+	segments := m.segments(path)
+	for _, pattern := range m.exclude {
+		if pattern.match(segments, false) {
+			return false
+		}
+	}
+	for _, pattern := range m.include {
+		if pattern.match(segments, true) {
+			return true
+		}
+	}
+	return false
+}
+
+// absolute returns spec, a path relative to the configuration directory, as
+// a clean, absolute, slash-separated path.
+func (m *FileMatcher) absolute(spec string) string {
+	spec = filepath.ToSlash(spec)
+	if !filepath.IsAbs(filepath.FromSlash(spec)) && !strings.HasPrefix(spec, "/") {
+		spec = m.dir + "/" + spec
+	}
+	return filepath.ToSlash(filepath.Clean(filepath.FromSlash(spec)))
+}
+
+// key returns path as compared with others: lowercased if file names are not
+// case-sensitive.
+func (m *FileMatcher) key(path string) string {
+	if m.caseSensitive {
+		return path
+	}
+	return strings.ToLower(path)
+}
+
+// segments splits an absolute, slash-separated path into its components, as
+// compared with patterns.
+func (m *FileMatcher) segments(path string) []string {
+	return strings.FieldsFunc(m.key(path), func(r rune) bool { return r == '/' })
+}
+
+// newPattern compiles an include or exclude spec. It returns nil for specs
+// tsc rejects: include specs ending in "**", and specs with ".." after a
+// "**".
+func (m *FileMatcher) newPattern(spec string, exclude bool) *globPattern {
+	path := m.absolute(spec)
+	segments := m.segments(path)
+	if len(segments) == 0 {
+		return nil
+	}
+	original := strings.FieldsFunc(path, func(r rune) bool { return r == '/' })
+
+	last := segments[len(segments)-1]
+	if !exclude && last == "**" {
+		return nil
+	}
+	if i := slices.Index(segments, "**"); i >= 0 && slices.Contains(segments[i:], "..") {
+		return nil
+	}
+	if !exclude && !strings.ContainsAny(last, ".*?") {
+		// An include spec naming a directory includes everything in it.
+		segments = append(segments, "**", "*")
+	}
+
+	return &globPattern{
+		prefix:   strings.HasPrefix(path, "/"),
+		original: original,
+		segments: segments,
+		exclude:  exclude,
+	}
+}
+
+// globPattern is a compiled include or exclude pattern, matched against the
+// components of absolute paths.
+type globPattern struct {
+	prefix   bool     // whether paths start with "/", as on Unix
+	original []string // the components of the pattern
+	segments []string // the components as compared with paths
+	exclude  bool
+}
+
+// base returns the directory part of the pattern before the first component
+// with a wildcard.
+func (p *globPattern) base() string {
+	i := slices.IndexFunc(p.segments, func(s string) bool { return strings.ContainsAny(s, "*?") })
+	if i < 0 {
+		i = len(p.segments) - 1
+	}
+	base := strings.Join(p.original[:i], "/")
+	if p.prefix {
+		base = "/" + base
+	}
+	return base
+}
+
+// match reports whether the pattern matches path, or, with partial, whether
+// it can match paths under path.
+func (p *globPattern) match(path []string, partial bool) bool {
+	return p.matchFrom(0, path, partial)
+}
+
+func (p *globPattern) matchFrom(i int, path []string, partial bool) bool {
+	if len(path) == 0 {
+		if partial {
+			return i < len(p.segments)
+		}
+		for ; i < len(p.segments) && p.segments[i] == "**"; i++ {
+		}
+		return i == len(p.segments)
+	}
+	if i == len(p.segments) {
+		// An exclude pattern excludes everything under what it matches.
+		return p.exclude
+	}
+
+	segment := p.segments[i]
+	if segment == "**" {
+		if p.matchFrom(i+1, path, partial) {
+			return true
+		}
+		if !p.exclude && (strings.HasPrefix(path[0], ".") || slices.Contains(commonPackageFolders, path[0])) {
+			return false
+		}
+		return p.matchFrom(i, path[1:], partial)
+	}
+
+	isLast := i == len(p.segments)-1 && len(path) == 1
+	if !matchSegment(segment, path[0], p.exclude, isLast) {
+		return false
+	}
+	return p.matchFrom(i+1, path[1:], partial)
+}
+
+// matchSegment matches a path component against a pattern component with
+// the wildcards "*" and "?". In include patterns, a leading wildcard does not
+// match a leading ".", and "*" does not match the "." of a ".min.js" ending
+// of the last component.
+func matchSegment(pattern, name string, exclude, isLast bool) bool {
+	if !exclude && pattern != "" && (pattern[0] == '*' || pattern[0] == '?') && strings.HasPrefix(name, ".") {
+		return false
+	}
+
+	var match func(pattern, name string) bool
+	match = func(pattern, name string) bool {
+		for pattern != "" {
+			switch pattern[0] {
+			case '*':
+				for i := 0; ; i++ {
+					if match(pattern[1:], name[i:]) {
+						return true
+					}
+					if i == len(name) || (!exclude && isLast && name[i:] == ".min.js") {
+						return false
+					}
+				}
+			case '?':
+				if name == "" {
+					return false
+				}
+				_, size := utf8.DecodeRuneInString(name)
+				pattern, name = pattern[1:], name[size:]
+			default:
+				if name == "" || name[0] != pattern[0] {
+					return false
+				}
+				pattern, name = pattern[1:], name[1:]
+			}
+		}
+		return name == ""
+	}
+	return match(pattern, name)
+}
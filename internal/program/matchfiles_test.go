@@ -0,0 +1,140 @@
+package program
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFileMatcher_FileNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   []string
+		config  TSConfig
+		allowJS bool
+		extra   []string
+		want    []string
+	}{
+		{
+			name: "defaults",
+			files: []string{
+				"a.ts", "src/b.tsx", "src/c.ts", "src/c.d.ts", "src/d.d.ts", "src/e.js",
+				"node_modules/pkg/index.ts", "dist/out.ts", ".cache/x.ts", "README.md",
+			},
+			config: TSConfig{CompilerOptions: CompilerOptions{OutDir: "dist"}},
+			want:   []string{"a.ts", "src/b.tsx", "src/c.ts", "src/d.d.ts"},
+		},
+		{
+			name:   "directory include and exclude pattern",
+			files:  []string{"a.ts", "src/b.ts", "src/b.test.ts", "src/sub/c.ts", "src/sub/c.test.ts"},
+			config: TSConfig{Include: []string{"src"}, Exclude: []string{"**/*.test.ts"}},
+			want:   []string{"src/b.ts", "src/sub/c.ts"},
+		},
+		{
+			name:   "wildcards",
+			files:  []string{"src/a1.ts", "src/a22.ts", "src/b1.ts", "src/x/a3.ts", "src/.a4.ts"},
+			config: TSConfig{Include: []string{"src/a?.ts", "src/**/a*.ts", "src/?1.ts"}},
+			want:   []string{"src/a1.ts", "src/a22.ts", "src/x/a3.ts", "src/b1.ts"},
+		},
+		{
+			name:    "allowJs",
+			files:   []string{"a.ts", "a.js", "b.js", "c.min.js", "d.mjs", "e.cts", "e.cjs"},
+			allowJS: true,
+			want:    []string{"a.ts", "b.js", "d.mjs", "e.cts"},
+		},
+		{
+			name:   "files first",
+			files:  []string{"src/a.ts", "z.ts", "gen/lib.js"},
+			config: TSConfig{Files: []string{"z.ts", "gen/lib.js", "./z.ts"}, Include: []string{"src/**/*"}},
+			want:   []string{"z.ts", "gen/lib.js", "src/a.ts"},
+		},
+		{
+			name:   "files only",
+			files:  []string{"a.ts", "b.ts"},
+			config: TSConfig{Files: []string{"b.ts"}},
+			want:   []string{"b.ts"},
+		},
+		{
+			name:   "extra extensions",
+			files:  []string{"App.vue", "main.ts", "style.css"},
+			config: TSConfig{},
+			extra:  []string{".vue"},
+			want:   []string{"App.vue", "main.ts"},
+		},
+		{
+			name:   "explicit node_modules",
+			files:  []string{"node_modules/pkg/a.ts", "node_modules/other/b.ts"},
+			config: TSConfig{Include: []string{"node_modules/pkg/*.ts"}, Exclude: []string{}},
+			want:   []string{"node_modules/pkg/a.ts"},
+		},
+		{
+			name:   "files before subdirectories",
+			files:  []string{"b/x.ts", "a.ts", "c.ts", "a/y.ts"},
+			config: TSConfig{},
+			want:   []string{"a.ts", "c.ts", "a/y.ts", "b/x.ts"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			contents := make(map[string]string)
+			for _, file := range tt.files {
+				contents[file] = ""
+			}
+			writeFiles(t, tmpDir, contents)
+
+			config := tt.config
+			config.path = filepath.Join(tmpDir, "tsconfig.json")
+			got := newFileMatcher(&config, tt.allowJS, tt.extra, true).FileNames()
+
+			want := make([]string, len(tt.want))
+			for i, file := range tt.want {
+				want[i] = filepath.Join(tmpDir, filepath.FromSlash(file))
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("Expected %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestFileMatcher_Match(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"src/a.ts":   "",
+		"src/c.ts":   "",
+		"src/c.d.ts": "",
+	})
+	config := &TSConfig{
+		path:    filepath.Join(tmpDir, "tsconfig.json"),
+		Files:   []string{"extra/generated.js"},
+		Include: []string{"src"},
+		Exclude: []string{"**/*.test.ts"},
+	}
+
+	tests := []struct {
+		path          string
+		caseSensitive bool
+		want          bool
+	}{
+		{path: "src/a.ts", caseSensitive: true, want: true},
+		{path: "src/new/b.tsx", caseSensitive: true, want: true},
+		{path: "src/a.test.ts", caseSensitive: true, want: false},
+		{path: "src/a.js", caseSensitive: true, want: false},
+		{path: "src/c.d.ts", caseSensitive: true, want: false},
+		{path: "other/a.ts", caseSensitive: true, want: false},
+		{path: "extra/generated.js", caseSensitive: true, want: true},
+		{path: "SRC/a.ts", caseSensitive: true, want: false},
+		{path: "SRC/a.ts", caseSensitive: false, want: true},
+		{path: "Extra/Generated.js", caseSensitive: false, want: true},
+	}
+
+	for _, tt := range tests {
+		matcher := newFileMatcher(config, false, nil, tt.caseSensitive)
+		path := filepath.Join(tmpDir, filepath.FromSlash(tt.path))
+		if got := matcher.Match(path); got != tt.want {
+			t.Errorf("Match(%s) with case sensitivity %v = %v, expected %v", tt.path, tt.caseSensitive, got, tt.want)
+		}
+	}
+}
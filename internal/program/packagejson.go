@@ -0,0 +1,207 @@
+package program
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// packageJSON holds the fields of a package.json file used to resolve
+// modules and configuration files in a package.
+type packageJSON struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Types    string `json:"types"`
+	Typings  string `json:"typings"`
+	Main     string `json:"main"`
+	TSConfig string `json:"tsconfig"`
+
+	// Exports and Imports are decoded with their objects in order, as the
+	// order of conditions matters; they are nil when absent or null.
+	Exports any `json:"-"`
+	Imports any `json:"-"`
+}
+
+// readPackageJSON reads the package.json file in dir. It returns nil if there
+// is none or it is not valid JSON.
+func readPackageJSON(dir string) *packageJSON {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json")) // #nosec G304 -- dir is a package directory being resolved
+	if err != nil {
+		return nil
+	}
+
+	var pkg packageJSON
+	var raw struct {
+		Exports json.RawMessage `json:"exports"`
+		Imports json.RawMessage `json:"imports"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+	if len(raw.Exports) > 0 {
+		pkg.Exports, _ = decodeOrdered(json.NewDecoder(bytes.NewReader(raw.Exports)))
+	}
+	if len(raw.Imports) > 0 {
+		pkg.Imports, _ = decodeOrdered(json.NewDecoder(bytes.NewReader(raw.Imports)))
+	}
+	return &pkg
+}
+
+// splitPackageName splits a module name into the package name, which
+// includes the scope of a scoped package, and the path within the package.
+func splitPackageName(name string) (packageName, subpath string) {
+	parts := strings.SplitN(name, "/", 3)
+	if strings.HasPrefix(name, "@") && len(parts) > 1 {
+		packageName = parts[0] + "/" + parts[1]
+		if len(parts) == 3 {
+			subpath = parts[2]
+		}
+		return packageName, subpath
+	}
+	packageName, subpath, _ = strings.Cut(name, "/")
+	return packageName, subpath
+}
+
+// resolveExports resolves subpath, which is empty for the package itself,
+// against the "exports" of a package with conditions, returning the path of
+// the exported file relative to the package, starting with "./".
+func resolveExports(exports any, subpath string, conditions map[string]bool) (string, bool) {
+	key := "."
+	if subpath != "" {
+		key = "./" + subpath
+	}
+
+	object, isObject := exports.(orderedObject)
+	if !isObject || len(object) == 0 || !strings.HasPrefix(object[0].key, ".") {
+		// Not a map of subpaths: the exports are those of the package itself.
+		if key != "." {
+			return "", false
+		}
+		return resolvePackageTarget(exports, "", conditions, false)
+	}
+	return resolveSubpathMap(object, key, conditions, false)
+}
+
+// resolveImports resolves specifier, which starts with "#", against the
+// "imports" of a package with conditions. The result is either a path
+// relative to the package, starting with "./", or the name of a module in
+// another package.
+func resolveImports(imports any, specifier string, conditions map[string]bool) (string, bool) {
+	object, ok := imports.(orderedObject)
+	if !ok {
+		return "", false
+	}
+	return resolveSubpathMap(object, specifier, conditions, true)
+}
+
+// resolveSubpathMap resolves key against the entries of an "exports" or
+// "imports" object. Entries with "*" are patterns, of which the one with the
+// longest prefix before the "*" wins, as in Node.js.
+func resolveSubpathMap(object orderedObject, key string, conditions map[string]bool, allowModules bool) (string, bool) {
+	for _, m := range object {
+		if m.key == key && !strings.Contains(m.key, "*") {
+			return resolvePackageTarget(m.value, "", conditions, allowModules)
+		}
+	}
+
+	var best *orderedMember
+	var bestMatch string
+	for i, m := range object {
+		prefix, suffix, ok := strings.Cut(m.key, "*")
+		if !ok || len(key) < len(prefix)+len(suffix) ||
+			!strings.HasPrefix(key, prefix) || !strings.HasSuffix(key, suffix) {
+			continue
+		}
+		if best == nil || len(prefix) > strings.Index(best.key, "*") {
+			best = &object[i]
+			bestMatch = key[len(prefix) : len(key)-len(suffix)]
+		}
+	}
+	if best == nil {
+		return "", false
+	}
+	return resolvePackageTarget(best.value, bestMatch, conditions, allowModules)
+}
+
+// resolvePackageTarget resolves the target of an "exports" or "imports"
+// entry, replacing "*" in it with match. Arrays are fallbacks, tried in
+// order; in conditions objects, the first matching condition decides.
+// Targets must be paths starting with "./", or, with allowModules, names of
+// modules in other packages.
+func resolvePackageTarget(target any, match string, conditions map[string]bool, allowModules bool) (string, bool) {
+	switch target := target.(type) {
+	case string:
+		isModule := !strings.HasPrefix(target, ".") && !strings.HasPrefix(target, "/")
+		if !strings.HasPrefix(target, "./") && !(allowModules && isModule) {
+			return "", false
+		}
+		return strings.ReplaceAll(target, "*", match), true
+	case []any:
+		for _, t := range target {
+			if resolved, ok := resolvePackageTarget(t, match, conditions, allowModules); ok {
+				return resolved, true
+			}
+		}
+	case orderedObject:
+		for _, m := range target {
+			if m.key == "default" || conditions[m.key] {
+				return resolvePackageTarget(m.value, match, conditions, allowModules)
+			}
+		}
+	}
+	return "", false
+}
+
+// orderedObject is a JSON object whose members are kept in order, as the
+// order of conditions in package.json "exports" matters.
+type orderedObject []orderedMember
+
+type orderedMember struct {
+	key   string
+	value any
+}
+
+// decodeOrdered decodes the next JSON value from dec like json.Unmarshal into
+// an any, except that objects are decoded as orderedObject.
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		var object orderedObject
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, orderedMember{key: key.(string), value: value})
+		}
+		_, err := dec.Token() // '}'
+		return object, err
+	case json.Delim('['):
+		array := []any{}
+		for dec.More() {
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err := dec.Token() // ']'
+		return array, err
+	default:
+		return token, nil
+	}
+}
@@ -4,10 +4,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 	"time"
 
-	"github.com/kdy1/go-typescript-eslint/internal/ast"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 )
 
 // Program represents a TypeScript program with type checking capabilities.
@@ -25,6 +26,33 @@ type Program struct {
 	// CreatedAt tracks when this program was created (for cache invalidation)
 	CreatedAt time.Time
 
+	// files decides which files are part of the program
+	files *FileMatcher
+
+	// diagnostics holds the problems found in each source file
+	diagnostics map[string][]Diagnostic
+
+	// rootDir is the directory relative root names are read from
+	rootDir string
+
+	// resolver resolves the imports of source files
+	resolver *ModuleResolver
+
+	// graph is the module graph, built on first use and reset when a source
+	// file is added
+	graph *ModuleGraph
+
+	// references holds the programs of the projects the tsconfig references
+	references []*Program
+
+	// outputs maps the output files of the program's root files to them,
+	// built on first use
+	outputs map[string]string
+
+	// directories lists the directories walked to find the root files, whose
+	// entries decide them
+	directories []string
+
 	// mu protects concurrent access to the program
 	mu sync.RWMutex
 }
@@ -42,10 +70,18 @@ type ProgramOptions struct {
 
 	// AllowJS enables JavaScript file parsing
 	AllowJS bool
+
+	// ExtraFileExtensions lists extensions besides those of TypeScript and
+	// JavaScript, such as ".vue", of files to include in the program
+	ExtraFileExtensions []string
 }
 
 // CreateProgram creates a TypeScript program from a tsconfig.json file.
 // This function handles tsconfig resolution, inheritance, and program initialization.
+// Every root file is parsed, concurrently; problems reading or parsing a file
+// are recorded as its diagnostics rather than returned as errors. A program
+// is also created for each project the tsconfig references, recursively; see
+// GetProjectReferences.
 func CreateProgram(opts *ProgramOptions) (*Program, error) {
 	if opts == nil {
 		return nil, fmt.Errorf("program options cannot be nil")
@@ -62,6 +98,14 @@ func CreateProgram(opts *ProgramOptions) (*Program, error) {
 		}
 	}
 
+	return createProgram(opts, tsconfigPath, make(map[string]*Program), nil)
+}
+
+// createProgram creates the program of the tsconfig at tsconfigPath and of
+// the projects it references. programs holds the programs created so far by
+// absolute tsconfig path, so that a project referenced twice has one
+// program; chain lists the tsconfigs referencing this one, to detect cycles.
+func createProgram(opts *ProgramOptions, tsconfigPath string, programs map[string]*Program, chain []string) (*Program, error) {
 	// Parse and resolve tsconfig with inheritance
 	config, err := ResolveTSConfig(tsconfigPath)
 	if err != nil {
@@ -69,10 +113,12 @@ func CreateProgram(opts *ProgramOptions) (*Program, error) {
 	}
 
 	// Determine root file names
+	files := NewFileMatcher(config, opts.AllowJS, opts.ExtraFileExtensions)
 	rootNames := opts.SourceFiles
+	var directories []string
 	if len(rootNames) == 0 {
-		// Use files from tsconfig
-		rootNames = config.Files
+		// Use files, include and exclude from tsconfig
+		rootNames, directories = files.fileNames()
 	}
 
 	// Create program instance
@@ -81,6 +127,25 @@ func CreateProgram(opts *ProgramOptions) (*Program, error) {
 		RootNames:   rootNames,
 		SourceFiles: make(map[string]*ast.Program),
 		CreatedAt:   time.Now(),
+		files:       files,
+		diagnostics: make(map[string][]Diagnostic),
+		rootDir:     opts.RootDir,
+		resolver:    NewModuleResolver(config),
+		directories: directories,
+	}
+
+	// Parse every root file
+	program.parseSourceFiles(opts.RootDir)
+
+	// Create the programs of referenced projects
+	if err := program.createReferencedPrograms(opts, programs, chain); err != nil {
+		return nil, err
+	}
+	if len(program.references) > 0 {
+		program.resolver.virtualFile = func(path string) bool {
+			_, ok := program.sourceOfProjectReferenceOutput(path)
+			return ok
+		}
 	}
 
 	return program, nil
@@ -101,6 +166,50 @@ func (p *Program) AddSourceFile(filePath string, ast *ast.Program) {
 	defer p.mu.Unlock()
 
 	p.SourceFiles[filePath] = ast
+	p.graph = nil
+}
+
+// ContainsFile reports whether the file at filePath is part of the program:
+// one of its root names, or a file its tsconfig includes.
+func (p *Program) ContainsFile(filePath string) bool {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return false
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, name := range p.RootNames {
+		if rootPath, err := filepath.Abs(name); err == nil && rootPath == absPath {
+			return true
+		}
+	}
+	return p.files != nil && p.files.Match(absPath)
+}
+
+// WatchedPaths returns the files and directories whose changes make the
+// program, or the program of a project it references, out of date: each
+// tsconfig and the configuration files it extends, the root files, whose
+// ASTs the program holds, and the directories walked to match the
+// tsconfig's include patterns, including those that do not exist. The
+// paths are absolute, and each appears once.
+func (p *Program) WatchedPaths() []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, project := range p.Projects() {
+		// GetConfigFiles returns the config's own slice, which must not be
+		// appended to
+		watched := slices.Clone(project.Config.GetConfigFiles())
+		watched = append(watched, project.rootFilePaths()...)
+		for _, path := range append(watched, project.directories...) {
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths
 }
 
 // GetCompilerOptions returns the TypeScript compiler options for this program.
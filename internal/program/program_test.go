@@ -3,9 +3,10 @@ package program
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
-	"github.com/kdy1/go-typescript-eslint/internal/ast"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 )
 
 func TestCreateProgram(t *testing.T) {
@@ -48,6 +49,164 @@ func TestCreateProgram(t *testing.T) {
 	}
 }
 
+func TestCreateProgram_RootNamesFromInclude(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"tsconfig.json":      `{ "include": ["src"], "exclude": ["src/vendor"] }`,
+		"src/index.ts":       "",
+		"src/util/math.ts":   "",
+		"src/vendor/lib.ts":  "",
+		"scripts/release.ts": "",
+	})
+
+	program, err := CreateProgram(&ProgramOptions{TSConfigPath: filepath.Join(tmpDir, "tsconfig.json")})
+	if err != nil {
+		t.Fatalf("Failed to create program: %v", err)
+	}
+
+	want := []string{filepath.Join(tmpDir, "src", "index.ts"), filepath.Join(tmpDir, "src", "util", "math.ts")}
+	if !slices.Equal(program.RootNames, want) {
+		t.Errorf("Expected root names %v, got %v", want, program.RootNames)
+	}
+
+	if !program.ContainsFile(filepath.Join(tmpDir, "src", "new.ts")) {
+		t.Error("Expected new file under src to be part of the program")
+	}
+	if program.ContainsFile(filepath.Join(tmpDir, "src", "vendor", "lib.ts")) {
+		t.Error("Expected excluded file not to be part of the program")
+	}
+	if program.ContainsFile(filepath.Join(tmpDir, "scripts", "release.ts")) {
+		t.Error("Expected file outside include not to be part of the program")
+	}
+}
+
+func TestCreateProgram_ParsesSourceFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"tsconfig.json": `{ "compilerOptions": { "module": "commonjs" } }`,
+		"a.ts":          "export const a = 1;",
+		"b.tsx":         "const element = <div>hello</div>;",
+		"c.ts":          "let x = ;",
+		"d.mts":         "const d = 1;",
+		"e.ts":          "const e = 1;",
+	})
+
+	program, err := CreateProgram(&ProgramOptions{TSConfigPath: filepath.Join(tmpDir, "tsconfig.json")})
+	if err != nil {
+		t.Fatalf("Failed to create program: %v", err)
+	}
+
+	names := program.SourceFileNames()
+	if len(names) != 5 {
+		t.Fatalf("Expected 5 source files, got %v", names)
+	}
+
+	sourceTypes := map[string]string{"a.ts": "module", "b.tsx": "script", "d.mts": "module", "e.ts": "script"}
+	for base, want := range sourceTypes {
+		file, ok := program.GetSourceFile(filepath.Join(tmpDir, base))
+		if !ok {
+			t.Errorf("Expected %s to be parsed", base)
+			continue
+		}
+		if file.SourceType != want {
+			t.Errorf("Expected %s to have source type %s, got %s", base, want, file.SourceType)
+		}
+	}
+
+	bad := filepath.Join(tmpDir, "c.ts")
+	diagnostics := program.GetDiagnostics(bad)
+	if len(diagnostics) == 0 {
+		t.Fatal("Expected diagnostics for c.ts")
+	}
+	if diagnostics[0].File != bad || diagnostics[0].Line != 1 {
+		t.Errorf("Unexpected diagnostic %v", diagnostics[0])
+	}
+	if all := program.GetAllDiagnostics(); len(all) != len(diagnostics) {
+		t.Errorf("Expected only the diagnostics of c.ts, got %v", all)
+	}
+	if d := program.GetDiagnostics(filepath.Join(tmpDir, "b.tsx")); len(d) != 0 {
+		t.Errorf("Expected JSX in .tsx file to parse, got %v", d)
+	}
+
+	var visited []string
+	program.ForEachSourceFile(func(name string, _ *ast.Program) bool {
+		visited = append(visited, name)
+		return len(visited) < 2
+	})
+	if !slices.Equal(visited, names[:2]) {
+		t.Errorf("Expected iteration to stop after %v, got %v", names[:2], visited)
+	}
+}
+
+func TestCreateProgram_ParsesJavaScriptWithJSX(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"tsconfig.json": `{ "compilerOptions": { "allowJs": true } }`,
+		"a.js":          "const a = <div>a</div>;",
+		"b.jsx":         "const b = <div>b</div>;",
+		"c.mjs":         "export const c = <div>c</div>;",
+		"d.cjs":         "const d = <div>d</div>;",
+	})
+
+	program, err := CreateProgram(&ProgramOptions{TSConfigPath: filepath.Join(tmpDir, "tsconfig.json")})
+	if err != nil {
+		t.Fatalf("Failed to create program: %v", err)
+	}
+
+	if names := program.SourceFileNames(); len(names) != 4 {
+		t.Fatalf("Expected 4 source files, got %v", names)
+	}
+	if all := program.GetAllDiagnostics(); len(all) != 0 {
+		t.Errorf("Expected JSX in JavaScript files to parse without the jsx option, got %v", all)
+	}
+}
+
+func TestCreateProgram_MissingSourceFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{"tsconfig.json": `{ "files": ["missing.ts"] }`})
+
+	program, err := CreateProgram(&ProgramOptions{TSConfigPath: filepath.Join(tmpDir, "tsconfig.json")})
+	if err != nil {
+		t.Fatalf("Failed to create program: %v", err)
+	}
+
+	missing := filepath.Join(tmpDir, "missing.ts")
+	if _, ok := program.GetSourceFile(missing); ok {
+		t.Error("Expected no AST for a missing file")
+	}
+	if d := program.GetDiagnostics(missing); len(d) != 1 || d[0].Line != 0 {
+		t.Errorf("Expected one diagnostic without position, got %v", d)
+	}
+}
+
+func TestProgramWatchedPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"tsconfig.base.json": `{"compilerOptions": {"strict": true}}`,
+		"tsconfig.json":      `{"extends": "./tsconfig.base.json", "include": ["src"]}`,
+		"src/a.ts":           "export const a = 1;\n",
+	})
+
+	prog, err := CreateProgram(&ProgramOptions{TSConfigPath: filepath.Join(tmpDir, "tsconfig.json")})
+	if err != nil {
+		t.Fatalf("CreateProgram() error = %v", err)
+	}
+
+	// Leave room after the config files, which WatchedPaths must not use
+	configFiles := prog.Config.GetConfigFiles()
+	prog.Config.files = append(make([]string, 0, len(configFiles)+4), configFiles...)
+
+	paths := prog.WatchedPaths()
+	for _, want := range []string{"tsconfig.json", "tsconfig.base.json", "src"} {
+		if !slices.Contains(paths, filepath.Join(tmpDir, want)) {
+			t.Errorf("Expected %s in the watched paths, got %v", want, paths)
+		}
+	}
+	if spare := prog.Config.files[len(configFiles):cap(prog.Config.files)]; slices.ContainsFunc(spare, func(path string) bool { return path != "" }) {
+		t.Errorf("Expected the config files to be left alone, got %v after them", spare)
+	}
+}
+
 func TestProgramGetSourceFile(t *testing.T) {
 	program := &Program{
 		SourceFiles: make(map[string]*ast.Program),
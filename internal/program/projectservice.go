@@ -0,0 +1,188 @@
+package program
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
+)
+
+// ProjectServiceOptions configures a ProjectService.
+type ProjectServiceOptions struct {
+	// DefaultProject is the path of the tsconfig whose compiler options the
+	// default project uses, relative to RootDir. Without it, the default
+	// project uses the default compiler options.
+	DefaultProject string
+
+	// RootDir is the directory relative paths are resolved against
+	RootDir string
+
+	// AllowJS enables JavaScript file parsing in configured projects
+	AllowJS bool
+
+	// ExtraFileExtensions lists extensions besides those of TypeScript and
+	// JavaScript, such as ".vue", of files to include in configured projects
+	ExtraFileExtensions []string
+}
+
+// ProjectService opens the projects of files as an editor does, and keeps
+// them open for later files. The configured project of a file is that of the
+// nearest tsconfig.json, looking up from the file, that includes it, itself
+// or through its project references. Files no configured project includes
+// can be opened in the default project instead, an inferred project holding
+// just those files.
+//
+// A ProjectService is safe for concurrent use.
+type ProjectService struct {
+	opts ProjectServiceOptions
+
+	// defaultConfig is the configuration of the default project
+	defaultConfig *TSConfig
+
+	// projects caches the programs of configured projects by tsconfig path
+	projects *ProgramCache
+
+	// defaultProjectFiles lists the files opened in the default project, in
+	// the order they were opened
+	defaultProjectFiles []string
+
+	// defaultProject is the program of the default project, to which the
+	// files opened in it are added
+	defaultProject *Program
+
+	// mu protects defaultProjectFiles and defaultProject
+	mu sync.Mutex
+}
+
+// NewProjectService returns a project service. It fails if the tsconfig of
+// the default project cannot be read.
+func NewProjectService(opts *ProjectServiceOptions) (*ProjectService, error) {
+	if opts == nil {
+		opts = &ProjectServiceOptions{}
+	}
+	rootDir, err := filepath.Abs(opts.RootDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve absolute path: %w", err)
+	}
+
+	s := &ProjectService{
+		opts:     *opts,
+		projects: NewProgramCache(0),
+	}
+	s.opts.RootDir = rootDir
+
+	if opts.DefaultProject != "" {
+		path := opts.DefaultProject
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(rootDir, path)
+		}
+		config, err := ResolveTSConfig(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read default project %s: %w", opts.DefaultProject, err)
+		}
+		s.defaultConfig = config
+	} else {
+		// The default project behaves as if it had an empty tsconfig.json
+		// in the root directory
+		s.defaultConfig = &TSConfig{path: filepath.Join(rootDir, "tsconfig.json")}
+	}
+	return s, nil
+}
+
+// absolutePath returns the absolute path of filePath, relative to the root
+// directory of the service.
+func (s *ProjectService) absolutePath(filePath string) string {
+	if filepath.IsAbs(filePath) {
+		return filepath.Clean(filePath)
+	}
+	return filepath.Join(s.opts.RootDir, filePath)
+}
+
+// OpenConfiguredProject returns the program of the configured project of the
+// file at filePath, opening the project, and those it references, if it is
+// not open yet. It returns nil, and no error, if no configured project
+// includes the file. Each tsconfig.json from the file's directory up is
+// tried in turn, as found by FindConfigForFile, and the program returned is
+// the one that ProjectForFile finds for the file.
+func (s *ProjectService) OpenConfiguredProject(filePath string) (*Program, error) {
+	path := s.absolutePath(filePath)
+
+	for search := path; ; {
+		configPath, err := FindConfigForFile(search)
+		if err != nil {
+			return nil, nil
+		}
+
+		prog, err := s.projects.GetOrCreate(&ProgramOptions{
+			TSConfigPath:        configPath,
+			RootDir:             s.opts.RootDir,
+			AllowJS:             s.opts.AllowJS,
+			ExtraFileExtensions: s.opts.ExtraFileExtensions,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to open project %s: %w", configPath, err)
+		}
+		if owner := prog.ProjectForFile(path); owner != nil {
+			return owner, nil
+		}
+
+		// Look further up, from the directory above the tsconfig's, unless
+		// the tsconfig is in the file system root and none is above it
+		search = filepath.Dir(configPath)
+		if filepath.Dir(search) == search {
+			return nil, nil
+		}
+	}
+}
+
+// OpenDefaultProject opens the file at filePath in the default project, if
+// it is not open in it yet, and returns the program of the default project.
+func (s *ProjectService) OpenDefaultProject(filePath string) *Program {
+	path := s.absolutePath(filePath)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.defaultProject == nil {
+		s.defaultProject = s.createDefaultProject()
+	}
+	if !slices.Contains(s.defaultProjectFiles, path) {
+		s.defaultProjectFiles = append(s.defaultProjectFiles, path)
+		s.defaultProject.addRootFile(path)
+	}
+	return s.defaultProject
+}
+
+// createDefaultProject creates the program of the default project, with no
+// files open in it yet.
+func (s *ProjectService) createDefaultProject() *Program {
+	return &Program{
+		Config:      s.defaultConfig,
+		SourceFiles: make(map[string]*ast.Program),
+		CreatedAt:   time.Now(),
+		diagnostics: make(map[string][]Diagnostic),
+		rootDir:     s.opts.RootDir,
+		resolver:    NewModuleResolver(s.defaultConfig),
+	}
+}
+
+// DefaultProjectFiles returns the absolute paths of the files opened in the
+// default project, in the order they were opened.
+func (s *ProjectService) DefaultProjectFiles() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.defaultProjectFiles)
+}
+
+// ClearDefaultProjectFiles closes the files opened in the default project.
+func (s *ProjectService) ClearDefaultProjectFiles() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.defaultProjectFiles = nil
+	s.defaultProject = nil
+}
@@ -0,0 +1,77 @@
+package program
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestProjectService(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"tsconfig.json":                  `{"files": [], "references": [{"path": "./packages/core"}]}`,
+		"tsconfig.eslint.json":           `{"compilerOptions": {"target": "ESNext"}}`,
+		"packages/core/tsconfig.json":    `{"include": ["src"]}`,
+		"packages/core/src/index.ts":     "export const core = 1;\n",
+		"packages/core/eslint.config.js": "export default [];\n",
+		"scripts/release.js":             "export {};\n",
+	})
+
+	service, err := NewProjectService(&ProjectServiceOptions{RootDir: tmpDir, DefaultProject: "tsconfig.eslint.json"})
+	if err != nil {
+		t.Fatalf("Failed to create project service: %v", err)
+	}
+
+	core, err := service.OpenConfiguredProject("packages/core/src/index.ts")
+	if err != nil {
+		t.Fatalf("OpenConfiguredProject() error = %v", err)
+	}
+	if core == nil || core.Config.GetPath() != filepath.Join(tmpDir, "packages", "core", "tsconfig.json") {
+		t.Fatalf("Expected the program of packages/core, got %v", core)
+	}
+	if again, _ := service.OpenConfiguredProject(filepath.Join(tmpDir, "packages", "core", "src", "index.ts")); again != core {
+		t.Errorf("Expected the open project to be reused")
+	}
+
+	// Neither packages/core/tsconfig.json nor the solution tsconfig above it
+	// include the file
+	config := filepath.Join(tmpDir, "packages", "core", "eslint.config.js")
+	if prog, err := service.OpenConfiguredProject(config); err != nil || prog != nil {
+		t.Fatalf("Expected no configured project, got %v, %v", prog, err)
+	}
+
+	prog := service.OpenDefaultProject(config)
+	if target := prog.GetCompilerOptions().Target; target != "ESNext" {
+		t.Errorf("Expected the compiler options of the default project, got target %q", target)
+	}
+	if !prog.ContainsFile(config) {
+		t.Errorf("Expected the default project to contain %s", config)
+	}
+	if _, ok := prog.GetSourceFile(config); !ok {
+		t.Errorf("Expected %s to be parsed", config)
+	}
+	if service.OpenDefaultProject(config) != prog {
+		t.Errorf("Expected the default project to be reused for a file open in it")
+	}
+	if files := service.DefaultProjectFiles(); !slices.Equal(files, []string{config}) {
+		t.Errorf("DefaultProjectFiles() = %v", files)
+	}
+
+	// Opening another file parses just that file
+	parsed, _ := prog.GetSourceFile(config)
+	release := filepath.Join(tmpDir, "scripts", "release.js")
+	if service.OpenDefaultProject(release) != prog {
+		t.Errorf("Expected files to be added to the open default project")
+	}
+	if again, _ := prog.GetSourceFile(config); again != parsed {
+		t.Errorf("Expected %s not to be parsed again", config)
+	}
+	if names := prog.SourceFileNames(); !slices.Equal(names, []string{config, release}) {
+		t.Errorf("SourceFileNames() = %v", names)
+	}
+
+	service.ClearDefaultProjectFiles()
+	if files := service.DefaultProjectFiles(); len(files) != 0 {
+		t.Errorf("Expected no default project files after clearing, got %v", files)
+	}
+}
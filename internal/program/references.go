@@ -0,0 +1,216 @@
+package program
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// resolveProjectReferencePath returns the path of the tsconfig of a project
+// reference in a tsconfig in configDir: the path itself if it names a .json
+// file, and otherwise the tsconfig.json in the directory it names, as in tsc.
+func resolveProjectReferencePath(configDir, ref string) string {
+	path := filepath.FromSlash(ref)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(configDir, path)
+	}
+	if strings.HasSuffix(path, ".json") {
+		return path
+	}
+	return filepath.Join(path, "tsconfig.json")
+}
+
+// createReferencedPrograms creates the programs of the projects that the
+// tsconfig of p references, and, recursively, of the projects they
+// reference. A project that references itself, directly or through others,
+// is an error.
+func (p *Program) createReferencedPrograms(opts *ProgramOptions, programs map[string]*Program, chain []string) error {
+	configPath := p.Config.GetPath()
+	programs[configPath] = p
+	chain = append(chain, configPath)
+
+	for _, ref := range p.Config.References {
+		path := resolveProjectReferencePath(p.Config.GetConfigDir(), ref.Path)
+		for i, seen := range chain {
+			if seen == path {
+				cycle := append(chain[i:len(chain):len(chain)], path)
+				return fmt.Errorf("circularity detected in project references: %s", strings.Join(cycle, " -> "))
+			}
+		}
+
+		referenced, ok := programs[path]
+		if !ok {
+			refOpts := &ProgramOptions{
+				TSConfigPath:        path,
+				AllowJS:             opts.AllowJS,
+				ExtraFileExtensions: opts.ExtraFileExtensions,
+			}
+			var err error
+			referenced, err = createProgram(refOpts, path, programs, chain)
+			if err != nil {
+				return fmt.Errorf("failed to load project reference %s in %s: %w", ref.Path, configPath, err)
+			}
+		}
+		p.references = append(p.references, referenced)
+	}
+	return nil
+}
+
+// GetProjectReferences returns the programs of the projects that the
+// program's tsconfig references, in order.
+func (p *Program) GetProjectReferences() []*Program {
+	return p.references
+}
+
+// Projects returns the program and the programs of all projects it
+// references, directly or indirectly, each once, depth first in the order of
+// the references.
+func (p *Program) Projects() []*Program {
+	var projects []*Program
+	seen := make(map[*Program]bool)
+	var visit func(*Program)
+	visit = func(program *Program) {
+		if seen[program] {
+			return
+		}
+		seen[program] = true
+		projects = append(projects, program)
+		for _, ref := range program.references {
+			visit(ref)
+		}
+	}
+	visit(p)
+	return projects
+}
+
+// ProjectForFile returns the program of the project the file at filePath
+// belongs to: the first of Projects that contains it, as reported by
+// ContainsFile, or nil if none does. A solution-style tsconfig, with no files
+// of its own and references to the projects of a repository, thus finds the
+// project of any of their files.
+func (p *Program) ProjectForFile(filePath string) *Program {
+	for _, project := range p.Projects() {
+		if project.ContainsFile(filePath) {
+			return project
+		}
+	}
+	return nil
+}
+
+// sourceOfProjectReferenceOutput returns the source file of a referenced
+// project that the file at path is an output of, such as the .ts file that
+// a .d.ts file in the project's outDir is emitted from. Imports that resolve
+// to the outputs of referenced projects are resolved to their sources
+// instead, as they are in editors.
+func (p *Program) sourceOfProjectReferenceOutput(path string) (string, bool) {
+	for _, project := range p.Projects()[1:] {
+		if source, ok := project.sourceOfOutput(path); ok {
+			return source, true
+		}
+	}
+	return "", false
+}
+
+// outputExtensions maps the extensions of source files to those of their
+// JavaScript and declaration outputs.
+var outputExtensions = []struct{ source, js, declaration string }{
+	{".tsx", ".js", ".d.ts"},
+	{".ts", ".js", ".d.ts"},
+	{".mts", ".mjs", ".d.mts"},
+	{".cts", ".cjs", ".d.cts"},
+	{".jsx", "", ".d.ts"},
+	{".js", "", ".d.ts"},
+	{".mjs", "", ".d.mts"},
+	{".cjs", "", ".d.cts"},
+}
+
+// sourceOfOutput returns the root file of p that the file at path is the
+// JavaScript or declaration output of.
+func (p *Program) sourceOfOutput(path string) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.outputs == nil {
+		p.outputs = p.outputFiles()
+	}
+	source, ok := p.outputs[path]
+	return source, ok
+}
+
+// outputFiles maps the paths of the files tsc would emit for the root files
+// of p to the root files. Outputs go to outDir, and declarations to
+// declarationDir if set, keeping the layout of the files under rootDir;
+// without outDir, they are written next to their sources. The caller must
+// hold p.mu.
+func (p *Program) outputFiles() map[string]string {
+	outputs := make(map[string]string)
+	options := &p.Config.CompilerOptions
+
+	var sources []string
+	for _, name := range p.RootNames {
+		if path := p.absolutePath(name); !isDeclarationFile(path) {
+			sources = append(sources, path)
+		}
+	}
+
+	rootDir := options.RootDir
+	if rootDir == "" {
+		rootDir = commonSourceDirectory(sources)
+	}
+	declarationDir := options.DeclarationDir
+	if declarationDir == "" {
+		declarationDir = options.OutDir
+	}
+
+	for _, source := range sources {
+		for _, ext := range outputExtensions {
+			base, ok := strings.CutSuffix(source, ext.source)
+			if !ok {
+				continue
+			}
+			rel, err := filepath.Rel(rootDir, base)
+			if err != nil {
+				break
+			}
+			if ext.js != "" {
+				outputs[outputPath(options.OutDir, rel, base)+ext.js] = source
+			}
+			outputs[outputPath(declarationDir, rel, base)+ext.declaration] = source
+			break
+		}
+	}
+	return outputs
+}
+
+// outputPath returns the path, without extension, of the output of a source
+// file at base, which is rel under the root directory, written to dir.
+func outputPath(dir, rel, base string) string {
+	if dir == "" {
+		return base
+	}
+	return filepath.Join(dir, rel)
+}
+
+// isDeclarationFile reports whether path is a declaration file.
+func isDeclarationFile(path string) bool {
+	return strings.HasSuffix(path, ".d.ts") || strings.HasSuffix(path, ".d.mts") || strings.HasSuffix(path, ".d.cts")
+}
+
+// commonSourceDirectory returns the longest directory that contains every
+// file in paths, which tsc uses as rootDir when it is not set.
+func commonSourceDirectory(paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	common := filepath.Dir(paths[0])
+	for _, path := range paths[1:] {
+		for !isUnder(path, common) {
+			parent := filepath.Dir(common)
+			if parent == common {
+				break
+			}
+			common = parent
+		}
+	}
+	return common
+}
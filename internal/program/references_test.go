@@ -0,0 +1,114 @@
+package program
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCreateProgram_ProjectReferences(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"tsconfig.json": `{
+			"files": [],
+			"references": [{ "path": "./packages/core" }, { "path": "./packages/app/tsconfig.json" }]
+		}`,
+		"packages/core/tsconfig.json": `{
+			"compilerOptions": { "composite": true, "rootDir": "src", "outDir": "dist" },
+			"include": ["src"]
+		}`,
+		"packages/core/src/index.ts": "export * from './util';\n",
+		"packages/core/src/util.ts":  "export const util = 1;\n",
+		"packages/app/tsconfig.json": `{
+			"compilerOptions": { "paths": { "@org/core": ["../core/dist/index.d.ts"] } },
+			"include": ["src"],
+			"references": [{ "path": "../core" }]
+		}`,
+		"packages/app/src/main.ts": "import { util } from '../../core/dist/util.js';\nimport * as core from '@org/core';\n",
+	})
+
+	root, err := CreateProgram(&ProgramOptions{TSConfigPath: filepath.Join(tmpDir, "tsconfig.json")})
+	if err != nil {
+		t.Fatalf("Failed to create program: %v", err)
+	}
+	if len(root.RootNames) != 0 {
+		t.Errorf("Expected a solution-style program without root names, got %v", root.RootNames)
+	}
+
+	refs := root.GetProjectReferences()
+	if len(refs) != 2 {
+		t.Fatalf("Expected 2 project references, got %d", len(refs))
+	}
+	core, app := refs[0], refs[1]
+	if appRefs := app.GetProjectReferences(); len(appRefs) != 1 || appRefs[0] != core {
+		t.Errorf("Expected app to share the program of core, got %v", appRefs)
+	}
+	if projects := root.Projects(); len(projects) != 3 {
+		t.Errorf("Expected 3 projects, got %d", len(projects))
+	}
+
+	coreIndex := filepath.Join(tmpDir, "packages", "core", "src", "index.ts")
+	main := filepath.Join(tmpDir, "packages", "app", "src", "main.ts")
+	tests := []struct {
+		file string
+		want *Program
+	}{
+		{file: coreIndex, want: core},
+		{file: main, want: app},
+		{file: filepath.Join(tmpDir, "packages", "app", "src", "new.ts"), want: app},
+		{file: filepath.Join(tmpDir, "scripts", "build.ts"), want: nil},
+	}
+	for _, tt := range tests {
+		if got := root.ProjectForFile(tt.file); got != tt.want {
+			t.Errorf("ProjectForFile(%s) returned the wrong program", tt.file)
+		}
+	}
+
+	imports := app.ModuleGraph().Imports(main)
+	if len(imports) != 2 {
+		t.Fatalf("Expected 2 imports, got %+v", imports)
+	}
+	if want := filepath.Join(tmpDir, "packages", "core", "src", "util.ts"); imports[0].ResolvedFileName != want {
+		t.Errorf("Expected the relative import to resolve to %s, got %s", want, imports[0].ResolvedFileName)
+	}
+	if imports[1].ResolvedFileName != coreIndex {
+		t.Errorf("Expected the paths import to resolve to %s, got %s", coreIndex, imports[1].ResolvedFileName)
+	}
+}
+
+func TestCreateProgram_ProjectReferenceErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name: "cycle",
+			files: map[string]string{
+				"tsconfig.json":   `{ "files": [], "references": [{ "path": "a" }] }`,
+				"a/tsconfig.json": `{ "files": [], "references": [{ "path": "../b" }] }`,
+				"b/tsconfig.json": `{ "files": [], "references": [{ "path": "../a" }] }`,
+			},
+			want: "circularity detected in project references",
+		},
+		{
+			name: "missing",
+			files: map[string]string{
+				"tsconfig.json": `{ "files": [], "references": [{ "path": "./missing" }] }`,
+			},
+			want: "failed to load project reference ./missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			writeFiles(t, tmpDir, tt.files)
+
+			_, err := CreateProgram(&ProgramOptions{TSConfigPath: filepath.Join(tmpDir, "tsconfig.json")})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
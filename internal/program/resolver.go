@@ -0,0 +1,727 @@
+package program
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ModuleResolutionKind is a module resolution strategy of tsc, as selected by
+// the moduleResolution compiler option.
+type ModuleResolutionKind string
+
+const (
+	// ModuleResolutionNode10 is the CommonJS resolution of Node.js before
+	// version 12: no "exports", and extensions and index files may be left out.
+	ModuleResolutionNode10 ModuleResolutionKind = "Node10"
+
+	// ModuleResolutionNode16 is the resolution of Node.js 16, with "exports"
+	// and "imports". ES module imports must name files in full.
+	ModuleResolutionNode16 ModuleResolutionKind = "Node16"
+
+	// ModuleResolutionNodeNext is the resolution of the latest Node.js, which
+	// is currently that of Node16.
+	ModuleResolutionNodeNext ModuleResolutionKind = "NodeNext"
+
+	// ModuleResolutionBundler is the resolution of bundlers: "exports" and
+	// "imports" with the "import" condition, while extensions and index
+	// files may be left out.
+	ModuleResolutionBundler ModuleResolutionKind = "Bundler"
+)
+
+// ResolutionMode is the kind of import a specifier is resolved for. It
+// selects the "import" or "require" condition of package.json "exports" and
+// "imports", and, for Node16 and NodeNext, whether extensions may be left out.
+type ResolutionMode int
+
+const (
+	// ResolutionModeDefault resolves in the mode implied by the importing
+	// file: for Node16 and NodeNext, by its extension or the "type" field of
+	// the nearest package.json; for Bundler, as an import.
+	ResolutionModeDefault ResolutionMode = iota
+
+	// ResolutionModeImport resolves an ES module import.
+	ResolutionModeImport
+
+	// ResolutionModeRequire resolves a CommonJS require.
+	ResolutionModeRequire
+)
+
+// ErrModuleNotFound is returned, wrapped, when a module cannot be resolved.
+var ErrModuleNotFound = errors.New("module not found")
+
+// ResolvedModule is the file that an import specifier resolves to.
+type ResolvedModule struct {
+	// ResolvedFileName is the absolute path of the file, with symbolic links
+	// resolved unless the preserveSymlinks compiler option is set.
+	ResolvedFileName string
+
+	// OriginalPath is the path the file was found at, if it differs from
+	// ResolvedFileName because symbolic links were resolved.
+	OriginalPath string
+
+	// Extension is the extension of the file, such as ".ts", ".d.ts" or ".js".
+	Extension string
+
+	// IsExternalLibraryImport reports whether the file was found in a
+	// node_modules directory.
+	IsExternalLibraryImport bool
+}
+
+// ModuleResolver resolves import specifiers to files as tsc does, following
+// the module resolution strategy of a configuration. It is safe for
+// concurrent use, and reads each package.json file once.
+type ModuleResolver struct {
+	// Trace, if not nil, is called with each step of every resolution, in the
+	// words of tsc --traceResolution. It must be safe for concurrent use if
+	// the resolver is used concurrently.
+	Trace func(message string)
+
+	options   *CompilerOptions
+	configDir string
+	kind      ModuleResolutionKind
+	explicit  bool // whether kind was set by moduleResolution
+
+	// virtualFile, if not nil, reports whether a file that does not exist is
+	// resolved to as if it did, as are the outputs of referenced projects
+	// that have not been built.
+	virtualFile func(path string) bool
+
+	// packageJSONs holds the package.json of each directory read, or nil if
+	// it has none.
+	packageJSONs sync.Map
+}
+
+// NewModuleResolver returns a resolver for the compiler options of config.
+// The strategy is that of the moduleResolution option or, if it is not set,
+// the one tsc implies from the module option. The Classic strategy is not
+// supported; it is resolved as Node10.
+func NewModuleResolver(config *TSConfig) *ModuleResolver {
+	r := &ModuleResolver{
+		options:   &config.CompilerOptions,
+		configDir: config.GetConfigDir(),
+		explicit:  config.CompilerOptions.ModuleResolution != "",
+	}
+
+	switch strings.ToLower(config.CompilerOptions.ModuleResolution) {
+	case "node16":
+		r.kind = ModuleResolutionNode16
+	case "nodenext":
+		r.kind = ModuleResolutionNodeNext
+	case "bundler":
+		r.kind = ModuleResolutionBundler
+	case "":
+		switch strings.ToLower(config.CompilerOptions.Module) {
+		case "node16":
+			r.kind = ModuleResolutionNode16
+		case "nodenext":
+			r.kind = ModuleResolutionNodeNext
+		case "preserve":
+			r.kind = ModuleResolutionBundler
+		default:
+			r.kind = ModuleResolutionNode10
+		}
+	default: // node, node10 and classic
+		r.kind = ModuleResolutionNode10
+	}
+	return r
+}
+
+// Kind returns the module resolution strategy of the resolver.
+func (r *ModuleResolver) Kind() ModuleResolutionKind {
+	return r.kind
+}
+
+// ResolveModule resolves specifier, imported by the file at containingFile,
+// in the given mode. It looks for TypeScript files and declarations first,
+// then JavaScript files, trying in turn:
+//
+//   - for a relative specifier, the file or directory it names, under each
+//     of the rootDirs if it is in one of them;
+//   - for other specifiers, the paths patterns, baseUrl, the "imports" of
+//     the nearest package.json for names starting with "#", and the
+//     packages, and their @types packages, in every node_modules directory
+//     from the importing file up.
+//
+// A ".js" extension also finds the ".ts", ".tsx" and ".d.ts" files that
+// compile to it, and likewise for ".jsx", ".mjs" and ".cjs". The returned
+// error wraps ErrModuleNotFound if the specifier cannot be resolved.
+func (r *ModuleResolver) ResolveModule(specifier, containingFile string, mode ResolutionMode) (*ResolvedModule, error) {
+	s := r.newResolution(containingFile, mode)
+	s.tracef("======== Resolving module '%s' from '%s'. ========", specifier, containingFile)
+	s.traceStrategy()
+
+	dir := filepath.Dir(containingFile)
+	for _, pass := range []extensionPass{typeScriptPass, javaScriptPass} {
+		if path, ok := s.resolve(specifier, dir, pass); ok {
+			resolved := s.result(path)
+			s.tracef("======== Module name '%s' was successfully resolved to '%s'. ========",
+				specifier, resolved.ResolvedFileName)
+			return resolved, nil
+		}
+	}
+
+	s.tracef("======== Module name '%s' was not resolved. ========", specifier)
+	return nil, fmt.Errorf("cannot resolve %q from %s: %w", specifier, containingFile, ErrModuleNotFound)
+}
+
+// ResolveTypeReferenceDirective resolves the name of a types package, as in
+// the types compiler option or a /// <reference types="..." /> directive in
+// containingFile. It looks in each of the typeRoots, by default the
+// node_modules/@types directories from the configuration directory up, and
+// then in node_modules directories like ResolveModule.
+func (r *ModuleResolver) ResolveTypeReferenceDirective(name, containingFile string) (*ResolvedModule, error) {
+	s := r.newResolution(containingFile, ResolutionModeDefault)
+	s.tracef("======== Resolving type reference directive '%s', containing file '%s'. ========", name, containingFile)
+
+	typeRoots := r.options.TypeRoots
+	if typeRoots == nil {
+		for dir := r.configDir; ; {
+			typeRoots = append(typeRoots, filepath.Join(dir, "node_modules", "@types"))
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				break
+			}
+			dir = parent
+		}
+	}
+
+	s.tracef("Resolving with primary search path '%s'.", strings.Join(typeRoots, ", "))
+	for _, root := range typeRoots {
+		candidate := filepath.Join(root, filepath.FromSlash(name))
+		if path, ok := s.loadDirectory(candidate, typeScriptPass); ok {
+			return s.typeReferenceResult(name, path), nil
+		}
+	}
+
+	s.tracef("Looking up in 'node_modules' folder, initial location '%s'.", filepath.Dir(containingFile))
+	if path, ok := s.loadFromNodeModules(name, filepath.Dir(containingFile), typeScriptPass); ok {
+		return s.typeReferenceResult(name, path), nil
+	}
+
+	s.tracef("======== Type reference directive '%s' was not resolved. ========", name)
+	return nil, fmt.Errorf("cannot resolve type reference directive %q from %s: %w", name, containingFile, ErrModuleNotFound)
+}
+
+func (s *resolution) typeReferenceResult(name, path string) *ResolvedModule {
+	resolved := s.result(path)
+	s.tracef("======== Type reference directive '%s' was successfully resolved to '%s'. ========",
+		name, resolved.ResolvedFileName)
+	return resolved
+}
+
+// ImpliedResolutionMode returns the mode in which imports in the file at
+// path are resolved by default: for Node16 and NodeNext, imports in .mts and
+// .mjs files, and in other files that the nearest package.json declares to be
+// of type "module", and requires otherwise; for Bundler, imports; and for
+// Node10, requires.
+func (r *ModuleResolver) ImpliedResolutionMode(path string) ResolutionMode {
+	switch r.kind {
+	case ModuleResolutionNode10:
+		return ResolutionModeRequire
+	case ModuleResolutionBundler:
+		return ResolutionModeImport
+	}
+
+	switch extensionOf(path) {
+	case ".mts", ".d.mts", ".mjs":
+		return ResolutionModeImport
+	case ".cts", ".d.cts", ".cjs":
+		return ResolutionModeRequire
+	}
+
+	if _, pkg := r.packageScope(filepath.Dir(path)); pkg != nil && pkg.Type == "module" {
+		return ResolutionModeImport
+	}
+	return ResolutionModeRequire
+}
+
+// extensionPass is one of the passes of a resolution: tsc first looks for
+// TypeScript files and declarations, and only then for JavaScript files.
+type extensionPass int
+
+const (
+	typeScriptPass extensionPass = iota
+	javaScriptPass
+)
+
+func (p extensionPass) String() string {
+	if p == typeScriptPass {
+		return "TypeScript, Declaration"
+	}
+	return "JavaScript"
+}
+
+// resolution is the state of one resolution.
+type resolution struct {
+	r          *ModuleResolver
+	mode       ResolutionMode
+	conditions map[string]bool // the conditions matched besides "default"
+	exports    bool            // whether package.json "exports" and "imports" are used
+	probe      bool            // whether extensions and index files may be left out
+}
+
+func (r *ModuleResolver) newResolution(containingFile string, mode ResolutionMode) *resolution {
+	if mode == ResolutionModeDefault {
+		mode = r.ImpliedResolutionMode(containingFile)
+	}
+
+	s := &resolution{r: r, mode: mode, conditions: make(map[string]bool)}
+	switch r.kind {
+	case ModuleResolutionNode10:
+		s.probe = true
+	case ModuleResolutionNode16, ModuleResolutionNodeNext:
+		s.exports = true
+		s.probe = mode == ResolutionModeRequire
+		s.conditions["node"] = true
+	case ModuleResolutionBundler:
+		s.exports = true
+		s.probe = true
+	}
+
+	if s.exports {
+		s.conditions["types"] = true
+		if mode == ResolutionModeImport {
+			s.conditions["import"] = true
+		} else {
+			s.conditions["require"] = true
+		}
+
+		var custom []string
+		if raw, ok := r.options.Unknown["customConditions"]; ok && json.Unmarshal(raw, &custom) == nil {
+			for _, condition := range custom {
+				s.conditions[condition] = true
+			}
+		}
+	}
+	return s
+}
+
+func (s *resolution) tracef(format string, args ...any) {
+	if s.r.Trace != nil {
+		s.r.Trace(fmt.Sprintf(format, args...))
+	}
+}
+
+func (s *resolution) traceStrategy() {
+	if s.r.Trace == nil {
+		return
+	}
+	if s.r.explicit {
+		s.tracef("Explicitly specified module resolution kind: '%s'.", s.r.kind)
+	} else {
+		s.tracef("Module resolution kind is not specified, using '%s'.", s.r.kind)
+	}
+	if s.exports {
+		var conditions []string
+		for condition := range s.conditions {
+			conditions = append(conditions, "'"+condition+"'")
+		}
+		slices.Sort(conditions)
+		system := "CJS"
+		if s.mode == ResolutionModeImport {
+			system = "ESM"
+		}
+		s.tracef("Resolving in %s mode with conditions %s.", system, strings.Join(conditions, ", "))
+	}
+}
+
+// resolve resolves specifier, imported from a file in dir, for one pass.
+func (s *resolution) resolve(specifier, dir string, pass extensionPass) (string, bool) {
+	if isRelativeSpecifier(specifier) || filepath.IsAbs(specifier) {
+		candidate := filepath.FromSlash(specifier)
+		if !filepath.IsAbs(candidate) {
+			candidate = filepath.Join(dir, candidate)
+		}
+		if path, ok := s.loadUsingRootDirs(candidate, pass); ok {
+			return path, true
+		}
+		return s.loadFileOrDirectory(candidate, pass)
+	}
+
+	if path, matched, ok := s.loadUsingPaths(specifier, pass); matched {
+		return path, ok
+	}
+	if baseURL := s.r.options.BaseUrl; baseURL != "" {
+		candidate := filepath.Join(baseURL, filepath.FromSlash(specifier))
+		s.tracef("Resolving module name '%s' relative to base url '%s' - '%s'.", specifier, baseURL, candidate)
+		if path, ok := s.loadFileOrDirectory(candidate, pass); ok {
+			return path, true
+		}
+	}
+
+	if strings.HasPrefix(specifier, "#") {
+		if !s.exports {
+			return "", false
+		}
+		return s.loadPackageImport(specifier, dir, pass)
+	}
+	return s.loadFromNodeModules(specifier, dir, pass)
+}
+
+// isRelativeSpecifier reports whether specifier is ".", "..", or starts with
+// "./" or "../".
+func isRelativeSpecifier(specifier string) bool {
+	return specifier == "." || specifier == ".." ||
+		strings.HasPrefix(specifier, "./") || strings.HasPrefix(specifier, "../")
+}
+
+// loadUsingPaths resolves specifier with the paths compiler option. It
+// reports whether a pattern matched, in which case baseUrl and node_modules
+// are not tried if the resolution fails.
+func (s *resolution) loadUsingPaths(specifier string, pass extensionPass) (path string, matched, ok bool) {
+	paths := s.r.options.Paths
+	if len(paths) == 0 {
+		return "", false, false
+	}
+	s.tracef("'paths' option is specified, looking for a pattern to match module name '%s'.", specifier)
+
+	pattern, match, found := matchPathsPattern(paths, specifier)
+	if !found {
+		return "", false, false
+	}
+	s.tracef("Module name '%s', matched pattern '%s'.", specifier, pattern)
+
+	base := s.r.options.BaseUrl
+	if base == "" {
+		base = s.r.options.PathsBasePath
+	}
+	for _, substitution := range paths[pattern] {
+		candidate := filepath.FromSlash(strings.Replace(substitution, "*", match, 1))
+		if !filepath.IsAbs(candidate) {
+			candidate = filepath.Join(base, candidate)
+		}
+		s.tracef("Trying substitution '%s', candidate module location: '%s'.", substitution, candidate)
+		if path, ok := s.loadFileOrDirectory(candidate, pass); ok {
+			return path, true, true
+		}
+	}
+	return "", true, false
+}
+
+// matchPathsPattern finds the pattern of paths that matches specifier: the
+// same string, or else the pattern with a "*" whose prefix is longest. It
+// returns the part of specifier that the "*" matches.
+func matchPathsPattern(paths map[string][]string, specifier string) (pattern, match string, ok bool) {
+	if _, exact := paths[specifier]; exact {
+		return specifier, "", true
+	}
+
+	longest := -1
+	for p := range paths {
+		prefix, suffix, hasStar := strings.Cut(p, "*")
+		if !hasStar || len(specifier) < len(prefix)+len(suffix) ||
+			!strings.HasPrefix(specifier, prefix) || !strings.HasSuffix(specifier, suffix) {
+			continue
+		}
+		// Ties are broken by the pattern itself, so that the result does not
+		// depend on map order.
+		if len(prefix) > longest || (len(prefix) == longest && p < pattern) {
+			longest = len(prefix)
+			pattern, match = p, specifier[len(prefix):len(specifier)-len(suffix)]
+		}
+	}
+	return pattern, match, longest >= 0
+}
+
+// loadUsingRootDirs resolves a relative import whose candidate location is in
+// one of the rootDirs by looking for it under each of them, as they are
+// merged into one directory at run time.
+func (s *resolution) loadUsingRootDirs(candidate string, pass extensionPass) (string, bool) {
+	rootDirs := s.r.options.RootDirs
+	if len(rootDirs) == 0 {
+		return "", false
+	}
+	s.tracef("'rootDirs' option is set, using it to resolve relative module name '%s'.", candidate)
+
+	matched := ""
+	for _, dir := range rootDirs {
+		if isUnder(candidate, dir) && len(dir) > len(matched) {
+			matched = dir
+		}
+	}
+	if matched == "" {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(matched, candidate)
+	if err != nil {
+		return "", false
+	}
+	s.tracef("Longest matching prefix for '%s' is '%s'.", candidate, matched)
+	if path, ok := s.loadFileOrDirectory(candidate, pass); ok {
+		return path, true
+	}
+	for _, dir := range rootDirs {
+		if dir == matched {
+			continue
+		}
+		if path, ok := s.loadFileOrDirectory(filepath.Join(dir, rel), pass); ok {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// isUnder reports whether path is dir or is inside it.
+func isUnder(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// loadFileOrDirectory loads candidate as a file or, if extensions and index
+// files may be left out, as a directory.
+func (s *resolution) loadFileOrDirectory(candidate string, pass extensionPass) (string, bool) {
+	s.tracef("Loading module as file / folder, candidate module location '%s', target file types: %s.", candidate, pass)
+	if path, ok := s.loadFile(candidate, pass, s.probe); ok {
+		return path, true
+	}
+	if !s.probe {
+		return "", false
+	}
+	return s.loadDirectory(candidate, pass)
+}
+
+// extensionOf returns the extension of path that module resolution
+// recognizes, or "" if it has none of them.
+func extensionOf(path string) string {
+	for _, ext := range []string{".d.ts", ".d.mts", ".d.cts", ".ts", ".tsx", ".mts", ".cts", ".js", ".jsx", ".mjs", ".cjs", ".json"} {
+		if strings.HasSuffix(path, ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
+// loadFile loads candidate as a file. A JavaScript extension is replaced
+// with those of the TypeScript files that compile to it in the TypeScript
+// pass; with probe, a candidate without a known extension is tried with each
+// extension of the pass.
+func (s *resolution) loadFile(candidate string, pass extensionPass, probe bool) (string, bool) {
+	ext := extensionOf(candidate)
+	base := strings.TrimSuffix(candidate, ext)
+
+	var ts, js []string
+	switch ext {
+	case ".js":
+		ts, js = []string{".ts", ".tsx", ".d.ts"}, []string{".js", ".jsx"}
+	case ".jsx":
+		ts, js = []string{".tsx", ".d.ts"}, []string{".jsx"}
+	case ".mjs":
+		ts, js = []string{".mts", ".d.mts"}, []string{".mjs"}
+	case ".cjs":
+		ts, js = []string{".cts", ".d.cts"}, []string{".cjs"}
+	case ".json":
+		if s.r.options.ResolveJsonModule != nil && *s.r.options.ResolveJsonModule {
+			ts = []string{".json"}
+		}
+	case "":
+		if !probe {
+			return "", false
+		}
+		ts, js = []string{".ts", ".tsx", ".d.ts"}, []string{".js", ".jsx"}
+	default: // a TypeScript extension
+		ts = []string{ext}
+	}
+
+	extensions := ts
+	if pass == javaScriptPass {
+		extensions = js
+	}
+	for _, e := range extensions {
+		if path := base + e; s.fileExists(path) {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// loadDirectory loads dir as a module: the file named by the "types",
+// "typings" or "main" field of its package.json, or else its index file.
+func (s *resolution) loadDirectory(dir string, pass extensionPass) (string, bool) {
+	if !isDirectory(dir) {
+		s.tracef("Directory '%s' does not exist, skipping all lookups in it.", dir)
+		return "", false
+	}
+
+	if pkg := s.r.readPackageJSON(dir); pkg != nil {
+		s.tracef("Found 'package.json' at '%s'.", filepath.Join(dir, "package.json"))
+
+		fields := []struct{ name, value string }{{"main", pkg.Main}}
+		if pass == typeScriptPass {
+			fields = []struct{ name, value string }{{"types", pkg.Types}, {"typings", pkg.Typings}, {"main", pkg.Main}}
+		}
+		for _, field := range fields {
+			if field.value == "" {
+				continue
+			}
+			path := filepath.Join(dir, filepath.FromSlash(field.value))
+			s.tracef("'package.json' has '%s' field '%s' that references '%s'.", field.name, field.value, path)
+			if resolved, ok := s.loadFile(path, pass, true); ok {
+				return resolved, true
+			}
+			if isDirectory(path) {
+				if resolved, ok := s.loadFile(filepath.Join(path, "index"), pass, true); ok {
+					return resolved, true
+				}
+			}
+		}
+	}
+
+	return s.loadFile(filepath.Join(dir, "index"), pass, true)
+}
+
+// loadFromNodeModules loads the package specifier names, or a file in it,
+// from the nearest node_modules directory from dir up that has it. In the
+// TypeScript pass, the package's @types package is tried after the package
+// in each directory.
+func (s *resolution) loadFromNodeModules(specifier, dir string, pass extensionPass) (string, bool) {
+	s.tracef("Loading module '%s' from 'node_modules' folder, target file types: %s.", specifier, pass)
+	name, subpath := splitPackageName(specifier)
+
+	for {
+		if filepath.Base(dir) != "node_modules" {
+			nodeModules := filepath.Join(dir, "node_modules")
+			if isDirectory(nodeModules) {
+				if path, ok := s.loadPackage(nodeModules, name, subpath, pass); ok {
+					return path, true
+				}
+				if pass == typeScriptPass {
+					typesDir := filepath.Join(nodeModules, "@types")
+					if path, ok := s.loadPackage(typesDir, typesPackageName(name), subpath, pass); ok {
+						return path, true
+					}
+				}
+			} else {
+				s.tracef("Directory '%s' does not exist, skipping all lookups in it.", nodeModules)
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// typesPackageName returns the name of the @types package for a package:
+// "@scope/name" becomes "scope__name".
+func typesPackageName(name string) string {
+	if scope, rest, ok := strings.Cut(strings.TrimPrefix(name, "@"), "/"); ok && strings.HasPrefix(name, "@") {
+		return scope + "__" + rest
+	}
+	return name
+}
+
+// loadPackage loads subpath, which is empty for the package itself, from
+// the package name in the node_modules directory nodeModules. If the package
+// has "exports" and they are used, only exported files can be loaded.
+func (s *resolution) loadPackage(nodeModules, name, subpath string, pass extensionPass) (string, bool) {
+	dir := filepath.Join(nodeModules, filepath.FromSlash(name))
+	if !isDirectory(dir) {
+		return "", false
+	}
+
+	pkg := s.r.readPackageJSON(dir)
+	if s.exports && pkg != nil && pkg.Exports != nil {
+		s.tracef("Found 'package.json' at '%s'.", filepath.Join(dir, "package.json"))
+		target, ok := resolveExports(pkg.Exports, subpath, s.conditions)
+		if !ok {
+			s.tracef("Export specifier '%s' does not exist in package.json scope path '%s'.", "./"+subpath, dir)
+			return "", false
+		}
+		s.tracef("Using 'exports' subpath '%s' with target '%s'.", "./"+subpath, target)
+		return s.loadFile(filepath.Join(dir, filepath.FromSlash(target)), pass, false)
+	}
+
+	if subpath == "" {
+		return s.loadDirectory(dir, pass)
+	}
+	return s.loadFileOrDirectory(filepath.Join(dir, filepath.FromSlash(subpath)), pass)
+}
+
+// loadPackageImport loads specifier, which starts with "#", from the
+// "imports" of the nearest package.json from dir up.
+func (s *resolution) loadPackageImport(specifier, dir string, pass extensionPass) (string, bool) {
+	scope, pkg := s.r.packageScope(dir)
+	if pkg == nil || pkg.Imports == nil {
+		s.tracef("Directory '%s' has no containing package.json scope with 'imports'.", dir)
+		return "", false
+	}
+
+	target, ok := resolveImports(pkg.Imports, specifier, s.conditions)
+	if !ok {
+		s.tracef("Import specifier '%s' does not exist in package.json scope at path '%s'.", specifier, scope)
+		return "", false
+	}
+	s.tracef("Using 'imports' subpath '%s' with target '%s'.", specifier, target)
+
+	if strings.HasPrefix(target, "./") {
+		return s.loadFile(filepath.Join(scope, filepath.FromSlash(target)), pass, false)
+	}
+	return s.loadFromNodeModules(target, scope, pass)
+}
+
+// result returns the resolved module for the file at path.
+func (s *resolution) result(path string) *ResolvedModule {
+	resolved := &ResolvedModule{
+		ResolvedFileName:        path,
+		Extension:               extensionOf(path),
+		IsExternalLibraryImport: slices.Contains(strings.Split(filepath.ToSlash(path), "/"), "node_modules"),
+	}
+
+	if preserve := s.r.options.PreserveSymlinks; preserve == nil || !*preserve {
+		if real, err := filepath.EvalSymlinks(path); err == nil && real != path {
+			s.tracef("Resolving real path for '%s', result '%s'.", path, real)
+			resolved.ResolvedFileName = real
+			resolved.OriginalPath = path
+		}
+	}
+	return resolved
+}
+
+func (s *resolution) fileExists(path string) bool {
+	if info, err := os.Stat(path); (err == nil && !info.IsDir()) || (err != nil && s.r.virtualFile != nil && s.r.virtualFile(path)) {
+		s.tracef("File '%s' exists - use it as a name resolution result.", path)
+		return true
+	}
+	s.tracef("File '%s' does not exist.", path)
+	return false
+}
+
+func isDirectory(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// readPackageJSON returns the package.json in dir, reading it only once.
+func (r *ModuleResolver) readPackageJSON(dir string) *packageJSON {
+	if pkg, ok := r.packageJSONs.Load(dir); ok {
+		return pkg.(*packageJSON)
+	}
+	pkg, _ := r.packageJSONs.LoadOrStore(dir, readPackageJSON(dir))
+	return pkg.(*packageJSON)
+}
+
+// packageScope returns the directory of the nearest package.json from dir
+// up, and its contents, or nil if there is none.
+func (r *ModuleResolver) packageScope(dir string) (string, *packageJSON) {
+	for {
+		if filepath.Base(dir) != "node_modules" {
+			if pkg := r.readPackageJSON(dir); pkg != nil {
+				return dir, pkg
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,358 @@
+package program
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestModuleResolver_ResolveModule(t *testing.T) {
+	files := map[string]string{
+		"src/main.ts":        "",
+		"src/util.ts":        "",
+		"src/view.tsx":       "",
+		"src/types.d.ts":     "",
+		"src/legacy.js":      "",
+		"src/esm.mts":        "",
+		"src/data.json":      "{}",
+		"src/lib/index.ts":   "",
+		"src/gen/out.ts":     "",
+		"generated/templ.ts": "",
+		"package.json":       `{ "name": "app", "imports": { "#internal/*": "./src/*.js", "#dep": "dep" } }`,
+
+		"node_modules/plain/package.json":     `{ "types": "./types/index.d.ts", "main": "./lib/index.js" }`,
+		"node_modules/plain/types/index.d.ts": "",
+		"node_modules/plain/lib/index.js":     "",
+		"node_modules/plain/lib/extra.js":     "",
+
+		"node_modules/untyped/index.js":             "",
+		"node_modules/@types/untyped/index.d.ts":    "",
+		"node_modules/@scope/pkg/index.js":          "",
+		"node_modules/@types/scope__pkg/index.d.ts": "",
+		"node_modules/jsonly/package.json":          `{ "main": "main.js" }`,
+		"node_modules/jsonly/main.js":               "",
+
+		"node_modules/dep/package.json": `{
+			"exports": {
+				".": { "types": "./dist/index.d.ts", "import": "./dist/index.mjs", "default": "./dist/index.cjs" },
+				"./feature/*": { "import": "./dist/feature/*.mjs", "require": "./dist/feature/*.cjs" },
+				"./package.json": "./package.json"
+			},
+			"types": "./legacy.d.ts"
+		}`,
+		"node_modules/dep/dist/index.d.ts":      "",
+		"node_modules/dep/dist/index.mjs":       "",
+		"node_modules/dep/dist/feature/a.mjs":   "",
+		"node_modules/dep/dist/feature/a.cjs":   "",
+		"node_modules/dep/dist/feature/a.d.mts": "",
+		"node_modules/dep/dist/hidden.d.ts":     "",
+		"node_modules/dep/legacy.d.ts":          "",
+	}
+
+	tests := []struct {
+		name      string
+		options   CompilerOptions
+		specifier string
+		from      string
+		mode      ResolutionMode
+		want      string // empty when the module is not resolved
+		external  bool
+	}{
+		{name: "relative without extension", specifier: "./util", want: "src/util.ts"},
+		{name: "js extension maps to ts", specifier: "./util.js", want: "src/util.ts"},
+		{name: "jsx extension maps to tsx", specifier: "./view.jsx", want: "src/view.tsx"},
+		{name: "mjs extension maps to mts", specifier: "./esm.mjs", want: "src/esm.mts"},
+		{name: "declaration", specifier: "./types", want: "src/types.d.ts"},
+		{name: "javascript fallback", specifier: "./legacy", want: "src/legacy.js"},
+		{name: "directory index", specifier: "./lib", want: "src/lib/index.ts"},
+		{name: "missing", specifier: "./missing"},
+		{name: "json without resolveJsonModule", specifier: "./data.json"},
+		{
+			name:      "json with resolveJsonModule",
+			options:   CompilerOptions{ResolveJsonModule: boolPtr(true)},
+			specifier: "./data.json",
+			want:      "src/data.json",
+		},
+		{name: "package types field", specifier: "plain", want: "node_modules/plain/types/index.d.ts", external: true},
+		{name: "package subpath", specifier: "plain/lib/extra", want: "node_modules/plain/lib/extra.js", external: true},
+		{name: "types package", specifier: "untyped", want: "node_modules/@types/untyped/index.d.ts", external: true},
+		{name: "scoped types package", specifier: "@scope/pkg", want: "node_modules/@types/scope__pkg/index.d.ts", external: true},
+		{name: "javascript package", specifier: "jsonly", want: "node_modules/jsonly/main.js", external: true},
+		{name: "node10 ignores exports", specifier: "dep", want: "node_modules/dep/legacy.d.ts", external: true},
+		{name: "node10 ignores imports", specifier: "#dep"},
+		{
+			name:      "paths",
+			options:   CompilerOptions{Paths: map[string][]string{"@app/*": {"src/*", "generated/*"}}, PathsBasePath: "."},
+			specifier: "@app/templ",
+			want:      "generated/templ.ts",
+		},
+		{
+			name:      "paths longest prefix",
+			options:   CompilerOptions{Paths: map[string][]string{"*": {"missing/*"}, "@app/*": {"src/*"}}, PathsBasePath: "."},
+			specifier: "@app/util",
+			want:      "src/util.ts",
+		},
+		{
+			name:      "matched paths do not fall back to node_modules",
+			options:   CompilerOptions{Paths: map[string][]string{"plain": {"missing"}}, PathsBasePath: "."},
+			specifier: "plain",
+		},
+		{
+			name:      "baseUrl",
+			options:   CompilerOptions{BaseUrl: "src"},
+			specifier: "lib",
+			want:      "src/lib/index.ts",
+		},
+		{
+			name:      "rootDirs",
+			options:   CompilerOptions{RootDirs: []string{"src", "src/gen"}},
+			specifier: "./gen/../out",
+			want:      "src/gen/out.ts",
+		},
+		{
+			name:      "node16 import requires extensions",
+			options:   CompilerOptions{ModuleResolution: "node16"},
+			specifier: "./util",
+			mode:      ResolutionModeImport,
+		},
+		{
+			name:      "node16 import with extension",
+			options:   CompilerOptions{ModuleResolution: "node16"},
+			specifier: "./util.js",
+			mode:      ResolutionModeImport,
+			want:      "src/util.ts",
+		},
+		{
+			name:      "node16 require probes",
+			options:   CompilerOptions{ModuleResolution: "Node16"},
+			specifier: "./util",
+			mode:      ResolutionModeRequire,
+			want:      "src/util.ts",
+		},
+		{
+			name:      "node16 file in esm mode",
+			options:   CompilerOptions{ModuleResolution: "nodenext"},
+			specifier: "./util",
+			from:      "src/esm.mts",
+		},
+		{
+			name:      "exports types condition",
+			options:   CompilerOptions{ModuleResolution: "nodenext"},
+			specifier: "dep",
+			mode:      ResolutionModeImport,
+			want:      "node_modules/dep/dist/index.d.ts",
+			external:  true,
+		},
+		{
+			name:      "exports pattern with import condition",
+			options:   CompilerOptions{Module: "NodeNext"},
+			specifier: "dep/feature/a",
+			mode:      ResolutionModeImport,
+			want:      "node_modules/dep/dist/feature/a.d.mts",
+			external:  true,
+		},
+		{
+			name:      "exports pattern with require condition",
+			options:   CompilerOptions{Module: "NodeNext"},
+			specifier: "dep/feature/a",
+			mode:      ResolutionModeRequire,
+			want:      "node_modules/dep/dist/feature/a.cjs",
+			external:  true,
+		},
+		{
+			name:      "exports hide other files",
+			options:   CompilerOptions{ModuleResolution: "bundler"},
+			specifier: "dep/dist/hidden",
+		},
+		{
+			name:      "imports pattern",
+			options:   CompilerOptions{ModuleResolution: "bundler"},
+			specifier: "#internal/util",
+			want:      "src/util.ts",
+		},
+		{
+			name:      "imports to package",
+			options:   CompilerOptions{ModuleResolution: "bundler"},
+			specifier: "#dep",
+			want:      "node_modules/dep/dist/index.d.ts",
+			external:  true,
+		},
+		{
+			name:      "bundler probes",
+			options:   CompilerOptions{ModuleResolution: "bundler"},
+			specifier: "./lib",
+			want:      "src/lib/index.ts",
+		},
+	}
+
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, files)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := tt.options
+			if options.PathsBasePath != "" {
+				options.PathsBasePath = filepath.Join(tmpDir, options.PathsBasePath)
+			}
+			if options.BaseUrl != "" {
+				options.BaseUrl = filepath.Join(tmpDir, options.BaseUrl)
+			}
+			options.RootDirs = slices.Clone(options.RootDirs)
+			for i, dir := range options.RootDirs {
+				options.RootDirs[i] = filepath.Join(tmpDir, filepath.FromSlash(dir))
+			}
+			resolver := NewModuleResolver(&TSConfig{path: filepath.Join(tmpDir, "tsconfig.json"), CompilerOptions: options})
+
+			from := tt.from
+			if from == "" {
+				from = "src/main.ts"
+			}
+			resolved, err := resolver.ResolveModule(tt.specifier, filepath.Join(tmpDir, filepath.FromSlash(from)), tt.mode)
+
+			if tt.want == "" {
+				if !errors.Is(err, ErrModuleNotFound) {
+					t.Fatalf("Expected ErrModuleNotFound, got %+v, %v", resolved, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to resolve %s: %v", tt.specifier, err)
+			}
+			if want := filepath.Join(tmpDir, filepath.FromSlash(tt.want)); resolved.ResolvedFileName != want {
+				t.Errorf("Expected %s, got %s", want, resolved.ResolvedFileName)
+			}
+			if resolved.IsExternalLibraryImport != tt.external {
+				t.Errorf("Expected IsExternalLibraryImport %v, got %v", tt.external, resolved.IsExternalLibraryImport)
+			}
+		})
+	}
+}
+
+func TestModuleResolver_ImpliedResolutionMode(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"esm/package.json": `{ "type": "module" }`,
+		"cjs/package.json": `{ "type": "commonjs" }`,
+	})
+
+	tests := []struct {
+		kind string
+		file string
+		want ResolutionMode
+	}{
+		{kind: "nodenext", file: "esm/a.ts", want: ResolutionModeImport},
+		{kind: "nodenext", file: "esm/a.cts", want: ResolutionModeRequire},
+		{kind: "nodenext", file: "cjs/a.ts", want: ResolutionModeRequire},
+		{kind: "nodenext", file: "cjs/a.mts", want: ResolutionModeImport},
+		{kind: "node16", file: "a.ts", want: ResolutionModeRequire},
+		{kind: "bundler", file: "cjs/a.ts", want: ResolutionModeImport},
+		{kind: "node10", file: "esm/a.ts", want: ResolutionModeRequire},
+	}
+
+	for _, tt := range tests {
+		resolver := NewModuleResolver(&TSConfig{CompilerOptions: CompilerOptions{ModuleResolution: tt.kind}})
+		if got := resolver.ImpliedResolutionMode(filepath.Join(tmpDir, filepath.FromSlash(tt.file))); got != tt.want {
+			t.Errorf("ImpliedResolutionMode(%s) with %s = %v, expected %v", tt.file, tt.kind, got, tt.want)
+		}
+	}
+}
+
+func TestModuleResolver_Symlinks(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"packages/shared/package.json": `{ "types": "index.d.ts" }`,
+		"packages/shared/index.d.ts":   "",
+		"app/main.ts":                  "",
+	})
+	link := filepath.Join(tmpDir, "app", "node_modules", "shared")
+	if err := os.MkdirAll(filepath.Dir(link), 0o750); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(tmpDir, "packages", "shared"), link); err != nil {
+		t.Skipf("Symbolic links are not supported: %v", err)
+	}
+	real, err := filepath.EvalSymlinks(filepath.Join(tmpDir, "packages", "shared", "index.d.ts"))
+	if err != nil {
+		t.Fatalf("Failed to evaluate symbolic links: %v", err)
+	}
+	main := filepath.Join(tmpDir, "app", "main.ts")
+
+	resolved, err := NewModuleResolver(&TSConfig{}).ResolveModule("shared", main, ResolutionModeDefault)
+	if err != nil {
+		t.Fatalf("Failed to resolve: %v", err)
+	}
+	if resolved.ResolvedFileName != real || resolved.OriginalPath != filepath.Join(link, "index.d.ts") {
+		t.Errorf("Expected %s from %s, got %+v", real, link, resolved)
+	}
+
+	preserved := &TSConfig{CompilerOptions: CompilerOptions{PreserveSymlinks: boolPtr(true)}}
+	resolved, err = NewModuleResolver(preserved).ResolveModule("shared", main, ResolutionModeDefault)
+	if err != nil {
+		t.Fatalf("Failed to resolve: %v", err)
+	}
+	if resolved.ResolvedFileName != filepath.Join(link, "index.d.ts") || resolved.OriginalPath != "" {
+		t.Errorf("Expected the symbolic link to be preserved, got %+v", resolved)
+	}
+}
+
+func TestModuleResolver_ResolveTypeReferenceDirective(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"node_modules/@types/node/index.d.ts": "",
+		"typings/custom/index.d.ts":           "",
+		"src/main.ts":                         "",
+	})
+	main := filepath.Join(tmpDir, "src", "main.ts")
+	config := &TSConfig{path: filepath.Join(tmpDir, "tsconfig.json")}
+
+	resolved, err := NewModuleResolver(config).ResolveTypeReferenceDirective("node", main)
+	if err != nil {
+		t.Fatalf("Failed to resolve: %v", err)
+	}
+	if want := filepath.Join(tmpDir, "node_modules", "@types", "node", "index.d.ts"); resolved.ResolvedFileName != want {
+		t.Errorf("Expected %s, got %s", want, resolved.ResolvedFileName)
+	}
+
+	config.CompilerOptions.TypeRoots = []string{filepath.Join(tmpDir, "typings")}
+	resolved, err = NewModuleResolver(config).ResolveTypeReferenceDirective("custom", main)
+	if err != nil {
+		t.Fatalf("Failed to resolve: %v", err)
+	}
+	if want := filepath.Join(tmpDir, "typings", "custom", "index.d.ts"); resolved.ResolvedFileName != want {
+		t.Errorf("Expected %s, got %s", want, resolved.ResolvedFileName)
+	}
+
+	if _, err := NewModuleResolver(config).ResolveTypeReferenceDirective("missing", main); !errors.Is(err, ErrModuleNotFound) {
+		t.Errorf("Expected ErrModuleNotFound, got %v", err)
+	}
+}
+
+func TestModuleResolver_Trace(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{"src/util.ts": ""})
+
+	var trace []string
+	resolver := NewModuleResolver(&TSConfig{CompilerOptions: CompilerOptions{ModuleResolution: "bundler"}})
+	resolver.Trace = func(message string) { trace = append(trace, message) }
+
+	main := filepath.Join(tmpDir, "src", "main.ts")
+	if _, err := resolver.ResolveModule("./util", main, ResolutionModeDefault); err != nil {
+		t.Fatalf("Failed to resolve: %v", err)
+	}
+
+	util := filepath.Join(tmpDir, "src", "util.ts")
+	for _, want := range []string{
+		"======== Resolving module './util' from '" + main + "'. ========",
+		"Explicitly specified module resolution kind: 'Bundler'.",
+		"Resolving in ESM mode with conditions 'import', 'types'.",
+		"File '" + util + "' exists - use it as a name resolution result.",
+		"======== Module name './util' was successfully resolved to '" + util + "'. ========",
+	} {
+		if !slices.Contains(trace, want) {
+			t.Errorf("Expected trace to contain %q, got:\n%s", want, strings.Join(trace, "\n"))
+		}
+	}
+}
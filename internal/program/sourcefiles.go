@@ -0,0 +1,256 @@
+package program
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kdy1/go-typescript-eslint/internal/converter"
+	"github.com/kdy1/go-typescript-eslint/internal/parser"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
+)
+
+// Diagnostic is a problem found while reading or parsing a source file of a
+// program. Line and Column are 1-based, and 0 when the problem has no
+// position, as when the file cannot be read.
+type Diagnostic struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// Error implements the error interface.
+func (d Diagnostic) Error() string {
+	if d.Line == 0 {
+		return fmt.Sprintf("%s: %s", d.File, d.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", d.File, d.Line, d.Column, d.Message)
+}
+
+// parsedFile is the result of parsing one source file.
+type parsedFile struct {
+	ast         *ast.Program
+	diagnostics []Diagnostic
+}
+
+// parseSourceFiles parses the root files of the program concurrently, with
+// at most GOMAXPROCS files parsed at once, and records their ASTs and
+// diagnostics. Relative root names are read relative to rootDir.
+func (p *Program) parseSourceFiles(rootDir string) {
+	results := make([]parsedFile, len(p.RootNames))
+
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for w := 0; w < min(runtime.GOMAXPROCS(0), len(p.RootNames)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				path := p.RootNames[i]
+				if !filepath.IsAbs(path) && rootDir != "" {
+					path = filepath.Join(rootDir, path)
+				}
+				results[i] = parseSourceFile(p.RootNames[i], path, &p.Config.CompilerOptions)
+			}
+		}()
+	}
+	for i := range p.RootNames {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, name := range p.RootNames {
+		if results[i].ast != nil {
+			p.SourceFiles[name] = results[i].ast
+		}
+		if len(results[i].diagnostics) > 0 {
+			p.diagnostics[name] = results[i].diagnostics
+		}
+	}
+}
+
+// rootFilePaths returns the absolute paths of the root files, reading
+// relative root names relative to the root directory, as parseSourceFiles
+// does.
+func (p *Program) rootFilePaths() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	paths := make([]string, 0, len(p.RootNames))
+	for _, name := range p.RootNames {
+		if !filepath.IsAbs(name) && p.rootDir != "" {
+			name = filepath.Join(p.rootDir, name)
+		}
+		if path, err := filepath.Abs(name); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// parseSourceFile parses the file named name, read from path, with the
+// parser options its extension and options call for.
+func parseSourceFile(name, path string, options *CompilerOptions) parsedFile {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is a file of the program
+	if err != nil {
+		return parsedFile{diagnostics: []Diagnostic{{File: name, Message: fmt.Sprintf("cannot read file: %v", err)}}}
+	}
+	source := string(data)
+
+	p := parser.New(source)
+	p.SetJSXEnabled(jsxEnabled(name))
+	program, _, _ := p.ParseUntil(nil)
+
+	var diagnostics []Diagnostic
+	for _, e := range p.Errors() {
+		diagnostics = append(diagnostics, Diagnostic{File: name, Line: e.Line, Column: e.Column, Message: e.Message})
+	}
+
+	file := converter.NewConverter(source, &converter.Options{SetParents: true}).ConvertProgram(program)
+	file.SourceType = sourceType(name, file, options)
+	return parsedFile{ast: file, diagnostics: diagnostics}
+}
+
+// jsxEnabled reports whether a file may contain JSX: .tsx files and
+// JavaScript files may, as tsc parses every JavaScript file with JSX, and
+// other TypeScript files never.
+func jsxEnabled(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".tsx", ".jsx", ".js", ".mjs", ".cjs":
+		return true
+	default:
+		return false
+	}
+}
+
+// sourceType returns the ESTree source type of a file, deciding as tsc does
+// whether it is a module: .mjs and .mts files are, as is every file when
+// moduleDetection is "force"; other files are modules if they have a
+// top-level import or export.
+func sourceType(name string, file *ast.Program, options *CompilerOptions) string {
+	lower := strings.ToLower(name)
+	if strings.HasSuffix(lower, ".mjs") || strings.HasSuffix(lower, ".mts") {
+		return "module"
+	}
+
+	var detection string
+	if raw, ok := options.Unknown["moduleDetection"]; ok && json.Unmarshal(raw, &detection) == nil &&
+		strings.EqualFold(detection, "force") {
+		return "module"
+	}
+
+	for _, stmt := range file.Body {
+		switch stmt.Type() {
+		case ast.NodeTypeImportDeclaration.String(),
+			ast.NodeTypeExportNamedDeclaration.String(),
+			ast.NodeTypeExportDefaultDeclaration.String(),
+			ast.NodeTypeExportAllDeclaration.String(),
+			ast.NodeTypeTSExportAssignment.String():
+			return "module"
+		case ast.NodeTypeTSImportEqualsDeclaration.String():
+			// import fs = require("fs") makes a module; import A = B.C does not.
+			if decl, ok := stmt.(*ast.TSImportEqualsDeclaration); ok {
+				if ref, ok := decl.ModuleReference.(ast.Node); ok &&
+					ref.Type() == ast.NodeTypeTSExternalModuleReference.String() {
+					return "module"
+				}
+			}
+		}
+	}
+	return "script"
+}
+
+// addRootFile parses the file at path and adds it to the root names of the
+// program, with its diagnostics, leaving the other source files as they are.
+func (p *Program) addRootFile(path string) {
+	parsed := parseSourceFile(path, path, &p.Config.CompilerOptions)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.RootNames = append(p.RootNames, path)
+	if parsed.ast != nil {
+		p.SourceFiles[path] = parsed.ast
+	}
+	if len(parsed.diagnostics) > 0 {
+		p.diagnostics[path] = parsed.diagnostics
+	}
+	p.graph = nil
+	p.outputs = nil
+}
+
+// SourceFileNames returns the names of the parsed source files of the
+// program: its root names that were parsed, in order, followed by any other
+// files added with AddSourceFile, sorted.
+func (p *Program) SourceFileNames() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.sourceFileNames()
+}
+
+// sourceFileNames is SourceFileNames for callers holding p.mu.
+func (p *Program) sourceFileNames() []string {
+	names := make([]string, 0, len(p.SourceFiles))
+	roots := make(map[string]bool, len(p.RootNames))
+	for _, name := range p.RootNames {
+		if _, ok := p.SourceFiles[name]; ok && !roots[name] {
+			names = append(names, name)
+		}
+		roots[name] = true
+	}
+
+	var others []string
+	for name := range p.SourceFiles {
+		if !roots[name] {
+			others = append(others, name)
+		}
+	}
+	sort.Strings(others)
+	return append(names, others...)
+}
+
+// ForEachSourceFile calls fn with each source file of the program and its
+// name, in the order of SourceFileNames, until fn returns false.
+func (p *Program) ForEachSourceFile(fn func(name string, file *ast.Program) bool) {
+	for _, name := range p.SourceFileNames() {
+		file, ok := p.GetSourceFile(name)
+		if ok && !fn(name, file) {
+			return
+		}
+	}
+}
+
+// GetDiagnostics returns the diagnostics recorded for a source file.
+func (p *Program) GetDiagnostics(filePath string) []Diagnostic {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.diagnostics[filePath]
+}
+
+// GetAllDiagnostics returns the diagnostics of all source files, in the
+// order of the program's root names.
+func (p *Program) GetAllDiagnostics() []Diagnostic {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var all []Diagnostic
+	seen := make(map[string]bool, len(p.RootNames))
+	for _, name := range p.RootNames {
+		if !seen[name] {
+			seen[name] = true
+			all = append(all, p.diagnostics[name]...)
+		}
+	}
+	return all
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 )
 
@@ -16,8 +18,13 @@ type CompilerOptions struct {
 	AllowJs                            *bool               `json:"allowJs,omitempty"`
 	CheckJs                            *bool               `json:"checkJs,omitempty"`
 	JSX                                string              `json:"jsx,omitempty"`
+	JSXFactory                         string              `json:"jsxFactory,omitempty"`
+	JSXFragmentFactory                 string              `json:"jsxFragmentFactory,omitempty"`
+	JSXImportSource                    string              `json:"jsxImportSource,omitempty"`
 	Declaration                        *bool               `json:"declaration,omitempty"`
 	DeclarationMap                     *bool               `json:"declarationMap,omitempty"`
+	DeclarationDir                     string              `json:"declarationDir,omitempty"`
+	Composite                          *bool               `json:"composite,omitempty"`
 	SourceMap                          *bool               `json:"sourceMap,omitempty"`
 	OutFile                            string              `json:"outFile,omitempty"`
 	OutDir                             string              `json:"outDir,omitempty"`
@@ -56,8 +63,33 @@ type CompilerOptions struct {
 	EmitDecoratorMetadata              *bool               `json:"emitDecoratorMetadata,omitempty"`
 	Incremental                        *bool               `json:"incremental,omitempty"`
 	TsBuildInfoFile                    string              `json:"tsBuildInfoFile,omitempty"`
+
+	// PathsBasePath is the directory that the substitutions in Paths are
+	// relative to when BaseUrl is not set: that of the configuration file
+	// that declares them. ResolveTSConfig sets it.
+	PathsBasePath string `json:"-"`
+
+	// Unknown holds the options that have no field above, such as
+	// verbatimModuleSyntax or moduleDetection, as raw JSON by name, so that
+	// they are not lost.
+	Unknown map[string]json.RawMessage `json:"-"`
 }
 
+// knownCompilerOptions holds the lowercased JSON names of the fields of
+// CompilerOptions. encoding/json matches names case-insensitively, so
+// options are compared lowercased.
+var knownCompilerOptions = func() map[string]bool {
+	known := make(map[string]bool)
+	t := reflect.TypeOf(CompilerOptions{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			known[strings.ToLower(name)] = true
+		}
+	}
+	return known
+}()
+
 // ProjectReference represents a TypeScript project reference.
 type ProjectReference struct {
 	Path      string `json:"path"`
@@ -67,8 +99,10 @@ type ProjectReference struct {
 
 // TSConfig represents a parsed TypeScript configuration file.
 type TSConfig struct {
-	// Extends specifies the base configuration file to inherit from.
-	Extends string `json:"extends,omitempty"`
+	// Extends lists the base configuration files to inherit from, in the
+	// order they are applied. It is decoded by ParseTSConfig, as the field
+	// may be a single string or, since TypeScript 5.0, an array of strings.
+	Extends []string `json:"-"`
 
 	// CompilerOptions contains TypeScript compiler options.
 	CompilerOptions CompilerOptions `json:"compilerOptions,omitempty"`
@@ -93,9 +127,15 @@ type TSConfig struct {
 
 	// path is the absolute path to this tsconfig file (internal use).
 	path string
+
+	// files lists the absolute paths of this tsconfig file and of the
+	// configuration files it extends, directly or indirectly (internal use).
+	files []string
 }
 
-// ParseTSConfig parses a tsconfig.json file from the given path.
+// ParseTSConfig parses a tsconfig.json file from the given path. Like tsc, it
+// accepts comments and trailing commas. Syntax errors and values of the wrong
+// type are reported as a *ConfigError giving the file, line and column.
 func ParseTSConfig(path string) (*TSConfig, error) {
 	// Normalize path to absolute
 	absPath, err := filepath.Abs(path)
@@ -109,10 +149,27 @@ func ParseTSConfig(path string) (*TSConfig, error) {
 		return nil, fmt.Errorf("failed to read tsconfig file %s: %w", absPath, err)
 	}
 
-	// Parse JSON (supports comments through lenient parsing)
 	var config TSConfig
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse tsconfig file %s: %w", absPath, err)
+	var raw struct {
+		Extends         json.RawMessage            `json:"extends"`
+		CompilerOptions map[string]json.RawMessage `json:"compilerOptions"`
+	}
+	if err := unmarshalJSONC(absPath, data, &config, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse tsconfig file: %w", err)
+	}
+
+	config.Extends, err = parseExtends(absPath, data, raw.Extends)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse tsconfig file: %w", err)
+	}
+
+	for name, value := range raw.CompilerOptions {
+		if !knownCompilerOptions[strings.ToLower(name)] {
+			if config.CompilerOptions.Unknown == nil {
+				config.CompilerOptions.Unknown = make(map[string]json.RawMessage)
+			}
+			config.CompilerOptions.Unknown[name] = value
+		}
 	}
 
 	config.path = absPath
@@ -120,62 +177,183 @@ func ParseTSConfig(path string) (*TSConfig, error) {
 	return &config, nil
 }
 
-// ResolveTSConfig resolves a tsconfig.json file with inheritance.
-// It handles the "extends" field and merges configurations.
+// ResolveTSConfig resolves a tsconfig.json file with inheritance, as tsc
+// does. The configurations named by its "extends" field are resolved in
+// order, each overriding the ones before it, and the file itself overrides
+// them all. Of the top-level fields, all but "references" are inherited.
+//
+// Path-valued compiler options, such as outDir and baseUrl, are made absolute
+// relative to the file that sets them. Inherited files, include and exclude
+// patterns are rebased onto the directory of the resolved file, and a
+// leading "${configDir}" in any of these is replaced with that directory.
+// A configuration that extends itself, directly or not, is an error.
 func ResolveTSConfig(path string) (*TSConfig, error) {
-	config, err := ParseTSConfig(path)
+	config, err := resolveTSConfig(path, nil)
 	if err != nil {
 		return nil, err
 	}
+	config.expandConfigDir()
+	return config, nil
+}
 
-	// If no extends, return as-is
-	if config.Extends == "" {
-		return config, nil
+// resolveTSConfig resolves the configuration at path, which the
+// configurations in chain extend, each the one before it.
+func resolveTSConfig(path string, chain []string) (*TSConfig, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve absolute path for %s: %w", path, err)
+	}
+	if i := slices.Index(chain, absPath); i >= 0 {
+		return nil, fmt.Errorf("circularity detected while resolving configuration: %s -> %s",
+			strings.Join(chain[i:], " -> "), absPath)
 	}
 
-	// Resolve the base configuration
-	baseConfigPath := resolveExtendsPath(config.path, config.Extends)
-	baseConfig, err := ResolveTSConfig(baseConfigPath)
+	config, err := ParseTSConfig(absPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve extended config %s: %w", config.Extends, err)
+		return nil, err
+	}
+	config.CompilerOptions.makePathsAbsolute(config.GetConfigDir())
+
+	files := []string{config.path}
+	var base *TSConfig
+	for _, name := range config.Extends {
+		if name == "" {
+			continue
+		}
+
+		basePath, err := resolveExtendsPath(config.path, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve extended config %s in %s: %w", name, config.path, err)
+		}
+		extended, err := resolveTSConfig(basePath, append(chain, config.path))
+		if err != nil {
+			return nil, err
+		}
+		extended.rebase(config.GetConfigDir())
+		files = append(files, extended.files...)
+
+		if base == nil {
+			base = extended
+		} else {
+			base = mergeConfigs(base, extended)
+		}
 	}
 
-	// Merge configurations (child overrides parent)
-	merged := mergeConfigs(baseConfig, config)
-	return merged, nil
+	if base != nil {
+		// Merge configurations (child overrides parent)
+		config = mergeConfigs(base, config)
+	}
+	config.files = files
+	return config, nil
 }
 
-// resolveExtendsPath resolves the path to an extended configuration file.
-func resolveExtendsPath(currentConfigPath, extendsPath string) string {
-	// If it's a package name (not starting with . or /), it's from node_modules
-	if !strings.HasPrefix(extendsPath, ".") && !strings.HasPrefix(extendsPath, "/") {
-		// For simplicity, assume it's in node_modules relative to current config
-		dir := filepath.Dir(currentConfigPath)
-		return filepath.Join(dir, "node_modules", extendsPath, "tsconfig.json")
+// makePathsAbsolute makes the relative path-valued options absolute, relative
+// to dir, the directory of the configuration file that sets them.
+func (o *CompilerOptions) makePathsAbsolute(dir string) {
+	abs := func(path string) string {
+		if path == "" || filepath.IsAbs(path) || strings.HasPrefix(path, configDirTemplate) {
+			return path
+		}
+		return filepath.Join(dir, filepath.FromSlash(path))
 	}
 
-	// Relative path
-	dir := filepath.Dir(currentConfigPath)
-	resolved := filepath.Join(dir, extendsPath)
+	o.OutFile = abs(o.OutFile)
+	o.OutDir = abs(o.OutDir)
+	o.DeclarationDir = abs(o.DeclarationDir)
+	o.RootDir = abs(o.RootDir)
+	o.BaseUrl = abs(o.BaseUrl)
+	o.TsBuildInfoFile = abs(o.TsBuildInfoFile)
+	for i := range o.RootDirs {
+		o.RootDirs[i] = abs(o.RootDirs[i])
+	}
+	for i := range o.TypeRoots {
+		o.TypeRoots[i] = abs(o.TypeRoots[i])
+	}
+	if len(o.Paths) > 0 {
+		o.PathsBasePath = dir
+	}
+}
+
+// rebase rewrites the relative files, include and exclude patterns of c,
+// which are relative to its own directory, to be relative to dir, that of
+// the configuration extending it.
+func (c *TSConfig) rebase(dir string) {
+	rel, err := filepath.Rel(dir, c.GetConfigDir())
+	if err != nil || rel == "." {
+		return
+	}
 
-	// If no extension, try .json
-	if filepath.Ext(resolved) == "" {
-		if _, err := os.Stat(resolved + ".json"); err == nil {
-			return resolved + ".json"
+	rebase := func(patterns []string) []string {
+		if len(patterns) == 0 {
+			return patterns
 		}
-		// Try as directory with tsconfig.json
-		if stat, err := os.Stat(resolved); err == nil && stat.IsDir() {
-			return filepath.Join(resolved, "tsconfig.json")
+		rebased := make([]string, len(patterns))
+		for i, pattern := range patterns {
+			if filepath.IsAbs(pattern) || strings.HasPrefix(pattern, configDirTemplate) {
+				rebased[i] = pattern
+			} else {
+				rebased[i] = filepath.ToSlash(filepath.Join(rel, filepath.FromSlash(pattern)))
+			}
 		}
+		return rebased
 	}
 
-	return resolved
+	c.Files = rebase(c.Files)
+	c.Include = rebase(c.Include)
+	c.Exclude = rebase(c.Exclude)
+}
+
+// expandConfigDir replaces a leading "${configDir}" in paths and patterns
+// with the directory of c.
+func (c *TSConfig) expandConfigDir() {
+	dir := c.GetConfigDir()
+	expand := func(path string) string {
+		rest, ok := strings.CutPrefix(path, configDirTemplate)
+		if !ok {
+			return path
+		}
+		return filepath.Join(dir, filepath.FromSlash(rest))
+	}
+	expandAll := func(paths []string) []string {
+		if !slices.ContainsFunc(paths, func(path string) bool {
+			return strings.HasPrefix(path, configDirTemplate)
+		}) {
+			return paths
+		}
+		expanded := make([]string, len(paths))
+		for i, path := range paths {
+			expanded[i] = expand(path)
+		}
+		return expanded
+	}
+
+	c.Files = expandAll(c.Files)
+	c.Include = expandAll(c.Include)
+	c.Exclude = expandAll(c.Exclude)
+
+	o := &c.CompilerOptions
+	o.OutFile = expand(o.OutFile)
+	o.OutDir = expand(o.OutDir)
+	o.DeclarationDir = expand(o.DeclarationDir)
+	o.RootDir = expand(o.RootDir)
+	o.BaseUrl = expand(o.BaseUrl)
+	o.TsBuildInfoFile = expand(o.TsBuildInfoFile)
+	o.RootDirs = expandAll(o.RootDirs)
+	o.TypeRoots = expandAll(o.TypeRoots)
+	if len(o.Paths) > 0 {
+		paths := make(map[string][]string, len(o.Paths))
+		for pattern, substitutions := range o.Paths {
+			paths[pattern] = expandAll(substitutions)
+		}
+		o.Paths = paths
+	}
 }
 
 // mergeConfigs merges two TSConfig objects, with child taking precedence.
 func mergeConfigs(parent, child *TSConfig) *TSConfig {
 	merged := &TSConfig{
-		path: child.path,
+		Extends: child.Extends,
+		path:    child.path,
 	}
 
 	// Merge compiler options
@@ -202,8 +380,8 @@ func mergeConfigs(parent, child *TSConfig) *TSConfig {
 		merged.Exclude = parent.Exclude
 	}
 
-	// References: combine
-	merged.References = append(parent.References, child.References...)
+	// References are not inherited
+	merged.References = child.References
 
 	// CompileOnSave: child overrides if present
 	if child.CompileOnSave != nil {
@@ -212,6 +390,13 @@ func mergeConfigs(parent, child *TSConfig) *TSConfig {
 		merged.CompileOnSave = parent.CompileOnSave
 	}
 
+	// TypeAcquisition: child overrides if present
+	if child.TypeAcquisition != nil {
+		merged.TypeAcquisition = child.TypeAcquisition
+	} else {
+		merged.TypeAcquisition = parent.TypeAcquisition
+	}
+
 	return merged
 }
 
@@ -240,12 +425,27 @@ func mergeCompilerOptions(parent, child *CompilerOptions) CompilerOptions {
 	if child.JSX != "" {
 		merged.JSX = child.JSX
 	}
+	if child.JSXFactory != "" {
+		merged.JSXFactory = child.JSXFactory
+	}
+	if child.JSXFragmentFactory != "" {
+		merged.JSXFragmentFactory = child.JSXFragmentFactory
+	}
+	if child.JSXImportSource != "" {
+		merged.JSXImportSource = child.JSXImportSource
+	}
 	if child.Declaration != nil {
 		merged.Declaration = child.Declaration
 	}
 	if child.DeclarationMap != nil {
 		merged.DeclarationMap = child.DeclarationMap
 	}
+	if child.DeclarationDir != "" {
+		merged.DeclarationDir = child.DeclarationDir
+	}
+	if child.Composite != nil {
+		merged.Composite = child.Composite
+	}
 	if child.SourceMap != nil {
 		merged.SourceMap = child.SourceMap
 	}
@@ -300,6 +500,15 @@ func mergeCompilerOptions(parent, child *CompilerOptions) CompilerOptions {
 	if child.NoFallthroughCasesInSwitch != nil {
 		merged.NoFallthroughCasesInSwitch = child.NoFallthroughCasesInSwitch
 	}
+	if child.NoUncheckedIndexedAccess != nil {
+		merged.NoUncheckedIndexedAccess = child.NoUncheckedIndexedAccess
+	}
+	if child.NoImplicitOverride != nil {
+		merged.NoImplicitOverride = child.NoImplicitOverride
+	}
+	if child.NoPropertyAccessFromIndexSignature != nil {
+		merged.NoPropertyAccessFromIndexSignature = child.NoPropertyAccessFromIndexSignature
+	}
 	if child.ModuleResolution != "" {
 		merged.ModuleResolution = child.ModuleResolution
 	}
@@ -308,6 +517,7 @@ func mergeCompilerOptions(parent, child *CompilerOptions) CompilerOptions {
 	}
 	if len(child.Paths) > 0 {
 		merged.Paths = child.Paths
+		merged.PathsBasePath = child.PathsBasePath
 	}
 	if len(child.RootDirs) > 0 {
 		merged.RootDirs = child.RootDirs
@@ -324,6 +534,12 @@ func mergeCompilerOptions(parent, child *CompilerOptions) CompilerOptions {
 	if child.EsModuleInterop != nil {
 		merged.EsModuleInterop = child.EsModuleInterop
 	}
+	if child.PreserveSymlinks != nil {
+		merged.PreserveSymlinks = child.PreserveSymlinks
+	}
+	if child.ForceConsistentCasingInFileNames != nil {
+		merged.ForceConsistentCasingInFileNames = child.ForceConsistentCasingInFileNames
+	}
 	if child.SkipLibCheck != nil {
 		merged.SkipLibCheck = child.SkipLibCheck
 	}
@@ -339,6 +555,21 @@ func mergeCompilerOptions(parent, child *CompilerOptions) CompilerOptions {
 	if child.EmitDecoratorMetadata != nil {
 		merged.EmitDecoratorMetadata = child.EmitDecoratorMetadata
 	}
+	if child.Incremental != nil {
+		merged.Incremental = child.Incremental
+	}
+	if child.TsBuildInfoFile != "" {
+		merged.TsBuildInfoFile = child.TsBuildInfoFile
+	}
+	if len(child.Unknown) > 0 {
+		merged.Unknown = make(map[string]json.RawMessage, len(parent.Unknown)+len(child.Unknown))
+		for name, value := range parent.Unknown {
+			merged.Unknown[name] = value
+		}
+		for name, value := range child.Unknown {
+			merged.Unknown[name] = value
+		}
+	}
 
 	return merged
 }
@@ -352,3 +583,13 @@ func (c *TSConfig) GetConfigDir() string {
 func (c *TSConfig) GetPath() string {
 	return c.path
 }
+
+// GetConfigFiles returns the absolute paths of this tsconfig file and, if it
+// was resolved by ResolveTSConfig, of the configuration files it extends,
+// directly or indirectly, in the order they were read.
+func (c *TSConfig) GetConfigFiles() []string {
+	if c.files == nil && c.path != "" {
+		return []string{c.path}
+	}
+	return c.files
+}
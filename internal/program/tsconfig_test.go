@@ -1,8 +1,10 @@
 package program
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -147,3 +149,165 @@ func TestGetPath(t *testing.T) {
 		t.Errorf("Expected path %s, got %s", tsconfigPath, path)
 	}
 }
+
+func TestParseTSConfig_JSONC(t *testing.T) {
+	tmpDir := t.TempDir()
+	tsconfigPath := filepath.Join(tmpDir, "tsconfig.json")
+	tsconfigContent := "\xef\xbb\xbf" + `{
+		// Line comment
+		"compilerOptions": {
+			/* Block
+			   comment */
+			"target": "ES2022", // trailing comment
+			"strict": true,
+			"lib": ["ES2022", "DOM",],
+			"verbatimModuleSyntax": true,
+			"moduleDetection": "force",
+			"paths": { "@/*": ["./src/*"], },
+		},
+		"include": ["src/**/*.ts", "url://not-a-comment"],
+	}`
+
+	if err := os.WriteFile(tsconfigPath, []byte(tsconfigContent), 0600); err != nil {
+		t.Fatalf("Failed to write test tsconfig: %v", err)
+	}
+
+	config, err := ParseTSConfig(tsconfigPath)
+	if err != nil {
+		t.Fatalf("Failed to parse tsconfig: %v", err)
+	}
+
+	if config.CompilerOptions.Target != "ES2022" {
+		t.Errorf("Expected target ES2022, got %s", config.CompilerOptions.Target)
+	}
+	if len(config.CompilerOptions.Lib) != 2 {
+		t.Errorf("Expected 2 libs, got %v", config.CompilerOptions.Lib)
+	}
+	if len(config.Include) != 2 || config.Include[1] != "url://not-a-comment" {
+		t.Errorf("Expected include to keep // inside strings, got %v", config.Include)
+	}
+
+	unknown := config.CompilerOptions.Unknown
+	if len(unknown) != 2 {
+		t.Fatalf("Expected 2 unknown options, got %v", unknown)
+	}
+	if string(unknown["verbatimModuleSyntax"]) != "true" {
+		t.Errorf("Expected verbatimModuleSyntax true, got %s", unknown["verbatimModuleSyntax"])
+	}
+	if string(unknown["moduleDetection"]) != `"force"` {
+		t.Errorf(`Expected moduleDetection "force", got %s`, unknown["moduleDetection"])
+	}
+}
+
+func TestParseTSConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		line    int
+		column  int
+		message string
+	}{
+		{
+			name:    "missing comma",
+			content: "{\n  \"target\": \"ES5\"\n  \"module\": \"commonjs\"\n}",
+			line:    3,
+			column:  3,
+			message: `expected ',' or '}', found '"'`,
+		},
+		{
+			name:    "unterminated comment",
+			content: "{\n  /* never closed\n}",
+			line:    2,
+			column:  3,
+			message: "unterminated comment",
+		},
+		{
+			name:    "unterminated string",
+			content: "{ \"include\": [\"src\n] }",
+			line:    1,
+			column:  15,
+			message: "unterminated string literal",
+		},
+		{
+			name:    "missing value",
+			content: "{\n  \"files\": ,\n}",
+			line:    2,
+			column:  12,
+			message: "expected a value, found ','",
+		},
+		{
+			name:    "wrong type",
+			content: "{\n  // comment\n  \"compilerOptions\": { \"strict\": \"yes\" }\n}",
+			line:    3,
+			column:  34,
+			message: `compilerOptions.strict: expected boolean, found string`,
+		},
+		{
+			name:    "wrong type in list",
+			content: "{ \"include\": [\"a\", 1] }",
+			line:    1,
+			column:  20,
+			message: "include.1: expected string, found number",
+		},
+		{
+			name:    "trailing content",
+			content: "{} {}",
+			line:    1,
+			column:  4,
+			message: "unexpected '{' after the end of the document",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tsconfigPath := filepath.Join(t.TempDir(), "tsconfig.json")
+			if err := os.WriteFile(tsconfigPath, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("Failed to write test tsconfig: %v", err)
+			}
+
+			_, err := ParseTSConfig(tsconfigPath)
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) {
+				t.Fatalf("Expected ConfigError, got %v", err)
+			}
+
+			if configErr.Path != tsconfigPath {
+				t.Errorf("Expected path %s, got %s", tsconfigPath, configErr.Path)
+			}
+			if configErr.Line != tt.line || configErr.Column != tt.column {
+				t.Errorf("Expected position %d:%d, got %d:%d", tt.line, tt.column, configErr.Line, configErr.Column)
+			}
+			// Older versions of encoding/json report shorter field paths.
+			_, want, found := strings.Cut(tt.message, ": ")
+			if !found {
+				want = tt.message
+			}
+			if !strings.HasSuffix(configErr.Message, want) {
+				t.Errorf("Expected message %q, got %q", tt.message, configErr.Message)
+			}
+		})
+	}
+}
+
+func TestResolveTSConfig_MergesUnknownOptions(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	base := `{ "compilerOptions": { "moduleDetection": "force", "verbatimModuleSyntax": false } }`
+	child := `{ "extends": "./base.json", "compilerOptions": { "verbatimModuleSyntax": true } }`
+	if err := os.WriteFile(filepath.Join(tmpDir, "base.json"), []byte(base), 0600); err != nil {
+		t.Fatalf("Failed to write base config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "tsconfig.json"), []byte(child), 0600); err != nil {
+		t.Fatalf("Failed to write child config: %v", err)
+	}
+
+	config, err := ResolveTSConfig(filepath.Join(tmpDir, "tsconfig.json"))
+	if err != nil {
+		t.Fatalf("Failed to resolve tsconfig: %v", err)
+	}
+
+	unknown := config.CompilerOptions.Unknown
+	if string(unknown["moduleDetection"]) != `"force"` || string(unknown["verbatimModuleSyntax"]) != "true" {
+		t.Errorf("Expected inherited and overridden unknown options, got %v", unknown)
+	}
+}
@@ -0,0 +1,211 @@
+package program
+
+import (
+	"crypto/sha256"
+	"os"
+	"sync"
+	"time"
+)
+
+// Watcher reports changes to files and directories, as the watchers of
+// file system notification libraries such as fsnotify do. A ProgramCache
+// with a watcher removes the programs that depend on a changed path as soon
+// as the watcher reports it, instead of checking the paths on every Get.
+type Watcher interface {
+	// Add starts watching the file or directory at path. Changes to the
+	// entries of a directory are reported, by the path of the directory or
+	// of the entry.
+	Add(path string) error
+
+	// Remove stops watching path.
+	Remove(path string) error
+
+	// Events returns the channel on which the paths of changed files and
+	// directories are sent. It is closed when the watcher is closed.
+	Events() <-chan string
+}
+
+// racyWindow is how close to the time a stamp was taken a directory may have
+// been modified for its modification time not to tell whether it has changed
+// since, as file systems record modification times at a coarse granularity.
+const racyWindow = 2 * time.Second
+
+// fileStamp identifies the state of a file or directory, to tell whether it
+// has changed: files by a hash of their contents, and directories by their
+// modification time, which changes when entries are added, removed or
+// renamed, and a hash of the names of their entries. The size and
+// modification time of a file spare reading it when they tell it is
+// unchanged.
+type fileStamp struct {
+	exists  bool
+	isDir   bool
+	modTime int64
+	size    int64
+	hash    [sha256.Size]byte
+}
+
+// stampPath returns the stamp of the file or directory at path.
+func stampPath(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	if info.IsDir() {
+		return fileStamp{exists: true, isDir: true, modTime: info.ModTime().UnixNano(), hash: hashEntries(path)}
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- path is a file a program depends on
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{exists: true, modTime: info.ModTime().UnixNano(), size: info.Size(), hash: sha256.Sum256(data)}
+}
+
+// equal reports whether s and t are stamps of the same state. Files are
+// compared by their contents alone, so that a file rewritten with the same
+// contents is unchanged.
+func (s fileStamp) equal(t fileStamp) bool {
+	if s.isDir || t.isDir {
+		return s == t
+	}
+	return s.exists == t.exists && s.hash == t.hash
+}
+
+// hashEntries returns a hash of the names of the entries of the directory
+// at path.
+func hashEntries(path string) [sha256.Size]byte {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return [sha256.Size]byte{}
+	}
+	h := sha256.New()
+	for _, entry := range entries {
+		h.Write([]byte(entry.Name()))
+		h.Write([]byte{0})
+	}
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// changed reports whether the file or directory at path differs from s,
+// which was taken at stampedAt. The entries of a directory, and the contents
+// of a file of the same size, are only compared if it was modified too close
+// to stampedAt for its modification time to tell; otherwise a stat suffices.
+func (s fileStamp) changed(path string, stampedAt time.Time) bool {
+	if !s.isDir {
+		info, err := os.Stat(path)
+		if err == nil && s.exists && !info.IsDir() && info.Size() == s.size &&
+			info.ModTime().UnixNano() == s.modTime && info.ModTime().Before(stampedAt.Add(-racyWindow)) {
+			return false
+		}
+		return !stampPath(path).equal(s)
+	}
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() || info.ModTime().UnixNano() != s.modTime {
+		return true
+	}
+	if info.ModTime().Before(stampedAt.Add(-racyWindow)) {
+		return false
+	}
+	return hashEntries(path) != s.hash
+}
+
+// PollingWatcher is a Watcher that checks the paths it watches for changes
+// at a fixed interval. It needs no support from the operating system, which
+// makes it a fallback where native notifications are unavailable, and a
+// predictable watcher in tests.
+type PollingWatcher struct {
+	interval time.Duration
+	events   chan string
+	done     chan struct{}
+	stop     sync.Once
+
+	// stamps holds the last seen state of each watched path
+	stamps map[string]fileStamp
+
+	// mu protects stamps
+	mu sync.Mutex
+}
+
+// NewPollingWatcher returns a watcher that checks its paths every interval,
+// until it is closed.
+func NewPollingWatcher(interval time.Duration) *PollingWatcher {
+	w := &PollingWatcher{
+		interval: interval,
+		events:   make(chan string, 64),
+		done:     make(chan struct{}),
+		stamps:   make(map[string]fileStamp),
+	}
+	go w.run()
+	return w
+}
+
+// Add starts watching the file or directory at path, which need not exist.
+func (w *PollingWatcher) Add(path string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.stamps[path]; !ok {
+		w.stamps[path] = stampPath(path)
+	}
+	return nil
+}
+
+// Remove stops watching path.
+func (w *PollingWatcher) Remove(path string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.stamps, path)
+	return nil
+}
+
+// Events returns the channel on which the paths of changed files and
+// directories are sent.
+func (w *PollingWatcher) Events() <-chan string {
+	return w.events
+}
+
+// Close stops the watcher and closes its Events channel.
+func (w *PollingWatcher) Close() error {
+	w.stop.Do(func() { close(w.done) })
+	return nil
+}
+
+// run checks the watched paths every interval until the watcher is closed.
+func (w *PollingWatcher) run() {
+	defer close(w.events)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			for _, path := range w.poll() {
+				select {
+				case w.events <- path:
+				case <-w.done:
+					return
+				}
+			}
+		}
+	}
+}
+
+// poll returns the watched paths that have changed since they were last
+// checked, and records their new state.
+func (w *PollingWatcher) poll() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var changed []string
+	for path, stamp := range w.stamps {
+		if current := stampPath(path); !current.equal(stamp) {
+			w.stamps[path] = current
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
@@ -80,12 +80,35 @@
 //	    Right:    rightExpr,
 //	}
 //
+// # Traversal
+//
+// Walk and Traverse visit every node of a tree in depth-first order;
+// WalkWithContext also reports each node's parent and ancestors. A
+// SelectorVisitor dispatches handlers registered for esquery selectors, as
+// ESLint rules do, calling "CallExpression" handlers on entering a matching
+// node and "CallExpression:exit" handlers on leaving it, in a single pass.
+//
 // # References
 //
 //   - ESTree specification: https://github.com/estree/estree
 //   - TypeScript ESTree: https://typescript-eslint.io/packages/typescript-estree/ast-spec/
 //   - AST_NODE_TYPES: https://typescript-eslint.io/packages/typescript-estree/
 //
-// This package is internal and should not be imported by external code.
-// External code should use the public API in pkg/typescriptestree.
+// # Compatibility
+//
+// This package is the public form of the trees returned by
+// pkg/typescriptestree and is versioned with the module, following semantic
+// versioning. Within a major version:
+//
+//   - Node structs, their exported fields and the Node, Expression,
+//     Statement, Pattern, Declaration and TSNode interfaces are not removed,
+//     renamed or given a different type.
+//   - New node types, fields, visitor keys, guards and traversal helpers may
+//     be added in minor releases, mirroring additions to typescript-estree.
+//     Type switches and visitors should therefore tolerate node types they
+//     do not know about.
+//   - The JSON encoding of nodes stays compatible with typescript-estree.
+//
+// Fields that are excluded from JSON, such as BaseNode.Start and
+// BaseNode.EndPos, are implementation details and may change at any time.
 package ast
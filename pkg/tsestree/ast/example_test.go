@@ -0,0 +1,27 @@
+package ast_test
+
+import (
+	"fmt"
+
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
+	"github.com/kdy1/go-typescript-eslint/pkg/typescriptestree"
+)
+
+// Example shows how code outside this module inspects a parsed tree.
+func Example() {
+	result, err := typescriptestree.Parse(`console.log(greet("world"));`, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	ast.Walk(result.AST, ast.VisitorFunc(func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpression); ok {
+			if callee, ok := call.Callee.(*ast.Identifier); ok {
+				fmt.Println("call to", callee.Name)
+			}
+		}
+		return true
+	}))
+	// Output:
+	// call to greet
+}
@@ -0,0 +1,318 @@
+// Command walkgen generates the child iteration used by ast.Walk from the
+// node definitions and VisitorKeys of the ast package.
+//
+// It is run by go generate in the ast package directory:
+//
+//	go generate ./pkg/tsestree/ast
+//
+// and writes walk_gen.go, which visits the children of each node type without
+// reflection, and walk_gen_test.go, which lists the generated node types for
+// the test that checks the generated code against the reflective walker.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	goast "go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const header = "// Code generated by walkgen from visitor_keys.go and the node definitions; DO NOT EDIT.\n\n"
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "walkgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	fset := token.NewFileSet()
+	files, err := parsePackage(fset)
+	if err != nil {
+		return err
+	}
+
+	keys, err := visitorKeys(files)
+	if err != nil {
+		return err
+	}
+
+	// The package is type-checked without the generated files, which may be
+	// stale or missing, so errors about what they define are expected.
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {},
+	}
+	pkg, _ := conf.Check("ast", fset, files, nil)
+	nodeObj := pkg.Scope().Lookup("Node")
+	if nodeObj == nil {
+		return fmt.Errorf("node interface not found")
+	}
+	nodeIface, ok := nodeObj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return fmt.Errorf("node is not an interface")
+	}
+
+	g := &generator{pkg: pkg, node: nodeIface}
+
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var generated []string
+	for _, name := range names {
+		obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		if _, ok := obj.Type().Underlying().(*types.Struct); !ok {
+			continue
+		}
+		if !types.Implements(types.NewPointer(obj.Type()), nodeIface) {
+			continue
+		}
+		if err := g.nodeCase(name, obj.Type(), keys[name]); err != nil {
+			return err
+		}
+		generated = append(generated, name)
+	}
+
+	if err := writeSource("walk_gen.go", g.walkFile()); err != nil {
+		return err
+	}
+	return writeSource("walk_gen_test.go", testFile(generated))
+}
+
+// parsePackage parses the non-test, non-generated sources of the package in
+// the current directory.
+func parsePackage(fset *token.FileSet) ([]*goast.File, error) {
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*goast.File
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") ||
+			strings.HasSuffix(name, "_test.go") || strings.HasSuffix(name, "_gen.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// visitorKeys reads the VisitorKeys map literal from the package sources.
+func visitorKeys(files []*goast.File) (map[string][]string, error) {
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*goast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs, ok := spec.(*goast.ValueSpec)
+				if !ok || len(vs.Names) != 1 || vs.Names[0].Name != "VisitorKeys" || len(vs.Values) != 1 {
+					continue
+				}
+				lit, ok := vs.Values[0].(*goast.CompositeLit)
+				if !ok {
+					return nil, fmt.Errorf("VisitorKeys is not a composite literal")
+				}
+				return keysFromLiteral(lit)
+			}
+		}
+	}
+	return nil, fmt.Errorf("VisitorKeys not found")
+}
+
+func keysFromLiteral(lit *goast.CompositeLit) (map[string][]string, error) {
+	keys := make(map[string][]string, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*goast.KeyValueExpr)
+		if !ok {
+			return nil, fmt.Errorf("unexpected VisitorKeys element %T", elt)
+		}
+		name, err := stringLiteral(kv.Key)
+		if err != nil {
+			return nil, err
+		}
+		list, ok := kv.Value.(*goast.CompositeLit)
+		if !ok {
+			return nil, fmt.Errorf("visitor keys of %s are not a literal", name)
+		}
+		keys[name] = []string{}
+		for _, e := range list.Elts {
+			key, err := stringLiteral(e)
+			if err != nil {
+				return nil, err
+			}
+			keys[name] = append(keys[name], key)
+		}
+	}
+	return keys, nil
+}
+
+func stringLiteral(e goast.Expr) (string, error) {
+	lit, ok := e.(*goast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", fmt.Errorf("expected string literal, got %T", e)
+	}
+	return strconv.Unquote(lit.Value)
+}
+
+type generator struct {
+	pkg   *types.Package
+	node  *types.Interface
+	cases bytes.Buffer
+}
+
+// nodeCase writes the case of forEachChild for one node type.
+func (g *generator) nodeCase(name string, typ types.Type, keys []string) error {
+	fmt.Fprintf(&g.cases, "case *%s:\n", name)
+	fmt.Fprintf(&g.cases, "if n.NodeType != %q {\nreturn false\n}\n", name)
+
+	for _, key := range keys {
+		field := fieldByKey(typ, key)
+		if field == nil {
+			continue
+		}
+		g.childField(key, "n."+field.Name(), field.Type())
+	}
+	return nil
+}
+
+// childField writes the code visiting the children held by a field, matching
+// the reflective walker: nil values are skipped, slice elements are visited
+// in order, and struct values in slices are visited in place.
+func (g *generator) childField(key, expr string, typ types.Type) {
+	switch t := typ.Underlying().(type) {
+	case *types.Interface:
+		if types.Implements(typ, g.node) {
+			fmt.Fprintf(&g.cases, "if %s != nil {\nfn(%q, -1, %s)\n}\n", expr, key, expr)
+		} else {
+			fmt.Fprintf(&g.cases, "if c, ok := %s.(Node); ok {\nfn(%q, -1, c)\n}\n", expr, key)
+		}
+	case *types.Pointer:
+		if types.Implements(typ, g.node) {
+			fmt.Fprintf(&g.cases, "if %s != nil {\nfn(%q, -1, %s)\n}\n", expr, key, expr)
+		}
+	case *types.Slice:
+		elem := t.Elem()
+		switch elem.Underlying().(type) {
+		case *types.Interface:
+			if types.Implements(elem, g.node) {
+				fmt.Fprintf(&g.cases, "for i, c := range %s {\nif c != nil {\nfn(%q, i, c)\n}\n}\n", expr, key)
+			} else {
+				fmt.Fprintf(&g.cases, "for i, e := range %s {\nif c, ok := e.(Node); ok {\nfn(%q, i, c)\n}\n}\n", expr, key)
+			}
+		case *types.Pointer:
+			if types.Implements(elem, g.node) {
+				fmt.Fprintf(&g.cases, "for i, c := range %s {\nif c != nil {\nfn(%q, i, c)\n}\n}\n", expr, key)
+			}
+		case *types.Struct:
+			if types.Implements(types.NewPointer(elem), g.node) {
+				fmt.Fprintf(&g.cases, "for i := range %s {\nfn(%q, i, &%s[i])\n}\n", expr, key, expr)
+			}
+		}
+	}
+}
+
+// fieldByKey finds the field for a visitor key the way the reflective walker
+// does: the field named by the capitalized key, or else the field whose name
+// matches the key case-insensitively, searching embedded structs breadth
+// first. It returns nil if there is no such field or the match is ambiguous.
+func fieldByKey(typ types.Type, key string) *types.Var {
+	exact := strings.ToUpper(key[:1]) + key[1:]
+	if f := findField(typ, func(name string) bool { return name == exact }); f != nil {
+		return f
+	}
+	return findField(typ, func(name string) bool { return strings.EqualFold(name, key) })
+}
+
+func findField(typ types.Type, match func(string) bool) *types.Var {
+	level := []types.Type{typ}
+	for len(level) > 0 {
+		var found *types.Var
+		count := 0
+		var next []types.Type
+		for _, t := range level {
+			st, ok := t.Underlying().(*types.Struct)
+			if !ok {
+				continue
+			}
+			for i := 0; i < st.NumFields(); i++ {
+				f := st.Field(i)
+				if match(f.Name()) {
+					found = f
+					count++
+				}
+				if f.Embedded() {
+					next = append(next, f.Type())
+				}
+			}
+		}
+		if count == 1 {
+			return found
+		}
+		if count > 1 {
+			return nil
+		}
+		level = next
+	}
+	return nil
+}
+
+func (g *generator) walkFile() []byte {
+	var b bytes.Buffer
+	b.WriteString(header)
+	b.WriteString("package ast\n\n")
+	b.WriteString(`// forEachChild calls fn for each child of node, in the order of its
+// visitor keys, with the key of the property holding the child and its index
+// if the property is a list, or -1. It reports false, without calling fn, if
+// node is not a type it was generated for or its NodeType is not the name of
+// its Go type; the caller then falls back to reflection.
+func forEachChild(node Node, fn func(key string, index int, child Node)) bool {
+switch n := node.(type) {
+`)
+	b.Write(g.cases.Bytes())
+	b.WriteString("default:\nreturn false\n}\nreturn true\n}\n")
+	return b.Bytes()
+}
+
+func testFile(names []string) []byte {
+	var b bytes.Buffer
+	b.WriteString(header)
+	b.WriteString("package ast\n\n")
+	b.WriteString("// generatedNodes holds a node of each type forEachChild was generated for.\n")
+	b.WriteString("var generatedNodes = []Node{\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "&%s{},\n", name)
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+func writeSource(name string, src []byte) error {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("formatting %s: %w", name, err)
+	}
+	return os.WriteFile(name, formatted, 0o600)
+}
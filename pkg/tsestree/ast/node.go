@@ -1,5 +1,7 @@
 package ast
 
+import "slices"
+
 // Node is the base interface for all AST nodes.
 // All AST node types must implement this interface.
 type Node interface {
@@ -21,6 +23,10 @@ type BaseNode struct {
 	Range    *Range          `json:"range,omitempty"`
 	Start    int             `json:"-"` // Internal use, not serialized
 	EndPos   int             `json:"-"` // Internal use, not serialized
+
+	// The node's parent, set by SetParents. It is unexported so that it is
+	// not serialized, which would make the JSON cyclic.
+	parent Node
 }
 
 // Type returns the type of the node.
@@ -38,6 +44,37 @@ func (n *BaseNode) End() int {
 	return n.EndPos
 }
 
+// Base returns the embedded BaseNode, so position information can be
+// updated without knowing the concrete node type.
+func (n *BaseNode) Base() *BaseNode {
+	return n
+}
+
+// Parent returns the node's parent, or nil for the root and for nodes whose
+// tree has not been linked with SetParents.
+//
+//nolint:ireturn // Interface types are intentional for generic AST node retrieval
+func (n *BaseNode) Parent() Node {
+	return n.parent
+}
+
+// SetParent sets the node's parent. Code that moves a node to a different
+// place in a tree uses it to keep the link up to date.
+func (n *BaseNode) SetParent(parent Node) {
+	n.parent = parent
+}
+
+// Ancestors returns the node's ancestors from the root down to its parent,
+// following parent links. It returns nil if the node has no parent.
+func (n *BaseNode) Ancestors() []Node {
+	var ancestors []Node
+	for p := n.parent; p != nil; p = Parent(p) {
+		ancestors = append(ancestors, p)
+	}
+	slices.Reverse(ancestors)
+	return ancestors
+}
+
 // SourceLocation represents the location of a node in source code.
 // It contains the start and end positions with line and column information.
 type SourceLocation struct {
@@ -0,0 +1,1029 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Selector is a compiled esquery selector, the syntax ESLint rules use to
+// choose the nodes they handle. The supported syntax is:
+//
+//   - any node
+//     CallExpression             a node type (case-sensitive)
+//     [attr]                     an attribute that is present and not null
+//     [attr="x"] [attr!=1]       an attribute compared as a string
+//     [attr=/re/i]               an attribute matching a regular expression
+//     [attr<=2] [attr>1]         an attribute compared as a number or string
+//     [attr=type(string)]        an attribute of a JavaScript type
+//     [callee.name="f"]          a path of attributes, by their JSON names
+//     .property                  a node held by a property of its parent
+//     A B  A > B  A ~ B  A + B   descendant, child, sibling and adjacent sibling
+//     A, B                       either selector
+//     :matches(A, B) :is(A, B)   either selector
+//     :not(A, B)                 neither selector
+//     :has(A) :has(> A)          a node with a descendant or child matching A
+//     :nth-child(2) :nth-last-child(1) :first-child :last-child
+//     :statement :expression :declaration :pattern :function
+//
+// Atoms written together, such as Identifier.property[name="x"], must all
+// match. Attribute names are the JSON names of fields, and "length" gives the
+// length of a list.
+type Selector struct {
+	raw  string
+	root selectorNode
+
+	// attributes and identifiers give the specificity of the selector, which
+	// orders the handlers SelectorVisitor calls for the same node.
+	attributes  int
+	identifiers int
+
+	// types are the node types the selector can match, or nil for any.
+	types []string
+}
+
+// ParseSelector compiles an esquery selector.
+func ParseSelector(s string) (*Selector, error) {
+	p := &selectorParser{src: s}
+	p.skipSpace()
+	root, err := p.parseSelectors()
+	if err == nil {
+		p.skipSpace()
+		if p.pos < len(p.src) {
+			err = p.errorf("unexpected %q", p.src[p.pos])
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, identifiers := specificity(root)
+	return &Selector{
+		raw:         s,
+		root:        root,
+		attributes:  attributes,
+		identifiers: identifiers,
+		types:       nodeTypes(root),
+	}, nil
+}
+
+// MustParseSelector is like ParseSelector but panics if the selector is invalid.
+func MustParseSelector(s string) *Selector {
+	sel, err := ParseSelector(s)
+	if err != nil {
+		panic(err)
+	}
+	return sel
+}
+
+// String returns the source of the selector.
+func (s *Selector) String() string {
+	return s.raw
+}
+
+// Matches reports whether node matches the selector, given its ancestors
+// from the root down to its parent.
+func (s *Selector) Matches(node Node, ancestors []Node) bool {
+	path := &matchPath{entries: make([]pathEntry, 0, len(ancestors)+1)}
+	nodes := append(ancestors[:len(ancestors):len(ancestors)], node)
+	for i, n := range nodes {
+		entry := pathEntry{node: n, index: -1}
+		if i > 0 {
+			eachChild(nodes[i-1], func(key string, index int, child Node) {
+				if child == n {
+					entry.key, entry.index = key, index
+				}
+			})
+		}
+		path.entries = append(path.entries, entry)
+	}
+	return s.root.match(path, len(path.entries)-1)
+}
+
+// pathEntry is a node on the path from the root, with the key and list index
+// at which its parent holds it.
+type pathEntry struct {
+	node  Node
+	key   string
+	index int
+}
+
+// matchPath is the path from the root to the node being matched.
+type matchPath struct {
+	entries []pathEntry
+}
+
+// siblings calls fn for each node in the same list of the parent as the node
+// at i, including that node.
+func (p *matchPath) siblings(i int, fn func(index int, node Node)) {
+	if i == 0 || p.entries[i].index < 0 {
+		return
+	}
+	key := p.entries[i].key
+	eachChild(p.entries[i-1].node, func(k string, index int, child Node) {
+		if k == key && index >= 0 {
+			fn(index, child)
+		}
+	})
+}
+
+// withSibling returns the path to the node at i with that node replaced by
+// a sibling.
+func (p *matchPath) withSibling(i, index int, node Node) *matchPath {
+	entries := append(p.entries[:i:i], pathEntry{node: node, key: p.entries[i].key, index: index})
+	return &matchPath{entries: entries}
+}
+
+// selectorNode is a part of a compiled selector. match reports whether the
+// node at index i of path matches it.
+type selectorNode interface {
+	match(path *matchPath, i int) bool
+}
+
+type (
+	wildcardSelector struct{}
+	typeSelector     struct{ name string }
+	fieldSelector    struct{ path []string }
+	classSelector    struct{ name string }
+	scopeSelector    struct{}
+
+	compoundSelector struct{ parts []selectorNode }
+	matchesSelector  struct{ alternatives []selectorNode }
+	notSelector      struct{ alternatives []selectorNode }
+	hasSelector      struct{ alternatives []selectorNode }
+
+	nthChildSelector struct {
+		n       int
+		fromEnd bool
+	}
+
+	// combinatorSelector joins two selectors with one of ' ', '>', '~' and '+'.
+	combinatorSelector struct {
+		op          byte
+		left, right selectorNode
+	}
+
+	attributeSelector struct {
+		path  []string
+		op    string // "" if the attribute only has to be present
+		value attributeValue
+		regex *regexp.Regexp // set if the value is a regular expression
+		kind  string         // set if the value is type(kind)
+	}
+)
+
+func (wildcardSelector) match(*matchPath, int) bool { return true }
+
+func (s typeSelector) match(p *matchPath, i int) bool {
+	return p.entries[i].node.Type() == s.name
+}
+
+func (s fieldSelector) match(p *matchPath, i int) bool {
+	if i < len(s.path) {
+		return false
+	}
+	for j := range s.path {
+		if p.entries[i-j].key != s.path[len(s.path)-1-j] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s classSelector) match(p *matchPath, i int) bool {
+	typ := p.entries[i].node.Type()
+	switch s.name {
+	case "statement":
+		return strings.HasSuffix(typ, "Statement") || strings.HasSuffix(typ, "Declaration")
+	case "declaration":
+		return strings.HasSuffix(typ, "Declaration")
+	case "pattern", "expression":
+		if s.name == "pattern" && strings.HasSuffix(typ, "Pattern") {
+			return true
+		}
+		if typ == "Identifier" {
+			return i == 0 || p.entries[i-1].node.Type() != "MetaProperty"
+		}
+		return strings.HasSuffix(typ, "Expression") || strings.HasSuffix(typ, "Literal") || typ == "MetaProperty"
+	case "function":
+		return typ == "FunctionDeclaration" || typ == "FunctionExpression" || typ == "ArrowFunctionExpression"
+	}
+	return false
+}
+
+// scopeSelector matches the node whose descendants :has is searching.
+func (scopeSelector) match(_ *matchPath, i int) bool { return i == 0 }
+
+func (s compoundSelector) match(p *matchPath, i int) bool {
+	for _, part := range s.parts {
+		if !part.match(p, i) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s matchesSelector) match(p *matchPath, i int) bool {
+	for _, alt := range s.alternatives {
+		if alt.match(p, i) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s notSelector) match(p *matchPath, i int) bool {
+	return !matchesSelector(s).match(p, i)
+}
+
+func (s hasSelector) match(p *matchPath, i int) bool {
+	sub := &matchPath{entries: []pathEntry{{node: p.entries[i].node, index: -1}}}
+	found := false
+
+	var visit func(key string, index int, child Node)
+	visit = func(key string, index int, child Node) {
+		if found {
+			return
+		}
+		sub.entries = append(sub.entries, pathEntry{node: child, key: key, index: index})
+		if (matchesSelector{s.alternatives}).match(sub, len(sub.entries)-1) {
+			found = true
+		} else {
+			eachChild(child, visit)
+		}
+		sub.entries = sub.entries[:len(sub.entries)-1]
+	}
+	eachChild(p.entries[i].node, visit)
+
+	return found
+}
+
+func (s nthChildSelector) match(p *matchPath, i int) bool {
+	index := p.entries[i].index
+	if i == 0 || index < 0 {
+		return false
+	}
+	if !s.fromEnd {
+		return index+1 == s.n
+	}
+
+	length := 0
+	p.siblings(i, func(index int, _ Node) {
+		length = index + 1
+	})
+	return length-index == s.n
+}
+
+func (s combinatorSelector) match(p *matchPath, i int) bool {
+	if !s.right.match(p, i) {
+		return false
+	}
+
+	switch s.op {
+	case '>':
+		return i > 0 && s.left.match(p, i-1)
+	case ' ':
+		for j := i - 1; j >= 0; j-- {
+			if s.left.match(p, j) {
+				return true
+			}
+		}
+		return false
+	default: // '~' and '+'
+		found := false
+		p.siblings(i, func(index int, node Node) {
+			before := index < p.entries[i].index
+			if s.op == '+' {
+				before = index == p.entries[i].index-1
+			}
+			if !found && before && s.left.match(p.withSibling(i, index, node), i) {
+				found = true
+			}
+		})
+		return found
+	}
+}
+
+func (s attributeSelector) match(p *matchPath, i int) bool {
+	v := attribute(p.entries[i].node, s.path)
+
+	switch {
+	case s.op == "":
+		return v.kind != kindUndefined && v.kind != kindNull
+	case s.kind != "":
+		kind := v.kind
+		if kind == kindNull {
+			kind = kindObject // typeof null
+		}
+		return (kind == s.kind) == (s.op == "=")
+	case s.regex != nil:
+		matched := v.kind == kindString && s.regex.MatchString(v.str)
+		return matched == (s.op == "=")
+	case s.op == "=":
+		return v.String() == s.value.String()
+	case s.op == "!=":
+		return v.String() != s.value.String()
+	default:
+		return compareAttributes(v, s.op, s.value)
+	}
+}
+
+// The JavaScript types an attribute value can have, as given by typeof.
+const (
+	kindUndefined = "undefined"
+	kindNull      = "null" // typeof null is "object"; null is kept apart to convert it
+	kindString    = "string"
+	kindNumber    = "number"
+	kindBoolean   = "boolean"
+	kindObject    = "object"
+)
+
+// attributeValue is the value of a node attribute, or of the literal it is
+// compared with, as JavaScript would see it.
+type attributeValue struct {
+	kind string
+	str  string
+	num  float64
+	b    bool
+}
+
+// String converts the value to a string like JavaScript's String().
+func (v attributeValue) String() string {
+	switch v.kind {
+	case kindString:
+		return v.str
+	case kindNumber:
+		return strconv.FormatFloat(v.num, 'f', -1, 64)
+	case kindBoolean:
+		return strconv.FormatBool(v.b)
+	case kindObject:
+		return "[object Object]"
+	default:
+		return v.kind
+	}
+}
+
+// number converts the value to a number like JavaScript's Number().
+func (v attributeValue) number() (float64, bool) {
+	switch v.kind {
+	case kindNumber:
+		return v.num, true
+	case kindBoolean:
+		if v.b {
+			return 1, true
+		}
+		return 0, true
+	case kindNull:
+		return 0, true
+	case kindString:
+		s := strings.TrimSpace(v.str)
+		if s == "" {
+			return 0, true
+		}
+		n, err := strconv.ParseFloat(s, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// compareAttributes compares two values with a relational operator, as
+// strings if both are strings and as numbers otherwise.
+func compareAttributes(a attributeValue, op string, b attributeValue) bool {
+	var cmp int
+	if a.kind == kindString && b.kind == kindString {
+		cmp = strings.Compare(a.str, b.str)
+	} else {
+		x, ok1 := a.number()
+		y, ok2 := b.number()
+		if !ok1 || !ok2 {
+			return false
+		}
+		switch {
+		case x < y:
+			cmp = -1
+		case x > y:
+			cmp = 1
+		}
+	}
+
+	switch op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default: // ">="
+		return cmp >= 0
+	}
+}
+
+// attribute returns the value at a path of JSON property names below node.
+func attribute(node Node, path []string) attributeValue {
+	v := reflect.ValueOf(node)
+	for _, name := range path {
+		v = indirect(v)
+		if !v.IsValid() {
+			return attributeValue{kind: kindUndefined}
+		}
+
+		//nolint:exhaustive // Only structs and lists have properties
+		switch v.Kind() {
+		case reflect.Struct:
+			field, ok := jsonFields(v.Type())[name]
+			if !ok {
+				return attributeValue{kind: kindUndefined}
+			}
+			v = v.FieldByIndex(field.index)
+			if field.omitEmpty && isNil(v) {
+				// The property is missing from the JSON form of the node.
+				return attributeValue{kind: kindUndefined}
+			}
+		case reflect.Slice, reflect.Array:
+			if name == "length" {
+				return attributeValue{kind: kindNumber, num: float64(v.Len())}
+			}
+			i, err := strconv.Atoi(name)
+			if err != nil || i < 0 || i >= v.Len() {
+				return attributeValue{kind: kindUndefined}
+			}
+			v = v.Index(i)
+		default:
+			return attributeValue{kind: kindUndefined}
+		}
+	}
+	return valueOf(v)
+}
+
+// indirect follows pointers and interfaces, returning an invalid value for nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func valueOf(v reflect.Value) attributeValue {
+	if !v.IsValid() {
+		return attributeValue{kind: kindUndefined}
+	}
+	if isNil(v) {
+		return attributeValue{kind: kindNull}
+	}
+
+	v = indirect(v)
+	//nolint:exhaustive // Other kinds are objects
+	switch v.Kind() {
+	case reflect.String:
+		return attributeValue{kind: kindString, str: v.String()}
+	case reflect.Bool:
+		return attributeValue{kind: kindBoolean, b: v.Bool()}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return attributeValue{kind: kindNumber, num: float64(v.Int())}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return attributeValue{kind: kindNumber, num: float64(v.Uint())}
+	case reflect.Float32, reflect.Float64:
+		return attributeValue{kind: kindNumber, num: v.Float()}
+	default:
+		return attributeValue{kind: kindObject}
+	}
+}
+
+func isNil(v reflect.Value) bool {
+	//nolint:exhaustive // Only these kinds can be nil
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return v.IsNil()
+	}
+	return false
+}
+
+// jsonField locates a field by its JSON name.
+type jsonField struct {
+	index     []int
+	omitEmpty bool
+}
+
+// jsonFieldCache maps struct types to the field indexes of their JSON names.
+var jsonFieldCache sync.Map
+
+// jsonFields returns the index of each field of struct type t by its JSON
+// name, including the fields of embedded structs, as encoding/json names them.
+func jsonFields(t reflect.Type) map[string]jsonField {
+	if fields, ok := jsonFieldCache.Load(t); ok {
+		return fields.(map[string]jsonField)
+	}
+
+	fields := make(map[string]jsonField)
+	var collect func(t reflect.Type, index []int)
+	collect = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			name, opts, _ := strings.Cut(tag, ",")
+			if !f.IsExported() || name == "-" {
+				continue
+			}
+			fieldIndex := append(index[:len(index):len(index)], i)
+			if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+				collect(f.Type, fieldIndex)
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			// Fields of the outer struct take precedence over embedded ones.
+			if existing, ok := fields[name]; !ok || len(existing.index) > len(fieldIndex) {
+				fields[name] = jsonField{index: fieldIndex, omitEmpty: strings.Contains(opts, "omitempty")}
+			}
+		}
+	}
+	collect(t, nil)
+
+	jsonFieldCache.Store(t, fields)
+	return fields
+}
+
+// specificity returns the number of attribute-like atoms (attributes,
+// fields, classes and positions) and of node types in a selector. ESLint
+// calls the handlers of less specific selectors first.
+func specificity(n selectorNode) (attributes, identifiers int) {
+	add := func(nodes ...selectorNode) {
+		for _, node := range nodes {
+			a, i := specificity(node)
+			attributes += a
+			identifiers += i
+		}
+	}
+
+	switch s := n.(type) {
+	case typeSelector:
+		identifiers = 1
+	case attributeSelector, fieldSelector, classSelector, nthChildSelector:
+		attributes = 1
+	case compoundSelector:
+		add(s.parts...)
+	case matchesSelector:
+		add(s.alternatives...)
+	case notSelector:
+		add(s.alternatives...)
+	case hasSelector:
+		add(s.alternatives...)
+	case combinatorSelector:
+		add(s.left, s.right)
+	}
+	return attributes, identifiers
+}
+
+// nodeTypes returns the node types a selector can match, or nil if it can
+// match any type.
+func nodeTypes(n selectorNode) []string {
+	switch s := n.(type) {
+	case typeSelector:
+		return []string{s.name}
+	case combinatorSelector:
+		return nodeTypes(s.right)
+	case matchesSelector:
+		var types []string
+		for _, alt := range s.alternatives {
+			altTypes := nodeTypes(alt)
+			if altTypes == nil {
+				return nil
+			}
+			types = append(types, altTypes...)
+		}
+		return types
+	case compoundSelector:
+		var types []string
+		for _, part := range s.parts {
+			partTypes := nodeTypes(part)
+			if partTypes == nil {
+				continue
+			}
+			if types == nil {
+				types = partTypes
+				continue
+			}
+			var both []string
+			for _, t := range types {
+				for _, pt := range partTypes {
+					if t == pt {
+						both = append(both, t)
+					}
+				}
+			}
+			types = both
+			if types == nil {
+				types = []string{} // matches no type
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// selectorParser parses the esquery selector syntax.
+type selectorParser struct {
+	src string
+	pos int
+}
+
+func (p *selectorParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid selector %q at offset %d: %s", p.src, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *selectorParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *selectorParser) skipSpace() bool {
+	start := p.pos
+	for p.pos < len(p.src) && strings.IndexByte(" \t\r\n\f", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+func (p *selectorParser) consume(s string) bool {
+	if strings.HasPrefix(p.src[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *selectorParser) expect(s string) error {
+	if !p.consume(s) {
+		return p.errorf("expected %q", s)
+	}
+	return nil
+}
+
+// parseSelectors parses a comma-separated list of selectors.
+func (p *selectorParser) parseSelectors() (selectorNode, error) {
+	return p.parseList(func() (selectorNode, error) {
+		return p.parseChain(nil)
+	})
+}
+
+// parseRelativeSelectors parses the argument of :has, whose selectors are
+// relative to the node being tested and may start with '>'.
+func (p *selectorParser) parseRelativeSelectors() (selectorNode, error) {
+	return p.parseList(func() (selectorNode, error) {
+		if p.consume(">") {
+			p.skipSpace()
+			right, err := p.parseSequence()
+			if err != nil {
+				return nil, err
+			}
+			return p.parseChain(combinatorSelector{op: '>', left: scopeSelector{}, right: right})
+		}
+		right, err := p.parseSequence()
+		if err != nil {
+			return nil, err
+		}
+		return p.parseChain(combinatorSelector{op: ' ', left: scopeSelector{}, right: right})
+	})
+}
+
+func (p *selectorParser) parseList(parseOne func() (selectorNode, error)) (selectorNode, error) {
+	var alternatives []selectorNode
+	for {
+		sel, err := parseOne()
+		if err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, sel)
+
+		p.skipSpace()
+		if !p.consume(",") {
+			break
+		}
+		p.skipSpace()
+	}
+
+	if len(alternatives) == 1 {
+		return alternatives[0], nil
+	}
+	return matchesSelector{alternatives}, nil
+}
+
+// parseChain parses sequences joined by combinators. If left is not nil, it
+// is the selector before the first combinator.
+func (p *selectorParser) parseChain(left selectorNode) (selectorNode, error) {
+	if left == nil {
+		var err error
+		if left, err = p.parseSequence(); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		start := p.pos
+		space := p.skipSpace()
+
+		var op byte
+		switch c := p.peek(); {
+		case c == '>' || c == '~' || c == '+':
+			op = c
+			p.pos++
+			p.skipSpace()
+		case c == 0 || c == ',' || c == ')':
+			p.pos = start
+			return left, nil
+		case space:
+			op = ' '
+		default:
+			return nil, p.errorf("unexpected %q", c)
+		}
+
+		right, err := p.parseSequence()
+		if err != nil {
+			return nil, err
+		}
+		left = combinatorSelector{op: op, left: left, right: right}
+	}
+}
+
+// parseSequence parses atoms written together, all of which must match.
+func (p *selectorParser) parseSequence() (selectorNode, error) {
+	var parts []selectorNode
+	for {
+		atom, err := p.parseAtom()
+		if err != nil {
+			return nil, err
+		}
+		if atom == nil {
+			break
+		}
+		parts = append(parts, atom)
+	}
+
+	switch len(parts) {
+	case 0:
+		if p.pos == len(p.src) {
+			return nil, p.errorf("unexpected end of selector")
+		}
+		return nil, p.errorf("unexpected %q", p.src[p.pos])
+	case 1:
+		return parts[0], nil
+	default:
+		return compoundSelector{parts}, nil
+	}
+}
+
+// parseAtom parses a single atom, or returns nil if none starts here.
+func (p *selectorParser) parseAtom() (selectorNode, error) {
+	switch c := p.peek(); c {
+	case '*':
+		p.pos++
+		return wildcardSelector{}, nil
+	case '[':
+		p.pos++
+		return p.parseAttribute()
+	case '.':
+		p.pos++
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		return fieldSelector{path}, nil
+	case ':':
+		p.pos++
+		return p.parsePseudo()
+	default:
+		if name := p.identifier(); name != "" {
+			return typeSelector{name}, nil
+		}
+		return nil, nil
+	}
+}
+
+// identifier consumes a name: a run of characters without special meaning.
+func (p *selectorParser) identifier() string {
+	start := p.pos
+	for p.pos < len(p.src) && !strings.ContainsRune(" \t\r\n\f[],():#!=<>~+.*'\"/", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// parsePath parses names separated by dots.
+func (p *selectorParser) parsePath() ([]string, error) {
+	var path []string
+	for {
+		name := p.identifier()
+		if name == "" {
+			return nil, p.errorf("expected name")
+		}
+		path = append(path, name)
+		if p.peek() != '.' {
+			return path, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *selectorParser) parseAttribute() (selectorNode, error) {
+	p.skipSpace()
+	path, err := p.parsePath()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+
+	sel := attributeSelector{path: path}
+	if p.consume("]") {
+		return sel, nil
+	}
+
+	for _, op := range []string{"!=", "<=", ">=", "=", "<", ">"} {
+		if p.consume(op) {
+			sel.op = op
+			break
+		}
+	}
+	if sel.op == "" {
+		return nil, p.errorf("expected attribute operator")
+	}
+	p.skipSpace()
+
+	switch c := p.peek(); {
+	case c == '"' || c == '\'':
+		s, err := p.parseString(c)
+		if err != nil {
+			return nil, err
+		}
+		sel.value = attributeValue{kind: kindString, str: s}
+	case c == '/':
+		if sel.regex, err = p.parseRegExp(); err != nil {
+			return nil, err
+		}
+	case strings.HasPrefix(p.src[p.pos:], "type("):
+		p.pos += len("type(")
+		p.skipSpace()
+		sel.kind = p.identifier()
+		p.skipSpace()
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	case c >= '0' && c <= '9', c == '.', c == '-':
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.src) && (p.src[p.pos] >= '0' && p.src[p.pos] <= '9' || p.src[p.pos] == '.') {
+			p.pos++
+		}
+		n, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", p.src[start:p.pos])
+		}
+		sel.value = attributeValue{kind: kindNumber, num: n}
+	default:
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		sel.value = attributeValue{kind: kindString, str: strings.Join(path, ".")}
+	}
+
+	if (sel.regex != nil || sel.kind != "") && sel.op != "=" && sel.op != "!=" {
+		return nil, p.errorf("operator %s cannot be used with a regular expression or type", sel.op)
+	}
+
+	p.skipSpace()
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	return sel, nil
+}
+
+func (p *selectorParser) parseString(quote byte) (string, error) {
+	p.pos++
+	var sb strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			return sb.String(), nil
+		case c == '\\' && p.pos < len(p.src):
+			sb.WriteByte(p.src[p.pos])
+			p.pos++
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *selectorParser) parseRegExp() (*regexp.Regexp, error) {
+	p.pos++
+	start := p.pos
+	inClass := false
+	for ; p.pos < len(p.src); p.pos++ {
+		switch c := p.src[p.pos]; {
+		case c == '\\':
+			p.pos++
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '/' && !inClass:
+			pattern := p.src[start:p.pos]
+			p.pos++
+
+			flags := ""
+			for p.pos < len(p.src) && strings.IndexByte("imsu", p.src[p.pos]) >= 0 {
+				if c := p.src[p.pos]; c != 'u' {
+					flags += string(c)
+				}
+				p.pos++
+			}
+			if flags != "" {
+				pattern = "(?" + flags + ")" + pattern
+			}
+
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, p.errorf("invalid regular expression: %v", err)
+			}
+			return re, nil
+		}
+	}
+	return nil, p.errorf("unterminated regular expression")
+}
+
+func (p *selectorParser) parsePseudo() (selectorNode, error) {
+	name := p.identifier()
+
+	switch name {
+	case "statement", "expression", "declaration", "pattern", "function":
+		return classSelector{name}, nil
+	case "first-child":
+		return nthChildSelector{n: 1}, nil
+	case "last-child":
+		return nthChildSelector{n: 1, fromEnd: true}, nil
+	case "not", "matches", "is", "has", "nth-child", "nth-last-child":
+	default:
+		return nil, p.errorf("unknown pseudo-class :%s", name)
+	}
+
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+
+	var sel selectorNode
+	switch name {
+	case "nth-child", "nth-last-child":
+		start := p.pos
+		for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+			p.pos++
+		}
+		n, err := strconv.Atoi(p.src[start:p.pos])
+		if err != nil || n < 1 {
+			p.pos = start
+			return nil, p.errorf("expected a positive integer")
+		}
+		sel = nthChildSelector{n: n, fromEnd: name == "nth-last-child"}
+	case "has":
+		inner, err := p.parseRelativeSelectors()
+		if err != nil {
+			return nil, err
+		}
+		sel = hasSelector{alternatives(inner)}
+	default:
+		inner, err := p.parseSelectors()
+		if err != nil {
+			return nil, err
+		}
+		if name == "not" {
+			sel = notSelector{alternatives(inner)}
+		} else {
+			sel = matchesSelector{alternatives(inner)}
+		}
+	}
+
+	p.skipSpace()
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return sel, nil
+}
+
+// alternatives returns the selectors of a comma-separated list.
+func alternatives(n selectorNode) []selectorNode {
+	if m, ok := n.(matchesSelector); ok {
+		return m.alternatives
+	}
+	return []selectorNode{n}
+}
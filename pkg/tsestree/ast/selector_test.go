@@ -0,0 +1,287 @@
+package ast_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
+	"github.com/kdy1/go-typescript-eslint/pkg/typescriptestree"
+)
+
+func parseForSelector(t *testing.T, source string) *ast.Program {
+	t.Helper()
+	result, err := typescriptestree.Parse(source, nil)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return result.AST
+}
+
+// describeNode describes a node by its type and the first name or literal in it.
+func describeNode(n ast.Node) string {
+	var first ast.Node
+	ast.Traverse(n, func(n ast.Node) bool {
+		if first == nil && (ast.IsIdentifier(n) || ast.IsLiteral(n)) {
+			first = n
+		}
+		return first == nil
+	})
+	switch first := first.(type) {
+	case *ast.Identifier:
+		return n.Type() + " " + first.Name
+	case *ast.Literal:
+		return n.Type() + " " + first.Raw
+	}
+	return n.Type()
+}
+
+func TestSelectorVisitor_Matches(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		selector string
+		want     []string
+	}{
+		{
+			name:     "node type",
+			source:   `f(a); g.h(b);`,
+			selector: "CallExpression",
+			want:     []string{"CallExpression f", "CallExpression g"},
+		},
+		{
+			name:     "child and field",
+			source:   `g.h(b); x.y.z;`,
+			selector: "MemberExpression > Identifier.property",
+			want:     []string{"Identifier h", "Identifier y", "Identifier z"},
+		},
+		{
+			name:     "nested field",
+			source:   `a.b(c.d);`,
+			selector: "Identifier.callee.property",
+			want:     []string{"Identifier b"},
+		},
+		{
+			name:     "descendant",
+			source:   `x = 1 + (2 * 3); y = 4;`,
+			selector: `BinaryExpression[operator="+"] Literal`,
+			want:     []string{"Literal 1", "Literal 2", "Literal 3"},
+		},
+		{
+			name:     "attribute string",
+			source:   `foo(bar, foo);`,
+			selector: `[name="foo"]`,
+			want:     []string{"Identifier foo", "Identifier foo"},
+		},
+		{
+			name:     "attribute regular expression",
+			source:   `apple; Avocado; banana;`,
+			selector: `Identifier[name=/^a/i]`,
+			want:     []string{"Identifier apple", "Identifier Avocado"},
+		},
+		{
+			name:     "attribute path and length",
+			source:   `f(1); f(1, 2); g(1, 2);`,
+			selector: `CallExpression[callee.name="f"][arguments.length=2]`,
+			want:     []string{"CallExpression f"},
+		},
+		{
+			name:     "attribute comparison",
+			source:   `[1, 5, 10];`,
+			selector: `Literal[value>=5]`,
+			want:     []string{"Literal 5", "Literal 10"},
+		},
+		{
+			name:     "attribute presence and boolean",
+			source:   `a[b]; a.c; function f() { return; return 1; }`,
+			selector: `MemberExpression[computed=true], ReturnStatement[argument]`,
+			want:     []string{"MemberExpression a", "ReturnStatement 1"},
+		},
+		{
+			name:     "attribute type",
+			source:   `f(1);`,
+			selector: `:expression[name=type(string)]`,
+			want:     []string{"Identifier f"},
+		},
+		{
+			name:     "matches and not",
+			source:   `function f() {} const g = () => 1; const h = function () {};`,
+			selector: `:matches(FunctionDeclaration, ArrowFunctionExpression, FunctionExpression):not(FunctionExpression)`,
+			want:     []string{"FunctionDeclaration f", "ArrowFunctionExpression 1"},
+		},
+		{
+			name:     "has",
+			source:   `function f() { if (a) { return 1; } } function g() {}`,
+			selector: `FunctionDeclaration:has(ReturnStatement)`,
+			want:     []string{"FunctionDeclaration f"},
+		},
+		{
+			name:     "has child",
+			source:   `f(a); g(h(b));`,
+			selector: `CallExpression:has(> CallExpression)`,
+			want:     []string{"CallExpression g"},
+		},
+		{
+			name:     "nth-child",
+			source:   `let a = 1, b = 2, c = 3;`,
+			selector: `VariableDeclarator:nth-child(2), VariableDeclarator:last-child`,
+			want:     []string{"VariableDeclarator b", "VariableDeclarator c"},
+		},
+		{
+			name:     "first-child and nth-last-child",
+			source:   `f(a, b, c);`,
+			selector: `Identifier:first-child, Identifier:nth-last-child(2)`,
+			want:     []string{"Identifier a", "Identifier b"},
+		},
+		{
+			name:     "sibling",
+			source:   `a; b; c;`,
+			selector: `ExpressionStatement ~ ExpressionStatement`,
+			want:     []string{"ExpressionStatement b", "ExpressionStatement c"},
+		},
+		{
+			name:     "adjacent sibling",
+			source:   `let x; a; b;`,
+			selector: `VariableDeclaration + ExpressionStatement`,
+			want:     []string{"ExpressionStatement a"},
+		},
+		{
+			name:     "classes",
+			source:   `function f() { return x; }`,
+			selector: `:function, :statement > :expression`,
+			want:     []string{"FunctionDeclaration f", "Identifier f", "Identifier x"},
+		},
+		{
+			name:     "wildcard",
+			source:   `a(b);`,
+			selector: `CallExpression > *`,
+			want:     []string{"Identifier a", "Identifier b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program := parseForSelector(t, tt.source)
+
+			var got []string
+			visitor, err := ast.NewSelectorVisitor(ast.SelectorListeners{
+				tt.selector: func(n ast.Node) {
+					got = append(got, describeNode(n))
+				},
+			})
+			if err != nil {
+				t.Fatalf("NewSelectorVisitor() error = %v", err)
+			}
+			visitor.Walk(program)
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSelectorVisitor_Order(t *testing.T) {
+	program := parseForSelector(t, `f(x);`)
+
+	var events []string
+	record := func(event string) func(ast.Node) {
+		return func(ast.Node) { events = append(events, event) }
+	}
+
+	visitor, err := ast.NewSelectorVisitor(
+		ast.SelectorListeners{
+			"CallExpression":                     record("call"),
+			"CallExpression:exit":                record("call:exit"),
+			"CallExpression[arguments.length=1]": record("call with one argument"),
+			"Identifier":                         record("identifier"),
+			"CallExpression > Identifier.callee": record("callee"),
+			"*":                                  record("any"),
+			"Program:exit":                       record("program:exit"),
+		},
+		ast.SelectorListeners{
+			"CallExpression": record("second call"),
+		},
+	)
+	if err != nil {
+		t.Fatalf("NewSelectorVisitor() error = %v", err)
+	}
+	visitor.Walk(program)
+
+	want := []string{
+		"any", // Program
+		"any", // ExpressionStatement
+		"any", "call", "second call", "call with one argument",
+		"any", "identifier", "callee", // f
+		"any", "identifier", // x
+		"call:exit",
+		"program:exit",
+	}
+	if !slices.Equal(events, want) {
+		t.Errorf("expected events\n%q\ngot\n%q", want, events)
+	}
+}
+
+func TestParseSelector_Errors(t *testing.T) {
+	for _, selector := range []string{
+		"",
+		"CallExpression >",
+		"[name",
+		`[name="x]`,
+		"[name=/x/ ",
+		"[name<type(string)]",
+		":unknown",
+		":nth-child(0)",
+		":not(Identifier",
+		"Identifier)",
+		"A B!",
+	} {
+		if _, err := ast.ParseSelector(selector); err == nil {
+			t.Errorf("ParseSelector(%q): expected error", selector)
+		}
+	}
+
+	if _, err := ast.NewSelectorVisitor(ast.SelectorListeners{"[": func(ast.Node) {}}); err == nil {
+		t.Error("NewSelectorVisitor: expected error for invalid selector")
+	}
+}
+
+func TestSelector_Matches(t *testing.T) {
+	program := parseForSelector(t, `a.b;`)
+	stmt := program.Body[0].(*ast.ExpressionStatement)
+	member := stmt.Expression.(*ast.MemberExpression)
+
+	sel := ast.MustParseSelector("ExpressionStatement > MemberExpression > Identifier.property")
+	if !sel.Matches(member.Property, []ast.Node{program, stmt, member}) {
+		t.Error("expected property to match")
+	}
+	if sel.Matches(member.Object, []ast.Node{program, stmt, member}) {
+		t.Error("expected object not to match")
+	}
+	if sel.String() != "ExpressionStatement > MemberExpression > Identifier.property" {
+		t.Errorf("unexpected String() %q", sel.String())
+	}
+}
+
+// ExampleSelectorVisitor reports calls of console methods, as an ESLint rule
+// would.
+func ExampleSelectorVisitor() {
+	result, err := typescriptestree.Parse(`console.log("a"); console.warn("b"); log("c");`, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	visitor, err := ast.NewSelectorVisitor(ast.SelectorListeners{
+		`CallExpression > MemberExpression.callee[object.name="console"]`: func(n ast.Node) {
+			member := n.(*ast.MemberExpression)
+			fmt.Println("console." + member.Property.(*ast.Identifier).Name)
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	visitor.Walk(result.AST)
+	// Output:
+	// console.log
+	// console.warn
+}
@@ -0,0 +1,155 @@
+package ast
+
+import (
+	"sort"
+	"strings"
+)
+
+// SelectorListeners maps selectors to the handlers to call for the nodes they
+// match, like the object an ESLint rule's create function returns. A handler
+// is called when traversal enters a matching node, or, if its selector ends
+// in ":exit", when traversal leaves it after all of its descendants:
+//
+//	ast.SelectorListeners{
+//		"CallExpression":                         onCall,
+//		"CallExpression:exit":                    afterCall,
+//		"MemberExpression > Identifier.property": onProperty,
+//	}
+type SelectorListeners map[string]func(node Node)
+
+// SelectorVisitor calls the handlers of a set of listeners during a single
+// traversal of a tree, matching each node only against the selectors that
+// can match its type. Selectors are compiled once, by NewSelectorVisitor, so
+// a SelectorVisitor can be reused for any number of trees.
+//
+// For each node, handlers are called in order of increasing specificity of
+// their selectors, as in ESLint: selectors with fewer attributes, fields and
+// pseudo-classes first, then those with fewer node types, then in
+// lexicographic order of the selectors. Handlers with the same selector are
+// called in the order of the listeners that registered them.
+type SelectorVisitor struct {
+	enter listenerIndex
+	exit  listenerIndex
+}
+
+// selectorListener is a handler with its compiled selector.
+type selectorListener struct {
+	selector *Selector
+	handler  func(node Node)
+	order    int // the position of the listener in dispatch order
+}
+
+// listenerIndex holds the listeners for one phase of traversal, by the node
+// types they can match.
+type listenerIndex struct {
+	byType  map[string][]*selectorListener
+	anyType []*selectorListener
+}
+
+// NewSelectorVisitor compiles the selectors of listeners. It returns an error
+// if any selector is invalid.
+func NewSelectorVisitor(listeners ...SelectorListeners) (*SelectorVisitor, error) {
+	var enter, exit []*selectorListener
+	for _, l := range listeners {
+		// Map order is random, so sort each map's selectors before adding
+		// them; the order of listeners with the same selector is kept.
+		selectors := make([]string, 0, len(l))
+		for s := range l {
+			selectors = append(selectors, s)
+		}
+		sort.Strings(selectors)
+
+		for _, s := range selectors {
+			handler := l[s]
+			if handler == nil {
+				continue
+			}
+
+			source, isExit := strings.CutSuffix(s, ":exit")
+			sel, err := ParseSelector(source)
+			if err != nil {
+				return nil, err
+			}
+
+			listener := &selectorListener{selector: sel, handler: handler}
+			if isExit {
+				exit = append(exit, listener)
+			} else {
+				enter = append(enter, listener)
+			}
+		}
+	}
+
+	return &SelectorVisitor{
+		enter: newListenerIndex(enter),
+		exit:  newListenerIndex(exit),
+	}, nil
+}
+
+func newListenerIndex(listeners []*selectorListener) listenerIndex {
+	sort.SliceStable(listeners, func(i, j int) bool {
+		a, b := listeners[i].selector, listeners[j].selector
+		if a.attributes != b.attributes {
+			return a.attributes < b.attributes
+		}
+		if a.identifiers != b.identifiers {
+			return a.identifiers < b.identifiers
+		}
+		return a.raw < b.raw
+	})
+
+	index := listenerIndex{byType: make(map[string][]*selectorListener)}
+	for i, l := range listeners {
+		l.order = i
+		if l.selector.types == nil {
+			index.anyType = append(index.anyType, l)
+			continue
+		}
+		for _, t := range l.selector.types {
+			if list := index.byType[t]; len(list) == 0 || list[len(list)-1] != l {
+				index.byType[t] = append(list, l)
+			}
+		}
+	}
+	return index
+}
+
+// Walk traverses the tree rooted at root in depth-first order, calling the
+// handlers of the selectors that match each node.
+func (v *SelectorVisitor) Walk(root Node) {
+	if root == nil {
+		return
+	}
+
+	path := &matchPath{}
+	var visit func(key string, index int, node Node)
+	visit = func(key string, index int, node Node) {
+		path.entries = append(path.entries, pathEntry{node: node, key: key, index: index})
+		v.enter.dispatch(path)
+		eachChild(node, visit)
+		v.exit.dispatch(path)
+		path.entries = path.entries[:len(path.entries)-1]
+	}
+	visit("", -1, root)
+}
+
+// dispatch calls the handlers whose selectors match the last node of path,
+// merging the listeners for its type with those for any type in order.
+func (index *listenerIndex) dispatch(path *matchPath) {
+	i := len(path.entries) - 1
+	node := path.entries[i].node
+	typed, untyped := index.byType[node.Type()], index.anyType
+
+	for len(typed) > 0 || len(untyped) > 0 {
+		var l *selectorListener
+		if len(untyped) == 0 || (len(typed) > 0 && typed[0].order < untyped[0].order) {
+			l, typed = typed[0], typed[1:]
+		} else {
+			l, untyped = untyped[0], untyped[1:]
+		}
+
+		if l.selector.root.match(path, i) {
+			l.handler(node)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package ast
 
-import "reflect"
+//go:generate go run ./internal/walkgen
+
+import (
+	"reflect"
+	"strings"
+)
 
 // Visitor is the interface for AST node visitors.
 // Implementations can define Visit methods to handle specific node types.
@@ -25,78 +30,77 @@ func Walk(node Node, visitor Visitor) {
 		return
 	}
 
-	// Visit the current node
-	if !visitor.Visit(node) {
-		return
+	var walk func(key string, index int, child Node)
+	walk = func(_ string, _ int, child Node) {
+		if visitor.Visit(child) {
+			eachChild(child, walk)
+		}
+	}
+	walk("", -1, node)
+}
+
+// eachChild calls fn for each child of node, in the order of its visitor
+// keys, with the key of the property holding the child and its index if the
+// property is a list, or -1. Node types known when walk_gen.go was generated
+// are handled without reflection.
+func eachChild(node Node, fn func(key string, index int, child Node)) {
+	if !forEachChild(node, fn) {
+		reflectChildren(node, fn)
 	}
+}
 
-	// Get visitor keys for this node type
+// reflectChildren is eachChild for node types the generated code does not
+// know, such as a node whose NodeType differs from its Go type. It finds the
+// child properties with reflection.
+func reflectChildren(node Node, fn func(key string, index int, child Node)) {
 	keys := GetVisitorKeys(node.Type())
 	if len(keys) == 0 {
 		return
 	}
 
-	// Use reflection to access child nodes
 	nodeValue := reflect.ValueOf(node)
 	if nodeValue.Kind() == reflect.Ptr {
 		nodeValue = nodeValue.Elem()
 	}
 
-	// Traverse each child property
 	for _, key := range keys {
-		field := nodeValue.FieldByName(capitalizeFirst(key))
+		field := fieldByKey(nodeValue, key)
 		if !field.IsValid() {
 			continue
 		}
 
-		traverseField(field, visitor)
-	}
-}
-
-// traverseField handles traversal of a field value, which may be a node,
-// a slice of nodes, or a pointer to a node.
-func traverseField(field reflect.Value, visitor Visitor) {
-	//nolint:exhaustive // Only specific reflection kinds need handling
-	switch field.Kind() {
-	case reflect.Ptr:
-		traversePointerField(field, visitor)
-	case reflect.Slice:
-		traverseSliceField(field, visitor)
-	case reflect.Interface:
-		traverseInterfaceField(field, visitor)
-	default:
-		// Other types (Bool, Int, String, etc.) are not traversable nodes
-		return
-	}
-}
-
-func traversePointerField(field reflect.Value, visitor Visitor) {
-	if !field.IsNil() {
-		if node, ok := field.Interface().(Node); ok {
-			Walk(node, visitor)
-		}
-	}
-}
-
-func traverseSliceField(field reflect.Value, visitor Visitor) {
-	for i := 0; i < field.Len(); i++ {
-		elem := field.Index(i)
-		if elem.Kind() == reflect.Ptr && !elem.IsNil() {
-			if node, ok := elem.Interface().(Node); ok {
-				Walk(node, visitor)
+		//nolint:exhaustive // Only specific reflection kinds need handling
+		switch field.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			if !field.IsNil() {
+				if child, ok := field.Interface().(Node); ok {
+					fn(key, -1, child)
+				}
 			}
-		} else if node, ok := elem.Interface().(Node); ok {
-			Walk(node, visitor)
+		case reflect.Slice:
+			for i := 0; i < field.Len(); i++ {
+				if child := extractNodeFromElement(field.Index(i)); child != nil {
+					fn(key, i, child)
+				}
+			}
+		default:
+			// Other types (Bool, Int, String, etc.) are not traversable nodes
 		}
 	}
 }
 
-func traverseInterfaceField(field reflect.Value, visitor Visitor) {
-	if !field.IsNil() {
-		if node, ok := field.Interface().(Node); ok {
-			Walk(node, visitor)
-		}
+// fieldByKey returns the struct field holding the child property named by a
+// visitor key. Keys are camelCase while fields may spell initialisms in
+// capitals (e.g. "id" is stored in ID), so it falls back to a case-insensitive match.
+//
+// The generator of walk_gen.go resolves keys to fields by the same rules.
+func fieldByKey(v reflect.Value, key string) reflect.Value {
+	if field := v.FieldByName(capitalizeFirst(key)); field.IsValid() {
+		return field
 	}
+	return v.FieldByNameFunc(func(name string) bool {
+		return strings.EqualFold(name, key)
+	})
 }
 
 // capitalizeFirst capitalizes the first letter of a string (for field name lookup).
@@ -152,33 +156,20 @@ func walkWithContextInternal(node Node, visitor ContextVisitor, ctx *TraverseCon
 		return
 	}
 
-	// Get visitor keys for this node type
-	keys := GetVisitorKeys(node.Type())
-	if len(keys) == 0 {
-		return
-	}
-
-	// Use reflection to access child nodes
-	nodeValue := reflect.ValueOf(node)
-	if nodeValue.Kind() == reflect.Ptr {
-		nodeValue = nodeValue.Elem()
-	}
-
-	// Update context for children
+	// The ancestors of the children are computed once, for the first child.
 	var newAncestors []Node
-	if ctx.Parent != nil {
-		newAncestors = make([]Node, len(ctx.Ancestors)+1)
-		copy(newAncestors, ctx.Ancestors)
-		newAncestors[len(ctx.Ancestors)] = ctx.Parent
-	} else {
-		newAncestors = ctx.Ancestors
-	}
+	linked := false
 
-	// Traverse each child property
-	for _, key := range keys {
-		field := nodeValue.FieldByName(capitalizeFirst(key))
-		if !field.IsValid() {
-			continue
+	eachChild(node, func(key string, index int, child Node) {
+		if !linked {
+			linked = true
+			if ctx.Parent != nil {
+				newAncestors = make([]Node, len(ctx.Ancestors)+1)
+				copy(newAncestors, ctx.Ancestors)
+				newAncestors[len(ctx.Ancestors)] = ctx.Parent
+			} else {
+				newAncestors = ctx.Ancestors
+			}
 		}
 
 		childCtx := &TraverseContext{
@@ -186,60 +177,11 @@ func walkWithContextInternal(node Node, visitor ContextVisitor, ctx *TraverseCon
 			Ancestors: newAncestors,
 			Key:       key,
 		}
-
-		traverseFieldWithContext(field, visitor, childCtx)
-	}
-}
-
-func traverseFieldWithContext(field reflect.Value, visitor ContextVisitor, ctx *TraverseContext) {
-	//nolint:exhaustive // Only specific reflection kinds need handling
-	switch field.Kind() {
-	case reflect.Ptr:
-		traversePointerWithContext(field, visitor, ctx)
-	case reflect.Slice:
-		traverseSliceWithContext(field, visitor, ctx)
-	case reflect.Interface:
-		traverseInterfaceWithContext(field, visitor, ctx)
-	default:
-		// Other types (Bool, Int, String, etc.) are not traversable nodes
-		return
-	}
-}
-
-func traversePointerWithContext(field reflect.Value, visitor ContextVisitor, ctx *TraverseContext) {
-	if !field.IsNil() {
-		if node, ok := field.Interface().(Node); ok {
-			walkWithContextInternal(node, visitor, ctx)
-		}
-	}
-}
-
-func traverseSliceWithContext(field reflect.Value, visitor ContextVisitor, ctx *TraverseContext) {
-	for i := 0; i < field.Len(); i++ {
-		elem := field.Index(i)
-		idx := i // Create a new variable for each iteration
-		childCtx := &TraverseContext{
-			Parent:    ctx.Parent,
-			Ancestors: ctx.Ancestors,
-			Key:       ctx.Key,
-			Index:     &idx,
-		}
-		if elem.Kind() == reflect.Ptr && !elem.IsNil() {
-			if node, ok := elem.Interface().(Node); ok {
-				walkWithContextInternal(node, visitor, childCtx)
-			}
-		} else if node, ok := elem.Interface().(Node); ok {
-			walkWithContextInternal(node, visitor, childCtx)
-		}
-	}
-}
-
-func traverseInterfaceWithContext(field reflect.Value, visitor ContextVisitor, ctx *TraverseContext) {
-	if !field.IsNil() {
-		if node, ok := field.Interface().(Node); ok {
-			walkWithContextInternal(node, visitor, ctx)
+		if index >= 0 {
+			childCtx.Index = &index
 		}
-	}
+		walkWithContextInternal(child, visitor, childCtx)
+	})
 }
 
 // Traverse is a convenience function that traverses an AST with a simple callback.
@@ -286,10 +228,47 @@ func FindByType(root Node, nodeType string) []Node {
 	})
 }
 
+// SetParents links every node below root to its parent, so that Parent and
+// Ancestors answer without searching the tree. The link of root itself is
+// left unchanged.
+func SetParents(root Node) {
+	TraverseWithContext(root, func(node Node, ctx *TraverseContext) bool {
+		if b, ok := node.(interface{ SetParent(Node) }); ok && ctx.Parent != nil {
+			b.SetParent(ctx.Parent)
+		}
+		return true
+	})
+}
+
+// Parent returns the parent of n recorded by SetParents, or nil.
+//
+//nolint:ireturn // Interface types are intentional for generic AST node retrieval
+func Parent(n Node) Node {
+	if b, ok := n.(interface{ Parent() Node }); ok {
+		return b.Parent()
+	}
+	return nil
+}
+
+// Ancestors returns the ancestors of n recorded by SetParents, from the root
+// down to its parent.
+func Ancestors(n Node) []Node {
+	if b, ok := n.(interface{ Ancestors() []Node }); ok {
+		return b.Ancestors()
+	}
+	return nil
+}
+
 // GetParent returns the parent node of the target node, or nil if not found.
+// If the tree has been linked with SetParents, the link is used instead of
+// searching from root.
 //
 //nolint:ireturn // Interface types are intentional for generic AST node retrieval
 func GetParent(root, target Node) Node {
+	if parent := Parent(target); parent != nil {
+		return parent
+	}
+
 	var parent Node
 	TraverseWithContext(root, func(node Node, ctx *TraverseContext) bool {
 		if node == target {
@@ -301,8 +280,13 @@ func GetParent(root, target Node) Node {
 	return parent
 }
 
-// GetAncestors returns all ancestor nodes of the target node.
+// GetAncestors returns all ancestor nodes of the target node. If the tree has
+// been linked with SetParents, the links are used instead of searching from root.
 func GetAncestors(root, target Node) []Node {
+	if ancestors := Ancestors(target); ancestors != nil {
+		return ancestors
+	}
+
 	var ancestors []Node
 	TraverseWithContext(root, func(node Node, ctx *TraverseContext) bool {
 		if node == target {
@@ -347,35 +331,35 @@ func findTargetContext(root, target Node) *TraverseContext {
 
 func extractSiblings(parent Node, key string, targetIndex *int) []Node {
 	var siblings []Node
-	parentValue := reflect.ValueOf(parent)
-	if parentValue.Kind() == reflect.Ptr {
-		parentValue = parentValue.Elem()
-	}
-
-	field := parentValue.FieldByName(capitalizeFirst(key))
-	if !field.IsValid() || field.Kind() != reflect.Slice {
-		return siblings
-	}
-
-	for i := 0; i < field.Len(); i++ {
-		if targetIndex != nil && i == *targetIndex {
-			continue // Skip the target itself
+	eachChild(parent, func(childKey string, index int, child Node) {
+		if childKey != key || index < 0 {
+			return
 		}
-		elem := field.Index(i)
-		if node := extractNodeFromElement(elem); node != nil {
-			siblings = append(siblings, node)
+		if targetIndex != nil && index == *targetIndex {
+			return // Skip the target itself
 		}
-	}
-
+		siblings = append(siblings, child)
+	})
 	return siblings
 }
 
+// extractNodeFromElement returns the node held by a slice element. Elements
+// stored by value, such as VariableDeclarator, are addressed in place so the
+// returned node aliases the slice entry.
+//
 //nolint:ireturn // Interface types are intentional for generic node extraction
 func extractNodeFromElement(elem reflect.Value) Node {
-	if elem.Kind() == reflect.Ptr && !elem.IsNil() {
+	if elem.Kind() == reflect.Ptr {
+		if elem.IsNil() {
+			return nil
+		}
 		if node, ok := elem.Interface().(Node); ok {
 			return node
 		}
+	} else if elem.Kind() == reflect.Struct && elem.CanAddr() {
+		if node, ok := elem.Addr().Interface().(Node); ok {
+			return node
+		}
 	} else if node, ok := elem.Interface().(Node); ok {
 		return node
 	}
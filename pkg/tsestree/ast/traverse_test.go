@@ -37,6 +37,41 @@ func TestWalk(t *testing.T) {
 	}
 }
 
+func TestWalkTypeAnnotations(t *testing.T) {
+	annotation := func() *TSTypeAnnotation {
+		return &TSTypeAnnotation{
+			BaseNode:       BaseNode{NodeType: "TSTypeAnnotation"},
+			TypeAnnotation: &TSNumberKeyword{BaseNode: BaseNode{NodeType: "TSNumberKeyword"}},
+		}
+	}
+	ident := func(name string) *Identifier {
+		return &Identifier{BaseNode: BaseNode{NodeType: "Identifier"}, Name: name}
+	}
+
+	// function f(a: number, [b]: number, {}: number, c: number = d, ...e: number) {}
+	a := ident("a")
+	a.TypeAnnotation = annotation()
+	c := ident("c")
+	c.TypeAnnotation = annotation()
+	fn := &FunctionDeclaration{
+		BaseNode: BaseNode{NodeType: "FunctionDeclaration"},
+		Params: []Pattern{
+			a,
+			&ArrayPattern{BaseNode: BaseNode{NodeType: "ArrayPattern"}, Elements: []Pattern{ident("b")}, TypeAnnotation: annotation()},
+			&ObjectPattern{BaseNode: BaseNode{NodeType: "ObjectPattern"}, TypeAnnotation: annotation()},
+			&AssignmentPattern{BaseNode: BaseNode{NodeType: "AssignmentPattern"}, Left: c, Right: ident("d")},
+			&RestElement{BaseNode: BaseNode{NodeType: "RestElement"}, Argument: ident("e"), TypeAnnotation: annotation()},
+		},
+	}
+
+	if got := len(FindByType(fn, "TSTypeAnnotation")); got != 5 {
+		t.Errorf("expected 5 type annotations to be visited, got %d", got)
+	}
+	if got := len(FindByType(fn, "TSNumberKeyword")); got != 5 {
+		t.Errorf("expected 5 annotated types to be visited, got %d", got)
+	}
+}
+
 func TestWalkSkipChildren(t *testing.T) {
 	// Create a simple AST
 	left := &Identifier{
@@ -174,6 +209,37 @@ func TestGetAncestors(t *testing.T) {
 	}
 }
 
+func TestSetParents(t *testing.T) {
+	id := &Identifier{BaseNode: BaseNode{NodeType: "Identifier"}, Name: "a"}
+	unary := &UnaryExpression{
+		BaseNode: BaseNode{NodeType: "UnaryExpression"},
+		Operator: "-",
+		Argument: id,
+	}
+	binary := &BinaryExpression{
+		BaseNode: BaseNode{NodeType: "BinaryExpression"},
+		Left:     unary,
+		Right:    &Identifier{BaseNode: BaseNode{NodeType: "Identifier"}, Name: "b"},
+	}
+
+	if id.Parent() != nil || Ancestors(id) != nil {
+		t.Fatal("expected no parent links before SetParents")
+	}
+
+	SetParents(binary)
+
+	if id.Parent() != unary || Parent(unary) != binary || binary.Parent() != nil {
+		t.Error("expected parent links from a to -a to the binary expression")
+	}
+	ancestors := id.Ancestors()
+	if len(ancestors) != 2 || ancestors[0] != binary || ancestors[1] != unary {
+		t.Errorf("expected ancestors [binary unary], got %v", ancestors)
+	}
+	if got := GetAncestors(nil, id); len(got) != 2 {
+		t.Errorf("expected GetAncestors to follow parent links, got %v", got)
+	}
+}
+
 func TestContains(t *testing.T) {
 	left := &Identifier{BaseNode: BaseNode{NodeType: "Identifier"}, Name: "a"}
 	right := &Identifier{BaseNode: BaseNode{NodeType: "Identifier"}, Name: "b"}
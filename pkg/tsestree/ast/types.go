@@ -156,7 +156,8 @@ type ClassExpression struct {
 	Body                *ClassBody                    `json:"body"`
 	Decorators          []Decorator                   `json:"decorators,omitempty"`
 	TypeParameters      *TSTypeParameterDeclaration   `json:"typeParameters,omitempty"`
-	SuperTypeParameters *TSTypeParameterInstantiation `json:"superTypeParameters,omitempty"`
+	SuperTypeArguments  *TSTypeParameterInstantiation `json:"superTypeArguments,omitempty"`
+	SuperTypeParameters *TSTypeParameterInstantiation `json:"superTypeParameters,omitempty"` // Deprecated, use SuperTypeArguments
 	Implements          []TSClassImplements           `json:"implements,omitempty"`
 	Abstract            bool                          `json:"abstract,omitempty"`
 	Declare             bool                          `json:"declare,omitempty"`
@@ -575,7 +576,8 @@ type ClassDeclaration struct {
 	Body                *ClassBody                    `json:"body"`
 	Decorators          []Decorator                   `json:"decorators,omitempty"`
 	TypeParameters      *TSTypeParameterDeclaration   `json:"typeParameters,omitempty"`
-	SuperTypeParameters *TSTypeParameterInstantiation `json:"superTypeParameters,omitempty"`
+	SuperTypeArguments  *TSTypeParameterInstantiation `json:"superTypeArguments,omitempty"`
+	SuperTypeParameters *TSTypeParameterInstantiation `json:"superTypeParameters,omitempty"` // Deprecated, use SuperTypeArguments
 	Implements          []TSClassImplements           `json:"implements,omitempty"`
 	Abstract            bool                          `json:"abstract,omitempty"`
 	Declare             bool                          `json:"declare,omitempty"`
@@ -501,6 +501,9 @@ type TSNamedTupleMember struct {
 	Optional    bool        `json:"optional,omitempty"`
 }
 
+// TSTypeNode marks this as a TypeScript type node.
+func (n *TSNamedTupleMember) TSTypeNode() {}
+
 // ==================== TypeScript Type Assertions & Expressions ====================
 
 // TSAsExpression represents a type assertion using 'as' (x as T).
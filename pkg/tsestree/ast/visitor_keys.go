@@ -12,7 +12,7 @@ var VisitorKeys = map[string][]string{
 
 	// ==================== Identifiers & Literals ====================
 
-	"Identifier":        {}, // No child nodes
+	"Identifier":        {"decorators", "typeAnnotation"},
 	"PrivateIdentifier": {}, // No child nodes
 	"Literal":           {}, // No child nodes
 
@@ -107,10 +107,10 @@ var VisitorKeys = map[string][]string{
 
 	// ==================== Patterns ====================
 
-	"ArrayPattern":      {"elements"},
-	"ObjectPattern":     {"properties"},
-	"RestElement":       {"argument"},
-	"AssignmentPattern": {"left", "right"},
+	"ArrayPattern":      {"decorators", "elements", "typeAnnotation"},
+	"ObjectPattern":     {"decorators", "properties", "typeAnnotation"},
+	"RestElement":       {"decorators", "argument", "typeAnnotation"},
+	"AssignmentPattern": {"decorators", "left", "right", "typeAnnotation"},
 
 	// ==================== Other ====================
 
@@ -16,9 +16,9 @@ func TestGetVisitorKeys(t *testing.T) {
 			expected: []string{"body"},
 		},
 		{
-			name:     "Identifier has no children",
+			name:     "Identifier has decorators and a type annotation",
 			nodeType: "Identifier",
-			expected: []string{},
+			expected: []string{"decorators", "typeAnnotation"},
 		},
 		{
 			name:     "BinaryExpression",
@@ -0,0 +1,1569 @@
+// Code generated by walkgen from visitor_keys.go and the node definitions; DO NOT EDIT.
+
+package ast
+
+// forEachChild calls fn for each child of node, in the order of its
+// visitor keys, with the key of the property holding the child and its index
+// if the property is a list, or -1. It reports false, without calling fn, if
+// node is not a type it was generated for or its NodeType is not the name of
+// its Go type; the caller then falls back to reflection.
+func forEachChild(node Node, fn func(key string, index int, child Node)) bool {
+	switch n := node.(type) {
+	case *AccessorProperty:
+		if n.NodeType != "AccessorProperty" {
+			return false
+		}
+		for i := range n.Decorators {
+			fn("decorators", i, &n.Decorators[i])
+		}
+		if n.Key != nil {
+			fn("key", -1, n.Key)
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+		if n.Value != nil {
+			fn("value", -1, n.Value)
+		}
+	case *ArrayExpression:
+		if n.NodeType != "ArrayExpression" {
+			return false
+		}
+		for i, c := range n.Elements {
+			if c != nil {
+				fn("elements", i, c)
+			}
+		}
+	case *ArrayPattern:
+		if n.NodeType != "ArrayPattern" {
+			return false
+		}
+		for i := range n.Decorators {
+			fn("decorators", i, &n.Decorators[i])
+		}
+		for i, c := range n.Elements {
+			if c != nil {
+				fn("elements", i, c)
+			}
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+	case *ArrowFunctionExpression:
+		if n.NodeType != "ArrowFunctionExpression" {
+			return false
+		}
+		if n.TypeParameters != nil {
+			fn("typeParameters", -1, n.TypeParameters)
+		}
+		for i, c := range n.Params {
+			if c != nil {
+				fn("params", i, c)
+			}
+		}
+		if n.ReturnType != nil {
+			fn("returnType", -1, n.ReturnType)
+		}
+		if c, ok := n.Body.(Node); ok {
+			fn("body", -1, c)
+		}
+	case *AssignmentExpression:
+		if n.NodeType != "AssignmentExpression" {
+			return false
+		}
+		if n.Left != nil {
+			fn("left", -1, n.Left)
+		}
+		if n.Right != nil {
+			fn("right", -1, n.Right)
+		}
+	case *AssignmentPattern:
+		if n.NodeType != "AssignmentPattern" {
+			return false
+		}
+		for i := range n.Decorators {
+			fn("decorators", i, &n.Decorators[i])
+		}
+		if n.Left != nil {
+			fn("left", -1, n.Left)
+		}
+		if n.Right != nil {
+			fn("right", -1, n.Right)
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+	case *AwaitExpression:
+		if n.NodeType != "AwaitExpression" {
+			return false
+		}
+		if n.Argument != nil {
+			fn("argument", -1, n.Argument)
+		}
+	case *BinaryExpression:
+		if n.NodeType != "BinaryExpression" {
+			return false
+		}
+		if n.Left != nil {
+			fn("left", -1, n.Left)
+		}
+		if n.Right != nil {
+			fn("right", -1, n.Right)
+		}
+	case *BlockStatement:
+		if n.NodeType != "BlockStatement" {
+			return false
+		}
+		for i, c := range n.Body {
+			if c != nil {
+				fn("body", i, c)
+			}
+		}
+	case *BreakStatement:
+		if n.NodeType != "BreakStatement" {
+			return false
+		}
+		if n.Label != nil {
+			fn("label", -1, n.Label)
+		}
+	case *CallExpression:
+		if n.NodeType != "CallExpression" {
+			return false
+		}
+		if n.Callee != nil {
+			fn("callee", -1, n.Callee)
+		}
+		if n.TypeArguments != nil {
+			fn("typeArguments", -1, n.TypeArguments)
+		}
+		for i, c := range n.Arguments {
+			if c != nil {
+				fn("arguments", i, c)
+			}
+		}
+	case *CatchClause:
+		if n.NodeType != "CatchClause" {
+			return false
+		}
+		if n.Param != nil {
+			fn("param", -1, n.Param)
+		}
+		if n.Body != nil {
+			fn("body", -1, n.Body)
+		}
+	case *ChainExpression:
+		if n.NodeType != "ChainExpression" {
+			return false
+		}
+		if n.Expression != nil {
+			fn("expression", -1, n.Expression)
+		}
+	case *ClassBody:
+		if n.NodeType != "ClassBody" {
+			return false
+		}
+		for i, e := range n.Body {
+			if c, ok := e.(Node); ok {
+				fn("body", i, c)
+			}
+		}
+	case *ClassDeclaration:
+		if n.NodeType != "ClassDeclaration" {
+			return false
+		}
+		for i := range n.Decorators {
+			fn("decorators", i, &n.Decorators[i])
+		}
+		if n.ID != nil {
+			fn("id", -1, n.ID)
+		}
+		if n.TypeParameters != nil {
+			fn("typeParameters", -1, n.TypeParameters)
+		}
+		if n.SuperClass != nil {
+			fn("superClass", -1, n.SuperClass)
+		}
+		if n.SuperTypeArguments != nil {
+			fn("superTypeArguments", -1, n.SuperTypeArguments)
+		}
+		for i := range n.Implements {
+			fn("implements", i, &n.Implements[i])
+		}
+		if n.Body != nil {
+			fn("body", -1, n.Body)
+		}
+	case *ClassExpression:
+		if n.NodeType != "ClassExpression" {
+			return false
+		}
+		for i := range n.Decorators {
+			fn("decorators", i, &n.Decorators[i])
+		}
+		if n.ID != nil {
+			fn("id", -1, n.ID)
+		}
+		if n.TypeParameters != nil {
+			fn("typeParameters", -1, n.TypeParameters)
+		}
+		if n.SuperClass != nil {
+			fn("superClass", -1, n.SuperClass)
+		}
+		if n.SuperTypeArguments != nil {
+			fn("superTypeArguments", -1, n.SuperTypeArguments)
+		}
+		for i := range n.Implements {
+			fn("implements", i, &n.Implements[i])
+		}
+		if n.Body != nil {
+			fn("body", -1, n.Body)
+		}
+	case *ConditionalExpression:
+		if n.NodeType != "ConditionalExpression" {
+			return false
+		}
+		if n.Test != nil {
+			fn("test", -1, n.Test)
+		}
+		if n.Consequent != nil {
+			fn("consequent", -1, n.Consequent)
+		}
+		if n.Alternate != nil {
+			fn("alternate", -1, n.Alternate)
+		}
+	case *ContinueStatement:
+		if n.NodeType != "ContinueStatement" {
+			return false
+		}
+		if n.Label != nil {
+			fn("label", -1, n.Label)
+		}
+	case *DebuggerStatement:
+		if n.NodeType != "DebuggerStatement" {
+			return false
+		}
+	case *Decorator:
+		if n.NodeType != "Decorator" {
+			return false
+		}
+		if n.Expression != nil {
+			fn("expression", -1, n.Expression)
+		}
+	case *DoWhileStatement:
+		if n.NodeType != "DoWhileStatement" {
+			return false
+		}
+		if n.Body != nil {
+			fn("body", -1, n.Body)
+		}
+		if n.Test != nil {
+			fn("test", -1, n.Test)
+		}
+	case *EmptyStatement:
+		if n.NodeType != "EmptyStatement" {
+			return false
+		}
+	case *ExportAllDeclaration:
+		if n.NodeType != "ExportAllDeclaration" {
+			return false
+		}
+		if n.Exported != nil {
+			fn("exported", -1, n.Exported)
+		}
+		if n.Source != nil {
+			fn("source", -1, n.Source)
+		}
+	case *ExportDefaultDeclaration:
+		if n.NodeType != "ExportDefaultDeclaration" {
+			return false
+		}
+		if c, ok := n.Declaration.(Node); ok {
+			fn("declaration", -1, c)
+		}
+	case *ExportNamedDeclaration:
+		if n.NodeType != "ExportNamedDeclaration" {
+			return false
+		}
+		if n.Declaration != nil {
+			fn("declaration", -1, n.Declaration)
+		}
+		for i := range n.Specifiers {
+			fn("specifiers", i, &n.Specifiers[i])
+		}
+		if n.Source != nil {
+			fn("source", -1, n.Source)
+		}
+	case *ExportSpecifier:
+		if n.NodeType != "ExportSpecifier" {
+			return false
+		}
+		if c, ok := n.Exported.(Node); ok {
+			fn("exported", -1, c)
+		}
+		if c, ok := n.Local.(Node); ok {
+			fn("local", -1, c)
+		}
+	case *ExpressionStatement:
+		if n.NodeType != "ExpressionStatement" {
+			return false
+		}
+		if n.Expression != nil {
+			fn("expression", -1, n.Expression)
+		}
+	case *ForInStatement:
+		if n.NodeType != "ForInStatement" {
+			return false
+		}
+		if c, ok := n.Left.(Node); ok {
+			fn("left", -1, c)
+		}
+		if n.Right != nil {
+			fn("right", -1, n.Right)
+		}
+		if n.Body != nil {
+			fn("body", -1, n.Body)
+		}
+	case *ForOfStatement:
+		if n.NodeType != "ForOfStatement" {
+			return false
+		}
+		if c, ok := n.Left.(Node); ok {
+			fn("left", -1, c)
+		}
+		if n.Right != nil {
+			fn("right", -1, n.Right)
+		}
+		if n.Body != nil {
+			fn("body", -1, n.Body)
+		}
+	case *ForStatement:
+		if n.NodeType != "ForStatement" {
+			return false
+		}
+		if c, ok := n.Init.(Node); ok {
+			fn("init", -1, c)
+		}
+		if n.Test != nil {
+			fn("test", -1, n.Test)
+		}
+		if n.Update != nil {
+			fn("update", -1, n.Update)
+		}
+		if n.Body != nil {
+			fn("body", -1, n.Body)
+		}
+	case *FunctionDeclaration:
+		if n.NodeType != "FunctionDeclaration" {
+			return false
+		}
+		if n.ID != nil {
+			fn("id", -1, n.ID)
+		}
+		if n.TypeParameters != nil {
+			fn("typeParameters", -1, n.TypeParameters)
+		}
+		for i, c := range n.Params {
+			if c != nil {
+				fn("params", i, c)
+			}
+		}
+		if n.ReturnType != nil {
+			fn("returnType", -1, n.ReturnType)
+		}
+		if n.Body != nil {
+			fn("body", -1, n.Body)
+		}
+	case *FunctionExpression:
+		if n.NodeType != "FunctionExpression" {
+			return false
+		}
+		if n.ID != nil {
+			fn("id", -1, n.ID)
+		}
+		if n.TypeParameters != nil {
+			fn("typeParameters", -1, n.TypeParameters)
+		}
+		for i, c := range n.Params {
+			if c != nil {
+				fn("params", i, c)
+			}
+		}
+		if n.ReturnType != nil {
+			fn("returnType", -1, n.ReturnType)
+		}
+		if n.Body != nil {
+			fn("body", -1, n.Body)
+		}
+	case *Identifier:
+		if n.NodeType != "Identifier" {
+			return false
+		}
+		for i := range n.Decorators {
+			fn("decorators", i, &n.Decorators[i])
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+	case *IfStatement:
+		if n.NodeType != "IfStatement" {
+			return false
+		}
+		if n.Test != nil {
+			fn("test", -1, n.Test)
+		}
+		if n.Consequent != nil {
+			fn("consequent", -1, n.Consequent)
+		}
+		if n.Alternate != nil {
+			fn("alternate", -1, n.Alternate)
+		}
+	case *ImportAttribute:
+		if n.NodeType != "ImportAttribute" {
+			return false
+		}
+		if c, ok := n.Key.(Node); ok {
+			fn("key", -1, c)
+		}
+		if n.Value != nil {
+			fn("value", -1, n.Value)
+		}
+	case *ImportDeclaration:
+		if n.NodeType != "ImportDeclaration" {
+			return false
+		}
+		for i, e := range n.Specifiers {
+			if c, ok := e.(Node); ok {
+				fn("specifiers", i, c)
+			}
+		}
+		if n.Source != nil {
+			fn("source", -1, n.Source)
+		}
+		for i := range n.Attributes {
+			fn("attributes", i, &n.Attributes[i])
+		}
+	case *ImportDefaultSpecifier:
+		if n.NodeType != "ImportDefaultSpecifier" {
+			return false
+		}
+		if n.Local != nil {
+			fn("local", -1, n.Local)
+		}
+	case *ImportExpression:
+		if n.NodeType != "ImportExpression" {
+			return false
+		}
+		if n.Source != nil {
+			fn("source", -1, n.Source)
+		}
+	case *ImportNamespaceSpecifier:
+		if n.NodeType != "ImportNamespaceSpecifier" {
+			return false
+		}
+		if n.Local != nil {
+			fn("local", -1, n.Local)
+		}
+	case *ImportSpecifier:
+		if n.NodeType != "ImportSpecifier" {
+			return false
+		}
+		if n.Imported != nil {
+			fn("imported", -1, n.Imported)
+		}
+		if n.Local != nil {
+			fn("local", -1, n.Local)
+		}
+	case *JSXAttribute:
+		if n.NodeType != "JSXAttribute" {
+			return false
+		}
+		if c, ok := n.Name.(Node); ok {
+			fn("name", -1, c)
+		}
+		if c, ok := n.Value.(Node); ok {
+			fn("value", -1, c)
+		}
+	case *JSXClosingElement:
+		if n.NodeType != "JSXClosingElement" {
+			return false
+		}
+		if c, ok := n.Name.(Node); ok {
+			fn("name", -1, c)
+		}
+	case *JSXClosingFragment:
+		if n.NodeType != "JSXClosingFragment" {
+			return false
+		}
+	case *JSXElement:
+		if n.NodeType != "JSXElement" {
+			return false
+		}
+		if n.OpeningElement != nil {
+			fn("openingElement", -1, n.OpeningElement)
+		}
+		for i, e := range n.Children {
+			if c, ok := e.(Node); ok {
+				fn("children", i, c)
+			}
+		}
+		if n.ClosingElement != nil {
+			fn("closingElement", -1, n.ClosingElement)
+		}
+	case *JSXEmptyExpression:
+		if n.NodeType != "JSXEmptyExpression" {
+			return false
+		}
+	case *JSXExpressionContainer:
+		if n.NodeType != "JSXExpressionContainer" {
+			return false
+		}
+		if c, ok := n.Expression.(Node); ok {
+			fn("expression", -1, c)
+		}
+	case *JSXFragment:
+		if n.NodeType != "JSXFragment" {
+			return false
+		}
+		if n.OpeningFragment != nil {
+			fn("openingFragment", -1, n.OpeningFragment)
+		}
+		for i, e := range n.Children {
+			if c, ok := e.(Node); ok {
+				fn("children", i, c)
+			}
+		}
+		if n.ClosingFragment != nil {
+			fn("closingFragment", -1, n.ClosingFragment)
+		}
+	case *JSXIdentifier:
+		if n.NodeType != "JSXIdentifier" {
+			return false
+		}
+	case *JSXMemberExpression:
+		if n.NodeType != "JSXMemberExpression" {
+			return false
+		}
+		if c, ok := n.Object.(Node); ok {
+			fn("object", -1, c)
+		}
+		if n.Property != nil {
+			fn("property", -1, n.Property)
+		}
+	case *JSXNamespacedName:
+		if n.NodeType != "JSXNamespacedName" {
+			return false
+		}
+		if n.Namespace != nil {
+			fn("namespace", -1, n.Namespace)
+		}
+		if n.Name != nil {
+			fn("name", -1, n.Name)
+		}
+	case *JSXOpeningElement:
+		if n.NodeType != "JSXOpeningElement" {
+			return false
+		}
+		if c, ok := n.Name.(Node); ok {
+			fn("name", -1, c)
+		}
+		if n.TypeArguments != nil {
+			fn("typeArguments", -1, n.TypeArguments)
+		}
+		for i, e := range n.Attributes {
+			if c, ok := e.(Node); ok {
+				fn("attributes", i, c)
+			}
+		}
+	case *JSXOpeningFragment:
+		if n.NodeType != "JSXOpeningFragment" {
+			return false
+		}
+	case *JSXSpreadAttribute:
+		if n.NodeType != "JSXSpreadAttribute" {
+			return false
+		}
+		if n.Argument != nil {
+			fn("argument", -1, n.Argument)
+		}
+	case *JSXSpreadChild:
+		if n.NodeType != "JSXSpreadChild" {
+			return false
+		}
+		if n.Expression != nil {
+			fn("expression", -1, n.Expression)
+		}
+	case *JSXText:
+		if n.NodeType != "JSXText" {
+			return false
+		}
+	case *LabeledStatement:
+		if n.NodeType != "LabeledStatement" {
+			return false
+		}
+		if n.Label != nil {
+			fn("label", -1, n.Label)
+		}
+		if n.Body != nil {
+			fn("body", -1, n.Body)
+		}
+	case *Literal:
+		if n.NodeType != "Literal" {
+			return false
+		}
+	case *LogicalExpression:
+		if n.NodeType != "LogicalExpression" {
+			return false
+		}
+		if n.Left != nil {
+			fn("left", -1, n.Left)
+		}
+		if n.Right != nil {
+			fn("right", -1, n.Right)
+		}
+	case *MemberExpression:
+		if n.NodeType != "MemberExpression" {
+			return false
+		}
+		if n.Object != nil {
+			fn("object", -1, n.Object)
+		}
+		if n.Property != nil {
+			fn("property", -1, n.Property)
+		}
+	case *MetaProperty:
+		if n.NodeType != "MetaProperty" {
+			return false
+		}
+		if n.Meta != nil {
+			fn("meta", -1, n.Meta)
+		}
+		if n.Property != nil {
+			fn("property", -1, n.Property)
+		}
+	case *MethodDefinition:
+		if n.NodeType != "MethodDefinition" {
+			return false
+		}
+		for i := range n.Decorators {
+			fn("decorators", i, &n.Decorators[i])
+		}
+		if n.Key != nil {
+			fn("key", -1, n.Key)
+		}
+		if n.Value != nil {
+			fn("value", -1, n.Value)
+		}
+	case *NewExpression:
+		if n.NodeType != "NewExpression" {
+			return false
+		}
+		if n.Callee != nil {
+			fn("callee", -1, n.Callee)
+		}
+		if n.TypeArguments != nil {
+			fn("typeArguments", -1, n.TypeArguments)
+		}
+		for i, c := range n.Arguments {
+			if c != nil {
+				fn("arguments", i, c)
+			}
+		}
+	case *ObjectExpression:
+		if n.NodeType != "ObjectExpression" {
+			return false
+		}
+		for i, e := range n.Properties {
+			if c, ok := e.(Node); ok {
+				fn("properties", i, c)
+			}
+		}
+	case *ObjectPattern:
+		if n.NodeType != "ObjectPattern" {
+			return false
+		}
+		for i := range n.Decorators {
+			fn("decorators", i, &n.Decorators[i])
+		}
+		for i, e := range n.Properties {
+			if c, ok := e.(Node); ok {
+				fn("properties", i, c)
+			}
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+	case *PrivateIdentifier:
+		if n.NodeType != "PrivateIdentifier" {
+			return false
+		}
+	case *Program:
+		if n.NodeType != "Program" {
+			return false
+		}
+		for i, c := range n.Body {
+			if c != nil {
+				fn("body", i, c)
+			}
+		}
+	case *Property:
+		if n.NodeType != "Property" {
+			return false
+		}
+		if n.Key != nil {
+			fn("key", -1, n.Key)
+		}
+		if n.Value != nil {
+			fn("value", -1, n.Value)
+		}
+	case *PropertyDefinition:
+		if n.NodeType != "PropertyDefinition" {
+			return false
+		}
+		for i := range n.Decorators {
+			fn("decorators", i, &n.Decorators[i])
+		}
+		if n.Key != nil {
+			fn("key", -1, n.Key)
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+		if n.Value != nil {
+			fn("value", -1, n.Value)
+		}
+	case *RestElement:
+		if n.NodeType != "RestElement" {
+			return false
+		}
+		for i := range n.Decorators {
+			fn("decorators", i, &n.Decorators[i])
+		}
+		if n.Argument != nil {
+			fn("argument", -1, n.Argument)
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+	case *ReturnStatement:
+		if n.NodeType != "ReturnStatement" {
+			return false
+		}
+		if n.Argument != nil {
+			fn("argument", -1, n.Argument)
+		}
+	case *SequenceExpression:
+		if n.NodeType != "SequenceExpression" {
+			return false
+		}
+		for i, c := range n.Expressions {
+			if c != nil {
+				fn("expressions", i, c)
+			}
+		}
+	case *SpreadElement:
+		if n.NodeType != "SpreadElement" {
+			return false
+		}
+		if n.Argument != nil {
+			fn("argument", -1, n.Argument)
+		}
+	case *StaticBlock:
+		if n.NodeType != "StaticBlock" {
+			return false
+		}
+		for i, c := range n.Body {
+			if c != nil {
+				fn("body", i, c)
+			}
+		}
+	case *Super:
+		if n.NodeType != "Super" {
+			return false
+		}
+	case *SwitchCase:
+		if n.NodeType != "SwitchCase" {
+			return false
+		}
+		if n.Test != nil {
+			fn("test", -1, n.Test)
+		}
+		for i, c := range n.Consequent {
+			if c != nil {
+				fn("consequent", i, c)
+			}
+		}
+	case *SwitchStatement:
+		if n.NodeType != "SwitchStatement" {
+			return false
+		}
+		if n.Discriminant != nil {
+			fn("discriminant", -1, n.Discriminant)
+		}
+		for i := range n.Cases {
+			fn("cases", i, &n.Cases[i])
+		}
+	case *TSAbstractAccessorProperty:
+		if n.NodeType != "TSAbstractAccessorProperty" {
+			return false
+		}
+		for i := range n.Decorators {
+			fn("decorators", i, &n.Decorators[i])
+		}
+		if n.Key != nil {
+			fn("key", -1, n.Key)
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+		if n.Value != nil {
+			fn("value", -1, n.Value)
+		}
+	case *TSAbstractMethodDefinition:
+		if n.NodeType != "TSAbstractMethodDefinition" {
+			return false
+		}
+		for i := range n.Decorators {
+			fn("decorators", i, &n.Decorators[i])
+		}
+		if n.Key != nil {
+			fn("key", -1, n.Key)
+		}
+		if n.Value != nil {
+			fn("value", -1, n.Value)
+		}
+	case *TSAbstractPropertyDefinition:
+		if n.NodeType != "TSAbstractPropertyDefinition" {
+			return false
+		}
+		for i := range n.Decorators {
+			fn("decorators", i, &n.Decorators[i])
+		}
+		if n.Key != nil {
+			fn("key", -1, n.Key)
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+		if n.Value != nil {
+			fn("value", -1, n.Value)
+		}
+	case *TSAnyKeyword:
+		if n.NodeType != "TSAnyKeyword" {
+			return false
+		}
+	case *TSArrayType:
+		if n.NodeType != "TSArrayType" {
+			return false
+		}
+		if n.ElementType != nil {
+			fn("elementType", -1, n.ElementType)
+		}
+	case *TSAsExpression:
+		if n.NodeType != "TSAsExpression" {
+			return false
+		}
+		if n.Expression != nil {
+			fn("expression", -1, n.Expression)
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+	case *TSBigIntKeyword:
+		if n.NodeType != "TSBigIntKeyword" {
+			return false
+		}
+	case *TSBooleanKeyword:
+		if n.NodeType != "TSBooleanKeyword" {
+			return false
+		}
+	case *TSCallSignatureDeclaration:
+		if n.NodeType != "TSCallSignatureDeclaration" {
+			return false
+		}
+		if n.TypeParameters != nil {
+			fn("typeParameters", -1, n.TypeParameters)
+		}
+		for i, c := range n.Params {
+			if c != nil {
+				fn("params", i, c)
+			}
+		}
+		if n.ReturnType != nil {
+			fn("returnType", -1, n.ReturnType)
+		}
+	case *TSClassImplements:
+		if n.NodeType != "TSClassImplements" {
+			return false
+		}
+		if n.Expression != nil {
+			fn("expression", -1, n.Expression)
+		}
+		if n.TypeArguments != nil {
+			fn("typeArguments", -1, n.TypeArguments)
+		}
+	case *TSConditionalType:
+		if n.NodeType != "TSConditionalType" {
+			return false
+		}
+		if n.CheckType != nil {
+			fn("checkType", -1, n.CheckType)
+		}
+		if n.ExtendsType != nil {
+			fn("extendsType", -1, n.ExtendsType)
+		}
+		if n.TrueType != nil {
+			fn("trueType", -1, n.TrueType)
+		}
+		if n.FalseType != nil {
+			fn("falseType", -1, n.FalseType)
+		}
+	case *TSConstructSignatureDeclaration:
+		if n.NodeType != "TSConstructSignatureDeclaration" {
+			return false
+		}
+		if n.TypeParameters != nil {
+			fn("typeParameters", -1, n.TypeParameters)
+		}
+		for i, c := range n.Params {
+			if c != nil {
+				fn("params", i, c)
+			}
+		}
+		if n.ReturnType != nil {
+			fn("returnType", -1, n.ReturnType)
+		}
+	case *TSConstructorType:
+		if n.NodeType != "TSConstructorType" {
+			return false
+		}
+		if n.TypeParameters != nil {
+			fn("typeParameters", -1, n.TypeParameters)
+		}
+		for i, c := range n.Params {
+			if c != nil {
+				fn("params", i, c)
+			}
+		}
+		if n.ReturnType != nil {
+			fn("returnType", -1, n.ReturnType)
+		}
+	case *TSDeclareFunction:
+		if n.NodeType != "TSDeclareFunction" {
+			return false
+		}
+		if n.ID != nil {
+			fn("id", -1, n.ID)
+		}
+		if n.TypeParameters != nil {
+			fn("typeParameters", -1, n.TypeParameters)
+		}
+		for i, c := range n.Params {
+			if c != nil {
+				fn("params", i, c)
+			}
+		}
+		if n.ReturnType != nil {
+			fn("returnType", -1, n.ReturnType)
+		}
+	case *TSEmptyBodyFunctionExpression:
+		if n.NodeType != "TSEmptyBodyFunctionExpression" {
+			return false
+		}
+		if n.ID != nil {
+			fn("id", -1, n.ID)
+		}
+		if n.TypeParameters != nil {
+			fn("typeParameters", -1, n.TypeParameters)
+		}
+		for i, c := range n.Params {
+			if c != nil {
+				fn("params", i, c)
+			}
+		}
+		if n.ReturnType != nil {
+			fn("returnType", -1, n.ReturnType)
+		}
+	case *TSEnumDeclaration:
+		if n.NodeType != "TSEnumDeclaration" {
+			return false
+		}
+		if n.ID != nil {
+			fn("id", -1, n.ID)
+		}
+		for i := range n.Members {
+			fn("members", i, &n.Members[i])
+		}
+	case *TSEnumMember:
+		if n.NodeType != "TSEnumMember" {
+			return false
+		}
+		if c, ok := n.ID.(Node); ok {
+			fn("id", -1, c)
+		}
+		if n.Initializer != nil {
+			fn("initializer", -1, n.Initializer)
+		}
+	case *TSExportAssignment:
+		if n.NodeType != "TSExportAssignment" {
+			return false
+		}
+		if n.Expression != nil {
+			fn("expression", -1, n.Expression)
+		}
+	case *TSExternalModuleReference:
+		if n.NodeType != "TSExternalModuleReference" {
+			return false
+		}
+		if n.Expression != nil {
+			fn("expression", -1, n.Expression)
+		}
+	case *TSFunctionType:
+		if n.NodeType != "TSFunctionType" {
+			return false
+		}
+		if n.TypeParameters != nil {
+			fn("typeParameters", -1, n.TypeParameters)
+		}
+		for i, c := range n.Params {
+			if c != nil {
+				fn("params", i, c)
+			}
+		}
+		if n.ReturnType != nil {
+			fn("returnType", -1, n.ReturnType)
+		}
+	case *TSImportEqualsDeclaration:
+		if n.NodeType != "TSImportEqualsDeclaration" {
+			return false
+		}
+		if n.ID != nil {
+			fn("id", -1, n.ID)
+		}
+		if c, ok := n.ModuleReference.(Node); ok {
+			fn("moduleReference", -1, c)
+		}
+	case *TSImportType:
+		if n.NodeType != "TSImportType" {
+			return false
+		}
+		if n.Argument != nil {
+			fn("argument", -1, n.Argument)
+		}
+		if c, ok := n.Qualifier.(Node); ok {
+			fn("qualifier", -1, c)
+		}
+		if n.TypeArguments != nil {
+			fn("typeArguments", -1, n.TypeArguments)
+		}
+	case *TSIndexSignature:
+		if n.NodeType != "TSIndexSignature" {
+			return false
+		}
+		for i, c := range n.Parameters {
+			if c != nil {
+				fn("parameters", i, c)
+			}
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+	case *TSIndexedAccessType:
+		if n.NodeType != "TSIndexedAccessType" {
+			return false
+		}
+		if n.ObjectType != nil {
+			fn("objectType", -1, n.ObjectType)
+		}
+		if n.IndexType != nil {
+			fn("indexType", -1, n.IndexType)
+		}
+	case *TSInferType:
+		if n.NodeType != "TSInferType" {
+			return false
+		}
+		if n.TypeParameter != nil {
+			fn("typeParameter", -1, n.TypeParameter)
+		}
+	case *TSInstantiationExpression:
+		if n.NodeType != "TSInstantiationExpression" {
+			return false
+		}
+		if n.Expression != nil {
+			fn("expression", -1, n.Expression)
+		}
+		if n.TypeArguments != nil {
+			fn("typeArguments", -1, n.TypeArguments)
+		}
+	case *TSInterfaceBody:
+		if n.NodeType != "TSInterfaceBody" {
+			return false
+		}
+		for i, e := range n.Body {
+			if c, ok := e.(Node); ok {
+				fn("body", i, c)
+			}
+		}
+	case *TSInterfaceDeclaration:
+		if n.NodeType != "TSInterfaceDeclaration" {
+			return false
+		}
+		if n.ID != nil {
+			fn("id", -1, n.ID)
+		}
+		if n.TypeParameters != nil {
+			fn("typeParameters", -1, n.TypeParameters)
+		}
+		for i := range n.Extends {
+			fn("extends", i, &n.Extends[i])
+		}
+		if n.Body != nil {
+			fn("body", -1, n.Body)
+		}
+	case *TSInterfaceHeritage:
+		if n.NodeType != "TSInterfaceHeritage" {
+			return false
+		}
+		if n.Expression != nil {
+			fn("expression", -1, n.Expression)
+		}
+		if n.TypeArguments != nil {
+			fn("typeArguments", -1, n.TypeArguments)
+		}
+	case *TSIntersectionType:
+		if n.NodeType != "TSIntersectionType" {
+			return false
+		}
+		for i, c := range n.Types {
+			if c != nil {
+				fn("types", i, c)
+			}
+		}
+	case *TSIntrinsicKeyword:
+		if n.NodeType != "TSIntrinsicKeyword" {
+			return false
+		}
+	case *TSLiteralType:
+		if n.NodeType != "TSLiteralType" {
+			return false
+		}
+		if c, ok := n.Literal.(Node); ok {
+			fn("literal", -1, c)
+		}
+	case *TSMappedType:
+		if n.NodeType != "TSMappedType" {
+			return false
+		}
+		if n.TypeParameter != nil {
+			fn("typeParameter", -1, n.TypeParameter)
+		}
+		if n.NameType != nil {
+			fn("nameType", -1, n.NameType)
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+	case *TSMethodSignature:
+		if n.NodeType != "TSMethodSignature" {
+			return false
+		}
+		if n.Key != nil {
+			fn("key", -1, n.Key)
+		}
+		if n.TypeParameters != nil {
+			fn("typeParameters", -1, n.TypeParameters)
+		}
+		for i, c := range n.Params {
+			if c != nil {
+				fn("params", i, c)
+			}
+		}
+		if n.ReturnType != nil {
+			fn("returnType", -1, n.ReturnType)
+		}
+	case *TSModuleBlock:
+		if n.NodeType != "TSModuleBlock" {
+			return false
+		}
+		for i, c := range n.Body {
+			if c != nil {
+				fn("body", i, c)
+			}
+		}
+	case *TSModuleDeclaration:
+		if n.NodeType != "TSModuleDeclaration" {
+			return false
+		}
+		if c, ok := n.ID.(Node); ok {
+			fn("id", -1, c)
+		}
+		if c, ok := n.Body.(Node); ok {
+			fn("body", -1, c)
+		}
+	case *TSNamedTupleMember:
+		if n.NodeType != "TSNamedTupleMember" {
+			return false
+		}
+		if n.Label != nil {
+			fn("label", -1, n.Label)
+		}
+		if n.ElementType != nil {
+			fn("elementType", -1, n.ElementType)
+		}
+	case *TSNamespaceExportDeclaration:
+		if n.NodeType != "TSNamespaceExportDeclaration" {
+			return false
+		}
+		if n.ID != nil {
+			fn("id", -1, n.ID)
+		}
+	case *TSNeverKeyword:
+		if n.NodeType != "TSNeverKeyword" {
+			return false
+		}
+	case *TSNonNullExpression:
+		if n.NodeType != "TSNonNullExpression" {
+			return false
+		}
+		if n.Expression != nil {
+			fn("expression", -1, n.Expression)
+		}
+	case *TSNullKeyword:
+		if n.NodeType != "TSNullKeyword" {
+			return false
+		}
+	case *TSNumberKeyword:
+		if n.NodeType != "TSNumberKeyword" {
+			return false
+		}
+	case *TSObjectKeyword:
+		if n.NodeType != "TSObjectKeyword" {
+			return false
+		}
+	case *TSOptionalType:
+		if n.NodeType != "TSOptionalType" {
+			return false
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+	case *TSParameterProperty:
+		if n.NodeType != "TSParameterProperty" {
+			return false
+		}
+		for i := range n.Decorators {
+			fn("decorators", i, &n.Decorators[i])
+		}
+		if n.Parameter != nil {
+			fn("parameter", -1, n.Parameter)
+		}
+	case *TSPropertySignature:
+		if n.NodeType != "TSPropertySignature" {
+			return false
+		}
+		if n.Key != nil {
+			fn("key", -1, n.Key)
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+	case *TSQualifiedName:
+		if n.NodeType != "TSQualifiedName" {
+			return false
+		}
+		if c, ok := n.Left.(Node); ok {
+			fn("left", -1, c)
+		}
+		if n.Right != nil {
+			fn("right", -1, n.Right)
+		}
+	case *TSRestType:
+		if n.NodeType != "TSRestType" {
+			return false
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+	case *TSSatisfiesExpression:
+		if n.NodeType != "TSSatisfiesExpression" {
+			return false
+		}
+		if n.Expression != nil {
+			fn("expression", -1, n.Expression)
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+	case *TSStringKeyword:
+		if n.NodeType != "TSStringKeyword" {
+			return false
+		}
+	case *TSSymbolKeyword:
+		if n.NodeType != "TSSymbolKeyword" {
+			return false
+		}
+	case *TSTemplateLiteralType:
+		if n.NodeType != "TSTemplateLiteralType" {
+			return false
+		}
+		for i := range n.Quasis {
+			fn("quasis", i, &n.Quasis[i])
+		}
+		for i, c := range n.Types {
+			if c != nil {
+				fn("types", i, c)
+			}
+		}
+	case *TSThisType:
+		if n.NodeType != "TSThisType" {
+			return false
+		}
+	case *TSTupleType:
+		if n.NodeType != "TSTupleType" {
+			return false
+		}
+		for i, c := range n.ElementTypes {
+			if c != nil {
+				fn("elementTypes", i, c)
+			}
+		}
+	case *TSTypeAliasDeclaration:
+		if n.NodeType != "TSTypeAliasDeclaration" {
+			return false
+		}
+		if n.ID != nil {
+			fn("id", -1, n.ID)
+		}
+		if n.TypeParameters != nil {
+			fn("typeParameters", -1, n.TypeParameters)
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+	case *TSTypeAnnotation:
+		if n.NodeType != "TSTypeAnnotation" {
+			return false
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+	case *TSTypeAssertion:
+		if n.NodeType != "TSTypeAssertion" {
+			return false
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+		if n.Expression != nil {
+			fn("expression", -1, n.Expression)
+		}
+	case *TSTypeLiteral:
+		if n.NodeType != "TSTypeLiteral" {
+			return false
+		}
+		for i, e := range n.Members {
+			if c, ok := e.(Node); ok {
+				fn("members", i, c)
+			}
+		}
+	case *TSTypeOperator:
+		if n.NodeType != "TSTypeOperator" {
+			return false
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+	case *TSTypeParameter:
+		if n.NodeType != "TSTypeParameter" {
+			return false
+		}
+		if n.Name != nil {
+			fn("name", -1, n.Name)
+		}
+		if n.Constraint != nil {
+			fn("constraint", -1, n.Constraint)
+		}
+		if n.Default != nil {
+			fn("default", -1, n.Default)
+		}
+	case *TSTypeParameterDeclaration:
+		if n.NodeType != "TSTypeParameterDeclaration" {
+			return false
+		}
+		for i := range n.Params {
+			fn("params", i, &n.Params[i])
+		}
+	case *TSTypeParameterInstantiation:
+		if n.NodeType != "TSTypeParameterInstantiation" {
+			return false
+		}
+		for i, c := range n.Params {
+			if c != nil {
+				fn("params", i, c)
+			}
+		}
+	case *TSTypePredicate:
+		if n.NodeType != "TSTypePredicate" {
+			return false
+		}
+		if c, ok := n.ParameterName.(Node); ok {
+			fn("parameterName", -1, c)
+		}
+		if n.TypeAnnotation != nil {
+			fn("typeAnnotation", -1, n.TypeAnnotation)
+		}
+	case *TSTypeQuery:
+		if n.NodeType != "TSTypeQuery" {
+			return false
+		}
+		if c, ok := n.ExprName.(Node); ok {
+			fn("exprName", -1, c)
+		}
+		if n.TypeArguments != nil {
+			fn("typeArguments", -1, n.TypeArguments)
+		}
+	case *TSTypeReference:
+		if n.NodeType != "TSTypeReference" {
+			return false
+		}
+		if c, ok := n.TypeName.(Node); ok {
+			fn("typeName", -1, c)
+		}
+		if n.TypeArguments != nil {
+			fn("typeArguments", -1, n.TypeArguments)
+		}
+	case *TSUndefinedKeyword:
+		if n.NodeType != "TSUndefinedKeyword" {
+			return false
+		}
+	case *TSUnionType:
+		if n.NodeType != "TSUnionType" {
+			return false
+		}
+		for i, c := range n.Types {
+			if c != nil {
+				fn("types", i, c)
+			}
+		}
+	case *TSUnknownKeyword:
+		if n.NodeType != "TSUnknownKeyword" {
+			return false
+		}
+	case *TSVoidKeyword:
+		if n.NodeType != "TSVoidKeyword" {
+			return false
+		}
+	case *TaggedTemplateExpression:
+		if n.NodeType != "TaggedTemplateExpression" {
+			return false
+		}
+		if n.Tag != nil {
+			fn("tag", -1, n.Tag)
+		}
+		if n.TypeArguments != nil {
+			fn("typeArguments", -1, n.TypeArguments)
+		}
+		if n.Quasi != nil {
+			fn("quasi", -1, n.Quasi)
+		}
+	case *TemplateElement:
+		if n.NodeType != "TemplateElement" {
+			return false
+		}
+	case *TemplateLiteral:
+		if n.NodeType != "TemplateLiteral" {
+			return false
+		}
+		for i := range n.Quasis {
+			fn("quasis", i, &n.Quasis[i])
+		}
+		for i, c := range n.Expressions {
+			if c != nil {
+				fn("expressions", i, c)
+			}
+		}
+	case *ThisExpression:
+		if n.NodeType != "ThisExpression" {
+			return false
+		}
+	case *ThrowStatement:
+		if n.NodeType != "ThrowStatement" {
+			return false
+		}
+		if n.Argument != nil {
+			fn("argument", -1, n.Argument)
+		}
+	case *TryStatement:
+		if n.NodeType != "TryStatement" {
+			return false
+		}
+		if n.Block != nil {
+			fn("block", -1, n.Block)
+		}
+		if n.Handler != nil {
+			fn("handler", -1, n.Handler)
+		}
+		if n.Finalizer != nil {
+			fn("finalizer", -1, n.Finalizer)
+		}
+	case *UnaryExpression:
+		if n.NodeType != "UnaryExpression" {
+			return false
+		}
+		if n.Argument != nil {
+			fn("argument", -1, n.Argument)
+		}
+	case *UpdateExpression:
+		if n.NodeType != "UpdateExpression" {
+			return false
+		}
+		if n.Argument != nil {
+			fn("argument", -1, n.Argument)
+		}
+	case *VariableDeclaration:
+		if n.NodeType != "VariableDeclaration" {
+			return false
+		}
+		for i := range n.Declarations {
+			fn("declarations", i, &n.Declarations[i])
+		}
+	case *VariableDeclarator:
+		if n.NodeType != "VariableDeclarator" {
+			return false
+		}
+		if n.ID != nil {
+			fn("id", -1, n.ID)
+		}
+		if n.Init != nil {
+			fn("init", -1, n.Init)
+		}
+	case *WhileStatement:
+		if n.NodeType != "WhileStatement" {
+			return false
+		}
+		if n.Test != nil {
+			fn("test", -1, n.Test)
+		}
+		if n.Body != nil {
+			fn("body", -1, n.Body)
+		}
+	case *WithStatement:
+		if n.NodeType != "WithStatement" {
+			return false
+		}
+		if n.Object != nil {
+			fn("object", -1, n.Object)
+		}
+		if n.Body != nil {
+			fn("body", -1, n.Body)
+		}
+	case *YieldExpression:
+		if n.NodeType != "YieldExpression" {
+			return false
+		}
+		if n.Argument != nil {
+			fn("argument", -1, n.Argument)
+		}
+	default:
+		return false
+	}
+	return true
+}
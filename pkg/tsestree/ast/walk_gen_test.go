@@ -0,0 +1,165 @@
+// Code generated by walkgen from visitor_keys.go and the node definitions; DO NOT EDIT.
+
+package ast
+
+// generatedNodes holds a node of each type forEachChild was generated for.
+var generatedNodes = []Node{
+	&AccessorProperty{},
+	&ArrayExpression{},
+	&ArrayPattern{},
+	&ArrowFunctionExpression{},
+	&AssignmentExpression{},
+	&AssignmentPattern{},
+	&AwaitExpression{},
+	&BinaryExpression{},
+	&BlockStatement{},
+	&BreakStatement{},
+	&CallExpression{},
+	&CatchClause{},
+	&ChainExpression{},
+	&ClassBody{},
+	&ClassDeclaration{},
+	&ClassExpression{},
+	&ConditionalExpression{},
+	&ContinueStatement{},
+	&DebuggerStatement{},
+	&Decorator{},
+	&DoWhileStatement{},
+	&EmptyStatement{},
+	&ExportAllDeclaration{},
+	&ExportDefaultDeclaration{},
+	&ExportNamedDeclaration{},
+	&ExportSpecifier{},
+	&ExpressionStatement{},
+	&ForInStatement{},
+	&ForOfStatement{},
+	&ForStatement{},
+	&FunctionDeclaration{},
+	&FunctionExpression{},
+	&Identifier{},
+	&IfStatement{},
+	&ImportAttribute{},
+	&ImportDeclaration{},
+	&ImportDefaultSpecifier{},
+	&ImportExpression{},
+	&ImportNamespaceSpecifier{},
+	&ImportSpecifier{},
+	&JSXAttribute{},
+	&JSXClosingElement{},
+	&JSXClosingFragment{},
+	&JSXElement{},
+	&JSXEmptyExpression{},
+	&JSXExpressionContainer{},
+	&JSXFragment{},
+	&JSXIdentifier{},
+	&JSXMemberExpression{},
+	&JSXNamespacedName{},
+	&JSXOpeningElement{},
+	&JSXOpeningFragment{},
+	&JSXSpreadAttribute{},
+	&JSXSpreadChild{},
+	&JSXText{},
+	&LabeledStatement{},
+	&Literal{},
+	&LogicalExpression{},
+	&MemberExpression{},
+	&MetaProperty{},
+	&MethodDefinition{},
+	&NewExpression{},
+	&ObjectExpression{},
+	&ObjectPattern{},
+	&PrivateIdentifier{},
+	&Program{},
+	&Property{},
+	&PropertyDefinition{},
+	&RestElement{},
+	&ReturnStatement{},
+	&SequenceExpression{},
+	&SpreadElement{},
+	&StaticBlock{},
+	&Super{},
+	&SwitchCase{},
+	&SwitchStatement{},
+	&TSAbstractAccessorProperty{},
+	&TSAbstractMethodDefinition{},
+	&TSAbstractPropertyDefinition{},
+	&TSAnyKeyword{},
+	&TSArrayType{},
+	&TSAsExpression{},
+	&TSBigIntKeyword{},
+	&TSBooleanKeyword{},
+	&TSCallSignatureDeclaration{},
+	&TSClassImplements{},
+	&TSConditionalType{},
+	&TSConstructSignatureDeclaration{},
+	&TSConstructorType{},
+	&TSDeclareFunction{},
+	&TSEmptyBodyFunctionExpression{},
+	&TSEnumDeclaration{},
+	&TSEnumMember{},
+	&TSExportAssignment{},
+	&TSExternalModuleReference{},
+	&TSFunctionType{},
+	&TSImportEqualsDeclaration{},
+	&TSImportType{},
+	&TSIndexSignature{},
+	&TSIndexedAccessType{},
+	&TSInferType{},
+	&TSInstantiationExpression{},
+	&TSInterfaceBody{},
+	&TSInterfaceDeclaration{},
+	&TSInterfaceHeritage{},
+	&TSIntersectionType{},
+	&TSIntrinsicKeyword{},
+	&TSLiteralType{},
+	&TSMappedType{},
+	&TSMethodSignature{},
+	&TSModuleBlock{},
+	&TSModuleDeclaration{},
+	&TSNamedTupleMember{},
+	&TSNamespaceExportDeclaration{},
+	&TSNeverKeyword{},
+	&TSNonNullExpression{},
+	&TSNullKeyword{},
+	&TSNumberKeyword{},
+	&TSObjectKeyword{},
+	&TSOptionalType{},
+	&TSParameterProperty{},
+	&TSPropertySignature{},
+	&TSQualifiedName{},
+	&TSRestType{},
+	&TSSatisfiesExpression{},
+	&TSStringKeyword{},
+	&TSSymbolKeyword{},
+	&TSTemplateLiteralType{},
+	&TSThisType{},
+	&TSTupleType{},
+	&TSTypeAliasDeclaration{},
+	&TSTypeAnnotation{},
+	&TSTypeAssertion{},
+	&TSTypeLiteral{},
+	&TSTypeOperator{},
+	&TSTypeParameter{},
+	&TSTypeParameterDeclaration{},
+	&TSTypeParameterInstantiation{},
+	&TSTypePredicate{},
+	&TSTypeQuery{},
+	&TSTypeReference{},
+	&TSUndefinedKeyword{},
+	&TSUnionType{},
+	&TSUnknownKeyword{},
+	&TSVoidKeyword{},
+	&TaggedTemplateExpression{},
+	&TemplateElement{},
+	&TemplateLiteral{},
+	&ThisExpression{},
+	&ThrowStatement{},
+	&TryStatement{},
+	&UnaryExpression{},
+	&UpdateExpression{},
+	&VariableDeclaration{},
+	&VariableDeclarator{},
+	&WhileStatement{},
+	&WithStatement{},
+	&YieldExpression{},
+}
@@ -0,0 +1,289 @@
+package ast
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// child is a child reported by forEachChild or reflectChildren.
+type child struct {
+	key   string
+	index int
+	node  Node
+}
+
+func collectChildren(node Node, each func(Node, func(string, int, Node))) []child {
+	var children []child
+	each(node, func(key string, index int, node Node) {
+		children = append(children, child{key, index, node})
+	})
+	return children
+}
+
+// nodeFiller populates every node-holding field of a node, so that the
+// children found by the generated code can be compared with reflection.
+type nodeFiller struct {
+	types []reflect.Type // the struct types of generatedNodes
+}
+
+func newNodeFiller() *nodeFiller {
+	f := &nodeFiller{}
+	for _, n := range generatedNodes {
+		f.types = append(f.types, reflect.TypeOf(n).Elem())
+	}
+	return f
+}
+
+// newNode returns a node of struct type t with its NodeType set.
+func (f *nodeFiller) newNode(t reflect.Type) reflect.Value {
+	v := reflect.New(t)
+	v.Interface().(interface{ Base() *BaseNode }).Base().NodeType = t.Name()
+	return v
+}
+
+// value returns a non-nil value assignable to a field of type t, or an
+// invalid value if t cannot hold a node.
+func (f *nodeFiller) value(t reflect.Type) reflect.Value {
+	nodeType := reflect.TypeOf((*Node)(nil)).Elem()
+
+	//nolint:exhaustive // Only types that can hold nodes are filled
+	switch t.Kind() {
+	case reflect.Interface:
+		for _, st := range f.types {
+			if reflect.PointerTo(st).Implements(t) && reflect.PointerTo(st).Implements(nodeType) {
+				return f.newNode(st)
+			}
+		}
+	case reflect.Ptr:
+		if t.Elem().Kind() == reflect.Struct && t.Implements(nodeType) {
+			return f.newNode(t.Elem())
+		}
+	case reflect.Struct:
+		if reflect.PointerTo(t).Implements(nodeType) {
+			return f.newNode(t).Elem()
+		}
+	case reflect.Slice:
+		if elem := f.value(t.Elem()); elem.IsValid() {
+			s := reflect.MakeSlice(t, 2, 2)
+			s.Index(0).Set(elem)
+			s.Index(1).Set(f.value(t.Elem()))
+			return s
+		}
+	}
+	return reflect.Value{}
+}
+
+// fill sets every field of the struct v that can hold a node.
+func (f *nodeFiller) fill(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field, sf := v.Field(i), v.Type().Field(i)
+		if !sf.IsExported() || sf.Type == reflect.TypeOf(BaseNode{}) {
+			continue
+		}
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			f.fill(field)
+			continue
+		}
+		if sf.Type.Kind() == reflect.Struct {
+			continue
+		}
+		if value := f.value(sf.Type); value.IsValid() {
+			field.Set(value)
+		}
+	}
+}
+
+// TestForEachChild_MatchesVisitorKeys checks that, for every node type, the
+// generated child iteration visits the same children in the same order as
+// the reflective walker, which follows VisitorKeys.
+func TestForEachChild_MatchesVisitorKeys(t *testing.T) {
+	filler := newNodeFiller()
+
+	for _, n := range generatedNodes {
+		typ := reflect.TypeOf(n).Elem()
+		t.Run(typ.Name(), func(t *testing.T) {
+			node := filler.newNode(typ)
+			filler.fill(node.Elem())
+
+			var generated []child
+			if !forEachChild(node.Interface().(Node), func(key string, index int, n Node) {
+				generated = append(generated, child{key, index, n})
+			}) {
+				t.Fatal("expected generated code for node type")
+			}
+			reflected := collectChildren(node.Interface().(Node), reflectChildren)
+
+			if !slices.Equal(generated, reflected) {
+				t.Errorf("expected children %v, got %v", reflected, generated)
+			}
+			if len(GetVisitorKeys(typ.Name())) > 0 && len(generated) == 0 {
+				t.Error("expected children for node type with visitor keys")
+			}
+		})
+	}
+}
+
+func TestForEachChild_CoversVisitorKeys(t *testing.T) {
+	generated := make(map[string]bool)
+	for _, n := range generatedNodes {
+		generated[reflect.TypeOf(n).Elem().Name()] = true
+	}
+
+	// Node types that have visitor keys but no Go type.
+	missing := map[string]bool{
+		"TSParenthesizedType": true,
+	}
+
+	for nodeType := range VisitorKeys {
+		if !generated[nodeType] && !missing[nodeType] {
+			t.Errorf("no generated child iteration for %s; run go generate", nodeType)
+		}
+	}
+}
+
+func TestForEachChild_Fallback(t *testing.T) {
+	id := &Identifier{BaseNode: BaseNode{NodeType: "Identifier"}, Name: "a"}
+
+	// A node whose NodeType is not the name of its Go type is walked by its
+	// NodeType's visitor keys.
+	node := &UnaryExpression{BaseNode: BaseNode{NodeType: "AwaitExpression"}, Argument: id}
+	if forEachChild(node, func(string, int, Node) {}) {
+		t.Error("expected no generated code for mismatched node type")
+	}
+
+	var visited []Node
+	Traverse(node, func(n Node) bool {
+		visited = append(visited, n)
+		return true
+	})
+	if want := []Node{node, id}; !slices.Equal(visited, want) {
+		t.Errorf("expected %v, got %v", want, visited)
+	}
+}
+
+// benchmarkProgram returns a program of n statements of the form
+//
+//	const x = f(a.b, c);
+//	function g(p) { if (p) { return p; } }
+func benchmarkProgram(n int) *Program {
+	ident := func(name string) *Identifier {
+		return &Identifier{BaseNode: BaseNode{NodeType: "Identifier"}, Name: name}
+	}
+
+	program := &Program{BaseNode: BaseNode{NodeType: "Program"}}
+	for i := 0; i < n; i++ {
+		call := &CallExpression{
+			BaseNode: BaseNode{NodeType: "CallExpression"},
+			Callee:   ident("f"),
+			Arguments: []Expression{
+				&MemberExpression{BaseNode: BaseNode{NodeType: "MemberExpression"}, Object: ident("a"), Property: ident("b")},
+				ident("c"),
+			},
+		}
+		decl := &VariableDeclaration{
+			BaseNode: BaseNode{NodeType: "VariableDeclaration"},
+			Kind:     "const",
+			Declarations: []VariableDeclarator{
+				{BaseNode: BaseNode{NodeType: "VariableDeclarator"}, ID: ident("x"), Init: call},
+			},
+		}
+		fn := &FunctionDeclaration{
+			BaseNode: BaseNode{NodeType: "FunctionDeclaration"},
+			ID:       ident("g"),
+			Params:   []Pattern{ident("p")},
+			Body: &BlockStatement{
+				BaseNode: BaseNode{NodeType: "BlockStatement"},
+				Body: []Statement{&IfStatement{
+					BaseNode: BaseNode{NodeType: "IfStatement"},
+					Test:     ident("p"),
+					Consequent: &BlockStatement{
+						BaseNode: BaseNode{NodeType: "BlockStatement"},
+						Body:     []Statement{&ReturnStatement{BaseNode: BaseNode{NodeType: "ReturnStatement"}, Argument: ident("p")}},
+					},
+				}},
+			},
+		}
+		program.Body = append(program.Body, decl, fn)
+	}
+	return program
+}
+
+// walkReflect is Walk using only reflection, as it was before walk_gen.go.
+func walkReflect(node Node, visitor Visitor) {
+	if visitor.Visit(node) {
+		reflectChildren(node, func(_ string, _ int, child Node) {
+			walkReflect(child, visitor)
+		})
+	}
+}
+
+func BenchmarkWalk(b *testing.B) {
+	program := benchmarkProgram(1000)
+	visitor := VisitorFunc(func(Node) bool { return true })
+
+	b.Run("Generated", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Walk(program, visitor)
+		}
+	})
+	b.Run("Reflect", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			walkReflect(program, visitor)
+		}
+	})
+}
+
+// walkWithContextReflect is WalkWithContext finding children only with
+// reflection, as it did before walk_gen.go.
+func walkWithContextReflect(node Node, visitor ContextVisitor, ctx *TraverseContext) {
+	if !visitor.VisitWithContext(node, ctx) {
+		return
+	}
+
+	var newAncestors []Node
+	linked := false
+
+	reflectChildren(node, func(key string, index int, child Node) {
+		if !linked {
+			linked = true
+			if ctx.Parent != nil {
+				newAncestors = make([]Node, len(ctx.Ancestors)+1)
+				copy(newAncestors, ctx.Ancestors)
+				newAncestors[len(ctx.Ancestors)] = ctx.Parent
+			} else {
+				newAncestors = ctx.Ancestors
+			}
+		}
+
+		childCtx := &TraverseContext{
+			Parent:    node,
+			Ancestors: newAncestors,
+			Key:       key,
+		}
+		if index >= 0 {
+			childCtx.Index = &index
+		}
+		walkWithContextReflect(child, visitor, childCtx)
+	})
+}
+
+func BenchmarkWalkWithContext(b *testing.B) {
+	program := benchmarkProgram(1000)
+	visitor := ContextVisitorFunc(func(Node, *TraverseContext) bool { return true })
+
+	b.Run("Generated", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			WalkWithContext(program, visitor)
+		}
+	})
+	b.Run("Reflect", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			walkWithContextReflect(program, visitor, &TraverseContext{Ancestors: []Node{}})
+		}
+	})
+}
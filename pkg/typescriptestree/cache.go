@@ -1,10 +1,52 @@
 package typescriptestree
 
 import (
+	"sync"
+	"time"
+
 	"github.com/kdy1/go-typescript-eslint/internal/program"
 )
 
-// ClearProgramCache clears all cached TypeScript programs.
+// programCaches holds the program cache of each CacheLifetime.Glob used, so
+// that calls with the same lifetime share their programs.
+var programCaches = struct {
+	caches map[CacheDurationSeconds]*program.ProgramCache
+	mu     sync.Mutex
+}{caches: make(map[CacheDurationSeconds]*program.ProgramCache)}
+
+// programCache returns the cache of the programs created for opts: the one
+// for CacheLifetime.Glob if it is set, and the global cache otherwise.
+func programCache(opts *ParseAndGenerateServicesOptions) *program.ProgramCache {
+	if opts.CacheLifetime == nil || opts.CacheLifetime.Glob == nil {
+		return program.GlobalCache
+	}
+	lifetime := *opts.CacheLifetime.Glob
+
+	programCaches.mu.Lock()
+	defer programCaches.mu.Unlock()
+
+	cache, ok := programCaches.caches[lifetime]
+	if !ok {
+		cache = program.NewProgramCache(time.Duration(lifetime) * time.Second)
+		programCaches.caches[lifetime] = cache
+	}
+	return cache
+}
+
+// clearProgramCaches clears and drops the caches of programCaches.
+func clearProgramCaches() {
+	programCaches.mu.Lock()
+	defer programCaches.mu.Unlock()
+
+	for _, cache := range programCaches.caches {
+		cache.Clear()
+	}
+	programCaches.caches = make(map[CacheDurationSeconds]*program.ProgramCache)
+}
+
+// ClearProgramCache clears all cached TypeScript programs, including the
+// projects opened by the project service, and the files that project globs
+// matched.
 // This is intended exclusively for test isolation between lint operations.
 //
 // Example usage:
@@ -16,9 +58,14 @@ import (
 // The cache automatically expires entries based on the configured lifetime.
 func ClearProgramCache() {
 	program.GlobalCache.Clear()
+	clearProgramCaches()
+	clearProjectServices()
+	clearGlobCache()
 }
 
-// ClearDefaultProjectMatchedFiles clears the tracked project-matched file records.
+// ClearDefaultProjectMatchedFiles clears the tracked project-matched file records:
+// the files the project service has parsed with the default project, which
+// count towards ProjectServiceOptions.MaximumDefaultProjectFileMatchCount.
 // This is intended for test isolation and cleanup.
 //
 // Example usage:
@@ -29,7 +76,5 @@ func ClearProgramCache() {
 // This is primarily for internal use and testing. In most cases, you don't
 // need to call this function manually.
 func ClearDefaultProjectMatchedFiles() {
-	// This is a placeholder for future implementation
-	// In typescript-estree, this tracks which files were matched by default projects
-	// For now, we don't have this tracking, but we provide the API for compatibility
+	clearDefaultProjectFiles()
 }
@@ -1,8 +1,8 @@
 package typescriptestree
 
 import (
-	"github.com/kdy1/go-typescript-eslint/internal/ast"
 	"github.com/kdy1/go-typescript-eslint/internal/lexer"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 )
 
 // AST_NODE_TYPES provides the string values for every single AST node's type property.
@@ -13,6 +13,9 @@
 //   - ParseAndGenerateServices: Parses code and generates TypeScript program services
 //     for type-aware linting and analysis
 //
+// Tools that do not need a full AST can use Tokenize or a Tokenizer, which
+// stream tokens together with the whitespace and comments around them.
+//
 // # Constants
 //
 // The package also exports constants for AST node and token types:
@@ -65,7 +68,7 @@
 //   - internal/lexer: Tokenizes TypeScript source code
 //   - internal/parser: Parses tokens into an AST using recursive descent parsing
 //   - internal/converter: Transforms TypeScript AST into ESTree format
-//   - internal/ast: Defines AST node types and utilities
+//   - pkg/tsestree/ast: Defines AST node types and utilities
 //   - internal/program: Manages TypeScript programs and tsconfig.json parsing
 //
 // # Compatibility
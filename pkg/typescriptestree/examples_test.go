@@ -124,8 +124,11 @@ func Example_parseAndGenerateServices() {
 
 	fmt.Printf("Has AST: %t\n", result.AST != nil)
 	fmt.Printf("Has Services: %t\n", result.Services != nil)
+	fmt.Printf("Has Program: %t\n", result.Services.Program != nil)
 	// Output:
-	// ParseAndGenerateServices requires a TypeScript project configuration
+	// Has AST: true
+	// Has Services: true
+	// Has Program: false
 }
 
 // Example_nodeTypes demonstrates using AST_NODE_TYPES constants.
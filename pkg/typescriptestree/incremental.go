@@ -0,0 +1,333 @@
+package typescriptestree
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/kdy1/go-typescript-eslint/internal/converter"
+	"github.com/kdy1/go-typescript-eslint/internal/parser"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
+)
+
+// TextEdit replaces the text between Start and End with NewText. Offsets are
+// measured in the OffsetEncoding of the parse options, so the UTF-16 offsets
+// used by editors and the Language Server Protocol can be passed unchanged.
+type TextEdit struct {
+	Start   int
+	End     int
+	NewText string
+}
+
+// incrementalState is what ParseIncremental needs to know about a parse.
+// All positions in it are byte offsets into source.
+type incrementalState struct {
+	source  string
+	offsets *SourceOffsets
+	opts    ParseOptions
+
+	// Where each top-level statement of the AST came from. Only valid if
+	// complete is set.
+	spans []parser.StatementSpan
+
+	// All comments and tokens of the source, regardless of whether the
+	// options include them in the AST.
+	comments []ast.Comment
+	tokens   []ast.Token
+
+	// complete reports that the source parsed without errors. Error recovery
+	// skips input that belongs to no statement, so only a clean parse can
+	// be reused.
+	complete bool
+}
+
+// ParseIncremental applies edits to the source of a previous result and
+// parses the new source with the same options. Top-level statements whose
+// text and lookahead lie entirely before or after the edited region are
+// reused, shifted to their new positions, and only the region in between is
+// scanned and parsed again. The result is identical to that of Parse on the
+// new source.
+//
+// Edits are applied in order, each to the text produced by the ones before it.
+// prev must have been returned by Parse or ParseIncremental. Its AST is reused
+// and must not be used once ParseIncremental returns.
+func ParseIncremental(prev *Result, edits []TextEdit) (*Result, error) {
+	if prev == nil || prev.incremental == nil {
+		return nil, errors.New("result does not support incremental parsing")
+	}
+	state := prev.incremental
+	opts := state.opts
+
+	source, err := applyEdits(state.source, state.offsets, edits, opts.OffsetEncoding)
+	if err != nil {
+		return nil, err
+	}
+	if !state.complete {
+		return Parse(source, &opts)
+	}
+
+	// Everything before damageStart and after the damage end is unchanged,
+	// although text after it has moved by delta bytes.
+	old := state.source
+	damageStart := commonPrefixLength(old, source)
+	suffix := commonSuffixLength(old[damageStart:], source[damageStart:])
+	oldDamageEnd, newDamageEnd := len(old)-suffix, len(source)-suffix
+	delta := len(source) - len(old)
+
+	// Statements that read no input from the damaged region are unaffected.
+	reused := 0
+	for reused < len(state.spans) && state.spans[reused].Lookahead <= damageStart {
+		reused++
+	}
+	restart := 0
+	if reused > 0 {
+		restart = state.spans[reused-1].End
+	}
+
+	offsets := NewSourceOffsets(source)
+	p := newParser(source, &opts)
+	if restart > 0 {
+		line, column := offsets.lineColumn(restart, OffsetEncodingUTF16)
+		p.Seek(restart, line, column)
+	}
+
+	// Once past the damage, parsing stops as soon as it reaches the start of
+	// an old statement, from where on the old statements are reused.
+	resume := len(state.spans)
+	region, spans, err := p.ParseUntil(func(offset int) bool {
+		if offset < newDamageEnd {
+			return false
+		}
+		i, found := slices.BinarySearchFunc(state.spans[reused:], offset-delta, func(span parser.StatementSpan, start int) int {
+			return span.Start - start
+		})
+		if found {
+			resume = reused + i
+		}
+		return found
+	})
+	if err != nil && !opts.AllowInvalidAST {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+
+	conv := converter.NewConverter(source, &converter.Options{
+		PreserveNodeMaps:                   false,
+		UseJSDocParsingMode:                opts.JSDocParsingMode != JSDocParsingModeNone,
+		SuppressDeprecatedPropertyWarnings: opts.SuppressDeprecatedPropertyWarnings,
+		SetParents:                         !opts.OmitParents,
+	})
+	estreeProgram := conv.ConvertProgram(region)
+
+	w := positionWriter{offsets: offsets, opts: &opts}
+	for _, stmt := range estreeProgram.Body {
+		w.node(stmt)
+	}
+
+	// Reused statements after the damage keep their positions relative to
+	// its end, so shift them by how far the end moved.
+	enc := opts.OffsetEncoding
+	oldLine, oldColumn := state.offsets.lineColumn(oldDamageEnd, enc)
+	newLine, newColumn := offsets.lineColumn(newDamageEnd, enc)
+	shift := positionShift{
+		offset: offsets.Convert(newDamageEnd, OffsetEncodingByte, enc) -
+			state.offsets.Convert(oldDamageEnd, OffsetEncodingByte, enc),
+		line:   newLine - oldLine,
+		onLine: oldLine,
+		column: newColumn - oldColumn,
+	}
+	for _, stmt := range prev.AST.Body[resume:] {
+		shift.node(stmt)
+	}
+
+	body := make([]ast.Statement, 0, reused+len(estreeProgram.Body)+len(prev.AST.Body)-resume)
+	body = append(body, prev.AST.Body[:reused]...)
+	body = append(body, estreeProgram.Body...)
+	body = append(body, prev.AST.Body[resume:]...)
+	estreeProgram.Body = body
+	if !opts.OmitParents {
+		for _, stmt := range body {
+			if b, ok := stmt.(interface{ SetParent(ast.Node) }); ok {
+				b.SetParent(estreeProgram)
+			}
+		}
+	}
+
+	next := &incrementalState{
+		source:   source,
+		offsets:  offsets,
+		opts:     opts,
+		complete: err == nil,
+	}
+
+	next.spans = append(next.spans, state.spans[:reused]...)
+	next.spans = append(next.spans, spans...)
+	for _, span := range state.spans[resume:] {
+		next.spans = append(next.spans, parser.StatementSpan{
+			Start:     span.Start + delta,
+			End:       span.End + delta,
+			Lookahead: span.Lookahead + delta,
+		})
+	}
+
+	// Old comments and tokens are kept up to where parsing restarted and
+	// from where it stopped, with the parsed region's own in between.
+	resumeAt := len(old)
+	if resume < len(state.spans) {
+		resumeAt = state.spans[resume].Start
+	}
+	next.comments = spliceComments(state.comments, region.Comments, restart, resumeAt, delta)
+	next.tokens = spliceTokens(state.tokens, region.Tokens, restart, resumeAt, delta)
+
+	var jsxConfig *JSXConfig
+	if opts.JSX {
+		jsxConfig = resolveJSXConfig(next.comments, next.tokens, nil)
+	}
+
+	estreeProgram.Comments = nil
+	if opts.Comment && len(next.comments) > 0 {
+		estreeProgram.Comments = slices.Clone(next.comments)
+		for i := range estreeProgram.Comments {
+			estreeProgram.Comments[i].Range, estreeProgram.Comments[i].Loc = w.convert(estreeProgram.Comments[i].Range)
+		}
+	}
+	estreeProgram.Tokens = nil
+	if opts.Tokens && len(next.tokens) > 0 {
+		estreeProgram.Tokens = slices.Clone(next.tokens)
+		for i := range estreeProgram.Tokens {
+			estreeProgram.Tokens[i].Range, estreeProgram.Tokens[i].Loc = w.convert(estreeProgram.Tokens[i].Range)
+		}
+	}
+
+	return &Result{
+		AST:         estreeProgram,
+		JSX:         jsxConfig,
+		incremental: next,
+	}, err
+}
+
+// newIncrementalState records what a later ParseIncremental needs to know
+// about a parse of source. program is the parser's output, before conversion.
+func newIncrementalState(source string, opts *ParseOptions, program *ast.Program, spans []parser.StatementSpan, err error) *incrementalState {
+	return &incrementalState{
+		source:   source,
+		offsets:  NewSourceOffsets(source),
+		opts:     *opts,
+		spans:    spans,
+		comments: program.Comments,
+		tokens:   program.Tokens,
+		complete: err == nil,
+	}
+}
+
+// applyEdits applies edits to source, whose offsets are indexed by offsets.
+func applyEdits(source string, offsets *SourceOffsets, edits []TextEdit, enc OffsetEncoding) (string, error) {
+	for i, edit := range edits {
+		if i > 0 {
+			offsets = NewSourceOffsets(source)
+		}
+
+		length := offsets.Convert(len(source), OffsetEncodingByte, enc)
+		if edit.Start < 0 || edit.End < edit.Start || edit.End > length {
+			return "", fmt.Errorf("edit %d: range [%d, %d) out of bounds [0, %d]", i, edit.Start, edit.End, length)
+		}
+
+		start := offsets.Convert(edit.Start, enc, OffsetEncodingByte)
+		end := offsets.Convert(edit.End, enc, OffsetEncodingByte)
+		source = source[:start] + edit.NewText + source[end:]
+	}
+	return source, nil
+}
+
+// commonPrefixLength returns the length in bytes of the longest common prefix of a and b.
+func commonPrefixLength(a, b string) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
+// commonSuffixLength returns the length in bytes of the longest common suffix of a and b.
+func commonSuffixLength(a, b string) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[len(a)-1-i] != b[len(b)-1-i] {
+			return i
+		}
+	}
+	return n
+}
+
+// spliceComments combines the old comments before restart and from resumeAt
+// on, the latter moved by delta, with the comments of the reparsed region.
+func spliceComments(old, region []ast.Comment, restart, resumeAt, delta int) []ast.Comment {
+	before := sort.Search(len(old), func(i int) bool { return old[i].Range[0] >= restart })
+	after := sort.Search(len(old), func(i int) bool { return old[i].Range[0] >= resumeAt })
+
+	var comments []ast.Comment
+	comments = append(comments, old[:before]...)
+	comments = append(comments, region...)
+	for _, comment := range old[after:] {
+		comment.Range = &ast.Range{comment.Range[0] + delta, comment.Range[1] + delta}
+		comments = append(comments, comment)
+	}
+	return comments
+}
+
+// spliceTokens is spliceComments for tokens.
+func spliceTokens(old, region []ast.Token, restart, resumeAt, delta int) []ast.Token {
+	before := sort.Search(len(old), func(i int) bool { return old[i].Range[0] >= restart })
+	after := sort.Search(len(old), func(i int) bool { return old[i].Range[0] >= resumeAt })
+
+	var tokens []ast.Token
+	tokens = append(tokens, old[:before]...)
+	tokens = append(tokens, region...)
+	for _, token := range old[after:] {
+		token.Range = &ast.Range{token.Range[0] + delta, token.Range[1] + delta}
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// positionShift moves the reported positions of nodes that follow an edit.
+type positionShift struct {
+	offset int // added to range offsets
+	line   int // added to loc lines
+	onLine int // line, before the edit, on which the edit ended
+	column int // added to loc columns on onLine
+}
+
+// node shifts the positions of root and every node below it, once per node.
+func (s positionShift) node(root ast.Node) {
+	seen := map[*ast.BaseNode]bool{}
+	ast.Walk(root, ast.VisitorFunc(func(n ast.Node) bool {
+		b, ok := n.(interface{ Base() *ast.BaseNode })
+		if !ok || seen[b.Base()] {
+			return true
+		}
+
+		base := b.Base()
+		seen[base] = true
+		if base.Range != nil {
+			base.Range = &ast.Range{base.Range[0] + s.offset, base.Range[1] + s.offset}
+		}
+		if base.Loc != nil {
+			loc := *base.Loc
+			loc.Start, loc.End = s.position(loc.Start), s.position(loc.End)
+			base.Loc = &loc
+		}
+		return true
+	}))
+}
+
+// position shifts a single loc position.
+func (s positionShift) position(pos ast.Position) ast.Position {
+	if pos.Line == s.onLine {
+		pos.Column += s.column
+	}
+	pos.Line += s.line
+	return pos
+}
@@ -0,0 +1,253 @@
+package typescriptestree
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
+)
+
+// incrementalSources are the documents the differential test edits.
+var incrementalSources = []string{
+	`import { a } from "./a";
+// leading comment
+const x: number = 1;
+let y = x + 2;
+
+/** doc */
+function add(a: number, b: number): number {
+	return a + b;
+}
+
+class Point {
+	x: number;
+	y: number;
+	constructor(x: number, y: number = 0) { this.x = x; this.y = y; }
+	get length() { return Math.sqrt(this.x * this.x + this.y * this.y); }
+}
+
+// Non-ASCII text shifts UTF-16 offsets: é, 中文 and 😀
+const label: string = "café 😀";
+
+interface Shape { area(): number }
+type Pair<T> = [T, T];
+const pair: Pair<number> = [x, y];
+class Segment extends Array<Point> implements Iterable<Point> {}
+enum Color { Red, Green }
+const s = ` + "`sum ${add(x, y)} é😀`" + `;
+export default Point;
+`,
+	`let a = 1
+let b = a
+++b
+const name = "中文"
+if (a) { b = 2 } else { b = 3 }
+for (let i = 0; i < 3; i++) console.log(i)
+`,
+}
+
+// incrementalJSXSource is edited with JSX enabled.
+var incrementalJSXSource = `/** @jsx h */
+import { h } from "preact";
+const App = () => <div className="app">Hello &amp; welcome {name}</div>;
+const List = <ul>{items.map(i => <li key={i}>{i}</li>)}</ul>;
+export default App;
+`
+
+// incrementalSnippets are inserted by random edits.
+var incrementalSnippets = []string{
+	"x", "1", ";", " ", "\n", "{", "}", "(", ")", ",", "=", "+", "'", "\"",
+	"`", "/", "*", "/* c */", "// c\n", "let z = 0;\n", "function f() {", "é", "😀",
+	"<b>", "</b>", "@jsx", "return", "\r\n",
+}
+
+// randomEdit returns an edit that deletes, inserts or replaces text at a
+// random position of source, with offsets in UTF-16 code units.
+func randomEdit(rng *rand.Rand, source string) TextEdit {
+	boundary := func() int {
+		i := rng.Intn(len(source) + 1)
+		for i < len(source) && !utf8.RuneStart(source[i]) {
+			i++
+		}
+		return i
+	}
+
+	start, end := boundary(), boundary()
+	if start > end {
+		start, end = end, start
+	}
+	if end-start > 20 {
+		end = start
+	}
+
+	var text string
+	if rng.Intn(3) > 0 {
+		text = incrementalSnippets[rng.Intn(len(incrementalSnippets))]
+	}
+
+	offsets := NewSourceOffsets(source)
+	return TextEdit{
+		Start:   offsets.Convert(start, OffsetEncodingByte, OffsetEncodingUTF16),
+		End:     offsets.Convert(end, OffsetEncodingByte, OffsetEncodingUTF16),
+		NewText: text,
+	}
+}
+
+// TestParseIncremental_MatchesFullParse applies random edits to documents and
+// checks that every incremental parse is identical to a full parse.
+func TestParseIncremental_MatchesFullParse(t *testing.T) {
+	tsOpts := NewBuilder().WithLoc(true).WithRange(true).WithTokens(true).WithComment(true).MustBuild()
+	tsOpts.AllowInvalidAST = true
+	jsxOpts := *tsOpts
+	jsxOpts.JSX = true
+
+	type document struct {
+		name   string
+		source string
+		opts   *ParseOptions
+	}
+	documents := []document{
+		{"declarations", incrementalSources[0], tsOpts},
+		{"asi", incrementalSources[1], tsOpts},
+		{"jsx", incrementalJSXSource, &jsxOpts},
+	}
+
+	for _, doc := range documents {
+		t.Run(doc.name, func(t *testing.T) {
+			// The edits must start from a document that parses, for the
+			// incremental path, rather than error recovery, to be compared
+			if _, err := Parse(doc.source, doc.opts); err != nil {
+				t.Fatalf("Parse() of the original document error = %v", err)
+			}
+			rng := rand.New(rand.NewSource(1))
+
+			// Most random edits break the document, and a broken document is
+			// always parsed in full, so each run starts over from the original.
+			for run := 0; run < 200; run++ {
+				source := doc.source
+				result, err := Parse(source, doc.opts)
+				if result == nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+
+				for step := 0; step < 3; step++ {
+					edit := randomEdit(rng, source)
+					newSource, err := applyEdits(source, NewSourceOffsets(source), []TextEdit{edit}, OffsetEncodingUTF16)
+					if err != nil {
+						t.Fatalf("applyEdits() error = %v", err)
+					}
+
+					want, wantErr := Parse(newSource, doc.opts)
+					got, gotErr := ParseIncremental(result, []TextEdit{edit})
+					if got == nil {
+						t.Fatalf("edit %+v of %q: ParseIncremental() error = %v", edit, source, gotErr)
+					}
+
+					if (wantErr == nil) != (gotErr == nil) || (wantErr != nil && wantErr.Error() != gotErr.Error()) {
+						t.Fatalf("edit %+v of %q: expected error %v, got %v", edit, source, wantErr, gotErr)
+					}
+					if !reflect.DeepEqual(want.AST, got.AST) {
+						t.Fatalf("edit %+v of %q: incremental AST differs from full parse of %q", edit, source, newSource)
+					}
+					if !reflect.DeepEqual(want.JSX, got.JSX) {
+						t.Fatalf("edit %+v: expected JSX config %+v, got %+v", edit, want.JSX, got.JSX)
+					}
+
+					source, result = newSource, got
+				}
+			}
+		})
+	}
+}
+
+func TestParseIncremental_CommentRescannedAsJSXText(t *testing.T) {
+	opts := NewBuilder().WithComment(true).MustBuild()
+	opts.AllowInvalidAST = true
+	opts.JSX = true
+
+	prev, err := Parse(incrementalJSXSource, opts)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	// The opening element turns the leading comment into JSX text, so the
+	// document has no comments left, as in a full parse
+	result, _ := ParseIncremental(prev, []TextEdit{{Start: 0, End: 0, NewText: "<b>"}})
+	want, _ := Parse("<b>"+incrementalJSXSource, opts)
+	if want.AST.Comments != nil || result.AST.Comments != nil {
+		t.Errorf("expected no comments, got %v and %v", want.AST.Comments, result.AST.Comments)
+	}
+}
+
+func TestParseIncremental_PragmaAfterCode(t *testing.T) {
+	opts := NewBuilder().MustBuild()
+	opts.JSX = true
+
+	prev, err := Parse(incrementalJSXSource, opts)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if prev.JSX.Factory != "h" {
+		t.Fatalf("expected factory h, got %q", prev.JSX.Factory)
+	}
+
+	// Code inserted before the pragma comment means it no longer applies
+	result, err := ParseIncremental(prev, []TextEdit{{Start: 0, End: 0, NewText: "let z = 0;\n"}})
+	if err != nil {
+		t.Fatalf("ParseIncremental() error = %v", err)
+	}
+	if result.JSX.Factory != defaultJSXFactory {
+		t.Errorf("expected factory %q, got %q", defaultJSXFactory, result.JSX.Factory)
+	}
+}
+
+func TestParseIncremental_ReusesUnchangedStatements(t *testing.T) {
+	source := "const a = 1;\nconst b = 2;\nconst c = 3;\n"
+	opts := NewBuilder().WithLoc(true).WithRange(true).MustBuild()
+
+	prev, err := Parse(source, opts)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	first, last := prev.AST.Body[0], prev.AST.Body[2]
+
+	// Replace "2" with "200".
+	result, err := ParseIncremental(prev, []TextEdit{{Start: 23, End: 24, NewText: "200"}})
+	if err != nil {
+		t.Fatalf("ParseIncremental() error = %v", err)
+	}
+
+	if len(result.AST.Body) != 3 {
+		t.Fatalf("expected 3 statements, got %d", len(result.AST.Body))
+	}
+	if result.AST.Body[0] != first || result.AST.Body[2] != last {
+		t.Error("expected the first and last statements to be reused")
+	}
+	if ast.Parent(last) != result.AST {
+		t.Error("expected reused statements to be linked to the new program")
+	}
+
+	base := last.(interface{ Base() *ast.BaseNode }).Base()
+	if base.Range == nil || base.Range[0] != 28 {
+		t.Errorf("expected last statement to start at 28, got %v", base.Range)
+	}
+	if base.Loc == nil || base.Loc.Start.Line != 3 {
+		t.Errorf("expected last statement on line 3, got %+v", base.Loc)
+	}
+}
+
+func TestParseIncremental_Errors(t *testing.T) {
+	prev, err := Parse("let a = 1;", NewParseOptions())
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if _, err := ParseIncremental(prev, []TextEdit{{Start: 5, End: 100}}); err == nil {
+		t.Error("expected error for out-of-range edit")
+	}
+	if _, err := ParseIncremental(&Result{}, nil); err == nil {
+		t.Error("expected error for result without incremental state")
+	}
+}
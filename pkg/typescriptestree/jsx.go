@@ -0,0 +1,137 @@
+package typescriptestree
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/kdy1/go-typescript-eslint/internal/program"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
+)
+
+// JSXRuntime specifies how JSX elements are compiled.
+type JSXRuntime string
+
+const (
+	// JSXRuntimeClassic compiles JSX to calls of a factory function, such as
+	// React.createElement, which must be in scope.
+	JSXRuntimeClassic JSXRuntime = "classic"
+
+	// JSXRuntimeAutomatic compiles JSX to calls of functions imported
+	// implicitly from "<importSource>/jsx-runtime".
+	JSXRuntimeAutomatic JSXRuntime = "automatic"
+)
+
+// Defaults used by TypeScript when neither pragmas nor compiler options say otherwise.
+const (
+	defaultJSXFactory         = "React.createElement"
+	defaultJSXFragmentFactory = "React.Fragment"
+	defaultJSXImportSource    = "react"
+)
+
+// JSXConfig describes how the JSX in a file is compiled. It is derived from
+// the @jsx, @jsxFrag, @jsxImportSource and @jsxRuntime pragmas in the comments
+// that precede the file's code, falling back to the jsx, jsxFactory,
+// jsxFragmentFactory and jsxImportSource compiler options.
+type JSXConfig struct {
+	// Runtime is the JSX runtime in effect.
+	Runtime JSXRuntime
+
+	// Factory is the entity name called for each element with the classic
+	// runtime, such as "React.createElement" or "h".
+	Factory string
+
+	// FragmentFactory is the entity name used for fragments with the classic
+	// runtime, such as "React.Fragment".
+	FragmentFactory string
+
+	// ImportSource is the module the automatic runtime imports from, such as
+	// "react" or "preact".
+	ImportSource string
+
+	// Pragmas lists the JSX pragmas found in the file's leading comments,
+	// keyed by name without the '@' (e.g. "jsx", "jsxFrag").
+	Pragmas map[string]string
+}
+
+// ReferencedIdentifiers returns the identifiers that JSX in the file
+// implicitly references and that must therefore be in scope: the root
+// identifiers of Factory and FragmentFactory with the classic runtime.
+// The automatic runtime inserts its own imports, so it references none.
+func (c *JSXConfig) ReferencedIdentifiers() []string {
+	if c == nil || c.Runtime != JSXRuntimeClassic {
+		return nil
+	}
+
+	var names []string
+	for _, entity := range []string{c.Factory, c.FragmentFactory} {
+		root, _, _ := strings.Cut(entity, ".")
+		if root != "" && (len(names) == 0 || names[0] != root) {
+			names = append(names, root)
+		}
+	}
+	return names
+}
+
+// jsxPragmaPattern matches a JSX pragma inside a comment, such as "@jsx h".
+var jsxPragmaPattern = regexp.MustCompile(`@(jsx|jsxFrag|jsxImportSource|jsxRuntime)\s+(\S+)`)
+
+// resolveJSXConfig computes the JSX configuration of a file from its comments
+// and, if prog is not nil, the compiler options of its program. As in the
+// TypeScript compiler, pragmas are only read from the comments that precede
+// the file's first token; the first occurrence of each one wins.
+func resolveJSXConfig(comments []ast.Comment, tokens []ast.Token, prog *program.Program) *JSXConfig {
+	config := &JSXConfig{
+		Runtime:         JSXRuntimeClassic,
+		Factory:         defaultJSXFactory,
+		FragmentFactory: defaultJSXFragmentFactory,
+		ImportSource:    defaultJSXImportSource,
+		Pragmas:         map[string]string{},
+	}
+
+	if prog != nil && prog.Config != nil {
+		options := prog.GetCompilerOptions()
+		if options.JSX == "react-jsx" || options.JSX == "react-jsxdev" {
+			config.Runtime = JSXRuntimeAutomatic
+		}
+		if options.JSXFactory != "" {
+			config.Factory = options.JSXFactory
+		}
+		if options.JSXFragmentFactory != "" {
+			config.FragmentFactory = options.JSXFragmentFactory
+		}
+		if options.JSXImportSource != "" {
+			config.ImportSource = options.JSXImportSource
+		}
+	}
+
+	for _, comment := range comments {
+		if len(tokens) > 0 && comment.Range[0] >= tokens[0].Range[0] {
+			break
+		}
+		for _, match := range jsxPragmaPattern.FindAllStringSubmatch(comment.Value, -1) {
+			if _, ok := config.Pragmas[match[1]]; !ok {
+				config.Pragmas[match[1]] = match[2]
+			}
+		}
+	}
+
+	if factory, ok := config.Pragmas["jsx"]; ok {
+		config.Factory = factory
+		config.Runtime = JSXRuntimeClassic
+	}
+	if fragment, ok := config.Pragmas["jsxFrag"]; ok {
+		config.FragmentFactory = fragment
+	}
+	if source, ok := config.Pragmas["jsxImportSource"]; ok {
+		config.ImportSource = source
+		config.Runtime = JSXRuntimeAutomatic
+	}
+	if runtime, ok := config.Pragmas["jsxRuntime"]; ok {
+		switch JSXRuntime(runtime) {
+		case JSXRuntimeClassic, JSXRuntimeAutomatic:
+			config.Runtime = JSXRuntime(runtime)
+		}
+	}
+
+	return config
+}
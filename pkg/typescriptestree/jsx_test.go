@@ -0,0 +1,112 @@
+package typescriptestree
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/kdy1/go-typescript-eslint/internal/program"
+)
+
+func TestParse_JSXPragmas(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		want       JSXConfig
+		referenced []string
+	}{
+		{
+			name:       "defaults",
+			source:     `const a = <div />;`,
+			want:       JSXConfig{Runtime: JSXRuntimeClassic, Factory: "React.createElement", FragmentFactory: "React.Fragment", ImportSource: "react"},
+			referenced: []string{"React"},
+		},
+		{
+			name:       "classic pragmas",
+			source:     "/** @jsx h */\n/** @jsxFrag Fragment */\nconst a = <></>;",
+			want:       JSXConfig{Runtime: JSXRuntimeClassic, Factory: "h", FragmentFactory: "Fragment", ImportSource: "react"},
+			referenced: []string{"h", "Fragment"},
+		},
+		{
+			name:       "member expression factory",
+			source:     "/** @jsx preact.h @jsxFrag preact.Fragment */\nconst a = <div />;",
+			want:       JSXConfig{Runtime: JSXRuntimeClassic, Factory: "preact.h", FragmentFactory: "preact.Fragment", ImportSource: "react"},
+			referenced: []string{"preact"},
+		},
+		{
+			name:   "import source implies automatic runtime",
+			source: "/** @jsxImportSource preact */\nconst a = <div />;",
+			want:   JSXConfig{Runtime: JSXRuntimeAutomatic, Factory: "React.createElement", FragmentFactory: "React.Fragment", ImportSource: "preact"},
+		},
+		{
+			name:       "explicit runtime wins",
+			source:     "// @jsxImportSource preact\n// @jsxRuntime classic\nconst a = <div />;",
+			want:       JSXConfig{Runtime: JSXRuntimeClassic, Factory: "React.createElement", FragmentFactory: "React.Fragment", ImportSource: "preact"},
+			referenced: []string{"React"},
+		},
+		{
+			name:       "pragma after code is ignored",
+			source:     "import { h } from 'preact';\n/** @jsx h */\nconst a = <div />;",
+			want:       JSXConfig{Runtime: JSXRuntimeClassic, Factory: "React.createElement", FragmentFactory: "React.Fragment", ImportSource: "react"},
+			referenced: []string{"React"},
+		},
+		{
+			name:       "pragma trailing the first statement is ignored",
+			source:     "/** @jsxFrag Fragment */\nconst a = <></>; // @jsx h",
+			want:       JSXConfig{Runtime: JSXRuntimeClassic, Factory: "React.createElement", FragmentFactory: "Fragment", ImportSource: "react"},
+			referenced: []string{"React", "Fragment"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(tt.source, NewBuilder().WithJSX(true).MustBuild())
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			got := result.JSX
+			if got == nil {
+				t.Fatal("expected JSX config")
+			}
+			if got.Runtime != tt.want.Runtime || got.Factory != tt.want.Factory ||
+				got.FragmentFactory != tt.want.FragmentFactory || got.ImportSource != tt.want.ImportSource {
+				t.Errorf("expected %+v, got %+v", tt.want, *got)
+			}
+			if refs := got.ReferencedIdentifiers(); !slices.Equal(refs, tt.referenced) {
+				t.Errorf("expected referenced identifiers %v, got %v", tt.referenced, refs)
+			}
+		})
+	}
+}
+
+func TestParse_JSXConfigRequiresJSX(t *testing.T) {
+	result, err := Parse("/** @jsx h */ const a = 1;", NewParseOptions())
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if result.JSX != nil {
+		t.Errorf("expected no JSX config without JSX enabled, got %+v", result.JSX)
+	}
+}
+
+func TestResolveJSXConfig_CompilerOptions(t *testing.T) {
+	prog := &program.Program{
+		Config: &program.TSConfig{
+			CompilerOptions: program.CompilerOptions{
+				JSX:             "react-jsx",
+				JSXImportSource: "@emotion/react",
+			},
+		},
+	}
+
+	config := resolveJSXConfig(nil, nil, prog)
+	if config.Runtime != JSXRuntimeAutomatic || config.ImportSource != "@emotion/react" {
+		t.Errorf("expected automatic runtime from @emotion/react, got %+v", config)
+	}
+
+	prog.Config.CompilerOptions = program.CompilerOptions{JSX: "react", JSXFactory: "h", JSXFragmentFactory: "Fragment"}
+	config = resolveJSXConfig(nil, nil, prog)
+	if config.Runtime != JSXRuntimeClassic || config.Factory != "h" || config.FragmentFactory != "Fragment" {
+		t.Errorf("expected classic runtime with h and Fragment, got %+v", config)
+	}
+}
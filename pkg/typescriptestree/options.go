@@ -49,6 +49,26 @@ type CacheLifetime struct {
 	Glob *CacheDurationSeconds `json:"glob,omitempty"`
 }
 
+// ProjectServiceOptions configures the project service used when
+// ProjectService is set.
+type ProjectServiceOptions struct {
+	// AllowDefaultProject lists globs, relative to TSConfigRootDir, of files
+	// that are parsed with the default project when no tsconfig includes them.
+	// The globs may not be "*" or contain "**".
+	AllowDefaultProject []string `json:"allowDefaultProject,omitempty"`
+
+	// DefaultProject is the path, relative to TSConfigRootDir, of the tsconfig
+	// whose compiler options the default project uses.
+	// Default: the default compiler options
+	DefaultProject string `json:"defaultProject,omitempty"`
+
+	// MaximumDefaultProjectFileMatchCount is the number of files that may be
+	// parsed with the default project before an error is returned, as having
+	// many files in it makes linting slow.
+	// Default: 8
+	MaximumDefaultProjectFileMatchCount int `json:"maximumDefaultProjectFileMatchCount_THIS_WILL_SLOW_DOWN_LINTING,omitempty"`
+}
+
 // ParseOptions configures the behavior of the TypeScript parser.
 // It matches the options available in @typescript-eslint/typescript-estree.
 type ParseOptions struct {
@@ -100,6 +120,16 @@ type ParseOptions struct {
 	// Default: logs to stderr
 	LoggerFn LoggerFn `json:"-"`
 
+	// OffsetEncoding selects the unit in which `range` offsets and `loc` columns
+	// are measured: "utf-16" (as in JavaScript and ESLint), "byte" or "rune".
+	// Default: "utf-16"
+	OffsetEncoding OffsetEncoding `json:"offsetEncoding,omitempty"`
+
+	// OmitParents leaves the parent links of AST nodes unset, saving memory in
+	// batch jobs that never look up a node's parent or ancestors.
+	// Default: false
+	OmitParents bool `json:"omitParents,omitempty"`
+
 	// Range indicates whether to add [start, end] range information to AST nodes.
 	// When enabled, nodes will have a `range` property with character offsets.
 	// Default: false
@@ -162,6 +192,10 @@ type ParseAndGenerateServicesOptions struct {
 	// Default: false
 	ProjectService bool `json:"projectService,omitempty"`
 
+	// ProjectServiceOptions configures the project service.
+	// Default: nil
+	ProjectServiceOptions *ProjectServiceOptions `json:"projectServiceOptions,omitempty"`
+
 	// TSConfigRootDir specifies the root directory for relative tsconfig paths.
 	// When set, paths in the `project` option are resolved relative to this directory.
 	// Default: current working directory
@@ -192,6 +226,7 @@ func NewParseOptions() *ParseOptions {
 		JSX:                                false,
 		Loc:                                false,
 		LoggerFn:                           defaultLogger,
+		OffsetEncoding:                     OffsetEncodingUTF16,
 		Range:                              false,
 		Tokens:                             false,
 		SuppressDeprecatedPropertyWarnings: false,
@@ -214,6 +249,7 @@ func NewParseAndGenerateServicesOptions() *ParseAndGenerateServicesOptions {
 		Project:                                     nil,
 		ProjectFolderIgnoreList:                     []string{"**/node_modules/**"},
 		ProjectService:                              false,
+		ProjectServiceOptions:                       nil,
 		TSConfigRootDir:                             "",
 		Programs:                                    nil,
 		WarnOnUnsupportedTypeScriptVersion:          &warnOnUnsupported,
@@ -254,6 +290,11 @@ func (o *ParseOptions) Validate() error {
 		return fmt.Errorf("invalid jsDocParsingMode: must be 'all', 'none', or 'type-info', got %q", o.JSDocParsingMode)
 	}
 
+	// Validate OffsetEncoding
+	if !validOffsetEncoding(o.OffsetEncoding) {
+		return fmt.Errorf("invalid offsetEncoding: must be 'utf-16', 'byte', or 'rune', got %q", o.OffsetEncoding)
+	}
+
 	return nil
 }
 
@@ -276,6 +317,13 @@ func (o *ParseAndGenerateServicesOptions) Validate() error {
 		return fmt.Errorf("cannot use both 'project' and 'projectService' options")
 	}
 
+	// Validate ProjectServiceOptions
+	if o.ProjectServiceOptions != nil {
+		if err := validateDefaultProjectForFilesGlob(o.ProjectServiceOptions.AllowDefaultProject); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -363,6 +411,18 @@ func (b *ParseOptionsBuilder) WithLoggerFn(fn LoggerFn) *ParseOptionsBuilder {
 	return b
 }
 
+// WithOffsetEncoding sets the unit used for range offsets and loc columns.
+func (b *ParseOptionsBuilder) WithOffsetEncoding(enc OffsetEncoding) *ParseOptionsBuilder {
+	b.opts.OffsetEncoding = enc
+	return b
+}
+
+// WithOmitParents disables or enables parent links on AST nodes.
+func (b *ParseOptionsBuilder) WithOmitParents(omit bool) *ParseOptionsBuilder {
+	b.opts.OmitParents = omit
+	return b
+}
+
 // WithRange enables or disables range information.
 func (b *ParseOptionsBuilder) WithRange(rang bool) *ParseOptionsBuilder {
 	b.opts.Range = rang
@@ -586,6 +646,16 @@ func (b *ParseAndGenerateServicesOptionsBuilder) WithProjectService(
 	return b
 }
 
+// WithProjectServiceOptions enables the TypeScript project service with the
+// given options.
+func (b *ParseAndGenerateServicesOptionsBuilder) WithProjectServiceOptions(
+	options *ProjectServiceOptions,
+) *ParseAndGenerateServicesOptionsBuilder {
+	b.opts.ProjectService = true
+	b.opts.ProjectServiceOptions = options
+	return b
+}
+
 // WithTSConfigRootDir sets the root directory for tsconfig paths.
 func (b *ParseAndGenerateServicesOptionsBuilder) WithTSConfigRootDir(
 	dir string,
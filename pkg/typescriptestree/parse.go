@@ -3,13 +3,11 @@ package typescriptestree
 import (
 	"errors"
 	"fmt"
-	"path/filepath"
-	"time"
 
-	"github.com/kdy1/go-typescript-eslint/internal/ast"
 	"github.com/kdy1/go-typescript-eslint/internal/converter"
 	"github.com/kdy1/go-typescript-eslint/internal/parser"
 	"github.com/kdy1/go-typescript-eslint/internal/program"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 )
 
 // ErrNotImplemented is returned when a feature is not yet implemented.
@@ -23,6 +21,14 @@ type Result struct {
 	// Services provides TypeScript language services for type-aware operations.
 	// This is only populated when using ParseAndGenerateServices.
 	Services *Services
+
+	// JSX describes how JSX in the file is compiled, from its pragmas and the
+	// program's compiler options. It is only populated when JSX is enabled.
+	JSX *JSXConfig
+
+	// incremental lets ParseIncremental reuse this result. It is only set by
+	// Parse and ParseIncremental.
+	incremental *incrementalState
 }
 
 // Parse parses TypeScript source code into an ESTree-compatible AST.
@@ -45,39 +51,28 @@ func Parse(source string, opts *ParseOptions) (*Result, error) {
 		opts = NewBuilder().MustBuild()
 	}
 
-	// Create parser with source code
-	p := parser.New(source)
-
-	// Configure parser from options
-	if opts.SourceType != "" {
-		p.SetSourceType(string(opts.SourceType))
-	}
-
-	if opts.JSX {
-		p.SetJSXEnabled(true)
-	}
-
 	// Parse the source into AST
-	astNode, err := p.Parse()
+	program, spans, err := newParser(source, opts).ParseUntil(nil)
 	if err != nil && !opts.AllowInvalidAST {
 		return nil, fmt.Errorf("parse error: %w", err)
 	}
 
-	// Cast to *ast.Program
-	program, ok := astNode.(*ast.Program)
-	if !ok {
-		return nil, fmt.Errorf("parser returned non-Program node")
-	}
-
 	// Apply converter to ensure proper ESTree format
 	converter := converter.NewConverter(source, &converter.Options{
 		PreserveNodeMaps:                   false, // Not needed for basic parsing
 		UseJSDocParsingMode:                opts.JSDocParsingMode != JSDocParsingModeNone,
 		SuppressDeprecatedPropertyWarnings: opts.SuppressDeprecatedPropertyWarnings,
+		SetParents:                         !opts.OmitParents,
 	})
 
 	estreeProgram := converter.ConvertProgram(program)
 
+	// Pragmas are read from comments, so resolve them before filtering
+	var jsxConfig *JSXConfig
+	if opts.JSX {
+		jsxConfig = resolveJSXConfig(estreeProgram.Comments, estreeProgram.Tokens, nil)
+	}
+
 	// Filter comments and tokens based on options
 	if !opts.Comment {
 		estreeProgram.Comments = nil
@@ -85,21 +80,33 @@ func Parse(source string, opts *ParseOptions) (*Result, error) {
 	if !opts.Tokens {
 		estreeProgram.Tokens = nil
 	}
-	if !opts.Loc {
-		// Clear location information if not requested
-		clearLocationInfo(estreeProgram)
-	}
-	if !opts.Range {
-		// Clear range information if not requested
-		clearRangeInfo(estreeProgram)
-	}
+
+	// Convert byte ranges to the requested encoding, adding loc if requested
+	applyPositions(estreeProgram, source, opts)
 
 	return &Result{
-		AST:      estreeProgram,
-		Services: nil, // No services for basic parsing
+		AST:         estreeProgram,
+		Services:    nil, // No services for basic parsing
+		JSX:         jsxConfig,
+		incremental: newIncrementalState(source, opts, program, spans, err),
 	}, err // Return error if AllowInvalidAST is true
 }
 
+// newParser creates a parser for source configured from opts.
+func newParser(source string, opts *ParseOptions) *parser.Parser {
+	p := parser.New(source)
+
+	if opts.SourceType != "" {
+		p.SetSourceType(string(opts.SourceType))
+	}
+
+	if opts.JSX {
+		p.SetJSXEnabled(true)
+	}
+
+	return p
+}
+
 // ParseAndGenerateServices parses TypeScript source code and generates
 // TypeScript program services for type-aware linting and analysis.
 //
@@ -123,48 +130,35 @@ func ParseAndGenerateServices(source string, opts *ParseAndGenerateServicesOptio
 	}
 
 	// Determine which program cache to use
-	var cache *program.ProgramCache
-	if opts.CacheLifetime != nil && opts.CacheLifetime.Glob != nil {
-		// Use custom cache with specified lifetime
-		cache = program.NewProgramCache(time.Duration(*opts.CacheLifetime.Glob) * time.Second)
-	} else {
-		// Use global cache
-		cache = program.GlobalCache
-	}
+	cache := programCache(opts)
 
 	// Create or retrieve TypeScript program
 	var prog *program.Program
 	var err error
 
 	if len(opts.Programs) > 0 {
-		// Use provided program instances
-		if len(opts.Programs) > 0 {
-			prog = opts.Programs[0]
+		// Use the provided program instance that includes the file
+		prog, err = selectProgram(opts)
+		if err != nil {
+			return nil, err
 		}
 	} else if opts.ProjectService {
-		// Use project service (not yet implemented)
-		return nil, fmt.Errorf("ProjectService is not yet implemented")
-	} else if len(opts.Project) > 0 {
-		// Create program from tsconfig.json paths
-		projectPath := opts.Project[0] // Use first project path
-		if opts.TSConfigRootDir != "" {
-			projectPath = filepath.Join(opts.TSConfigRootDir, projectPath)
-		}
-
-		programOpts := &program.ProgramOptions{
-			TSConfigPath: projectPath,
-			RootDir:      opts.TSConfigRootDir,
+		// Use the project of the nearest tsconfig.json that includes the
+		// file, or the default project
+		prog, err = useProgramFromProjectService(opts)
+		if err != nil {
+			return nil, err
 		}
-
-		prog, err = cache.GetOrCreate(programOpts)
+	} else if len(opts.Project) > 0 {
+		// Create programs from tsconfig.json paths and globs, and use the
+		// first whose project, or a project it references, includes the file
+		prog, err = selectProjectProgram(opts, cache)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create TypeScript program: %w", err)
+			return nil, err
 		}
-	} else {
-		// No type-aware configuration provided
-		// Fall back to basic parsing without services
-		return Parse(source, &opts.ParseOptions)
 	}
+	// Without a type-aware configuration, prog stays nil and the services
+	// have no program, as typescript-estree returns them
 
 	// Create parser with source code
 	p := parser.New(source)
@@ -196,10 +190,17 @@ func ParseAndGenerateServices(source string, opts *ParseAndGenerateServicesOptio
 		PreserveNodeMaps:                   preserveNodeMaps,
 		UseJSDocParsingMode:                opts.JSDocParsingMode != JSDocParsingModeNone,
 		SuppressDeprecatedPropertyWarnings: opts.SuppressDeprecatedPropertyWarnings,
+		SetParents:                         !opts.OmitParents,
 	})
 
 	estreeProgram := conv.ConvertProgram(program)
 
+	// Pragmas are read from comments, so resolve them before filtering
+	var jsxConfig *JSXConfig
+	if opts.JSX {
+		jsxConfig = resolveJSXConfig(estreeProgram.Comments, estreeProgram.Tokens, prog)
+	}
+
 	// Filter comments and tokens based on options
 	if !opts.Comment {
 		estreeProgram.Comments = nil
@@ -207,12 +208,9 @@ func ParseAndGenerateServices(source string, opts *ParseAndGenerateServicesOptio
 	if !opts.Tokens {
 		estreeProgram.Tokens = nil
 	}
-	if !opts.Loc {
-		clearLocationInfo(estreeProgram)
-	}
-	if !opts.Range {
-		clearRangeInfo(estreeProgram)
-	}
+
+	// Convert byte ranges to the requested encoding, adding loc if requested
+	applyPositions(estreeProgram, source, &opts.ParseOptions)
 
 	// Create ParserServices with node mappings
 	services := NewParserServices(prog)
@@ -229,37 +227,8 @@ func ParseAndGenerateServices(source string, opts *ParseAndGenerateServicesOptio
 	result := &Result{
 		AST:      estreeProgram,
 		Services: services,
+		JSX:      jsxConfig,
 	}
 
 	return result, err // Return error if AllowInvalidAST is true
 }
-
-// clearLocationInfo recursively removes location information from AST nodes.
-func clearLocationInfo(node ast.Node) {
-	if node == nil {
-		return
-	}
-
-	// Use the ast traversal utilities to walk the tree
-	ast.Walk(node, ast.VisitorFunc(func(n ast.Node) bool {
-		// Location info is typically in the BaseNode, which is embedded
-		// For now, we'll skip this optimization and just leave loc info
-		// This is a performance optimization, not a correctness requirement
-		return true
-	}))
-}
-
-// clearRangeInfo recursively removes range information from AST nodes.
-func clearRangeInfo(node ast.Node) {
-	if node == nil {
-		return
-	}
-
-	// Use the ast traversal utilities to walk the tree
-	ast.Walk(node, ast.VisitorFunc(func(n ast.Node) bool {
-		// Range info is typically in the BaseNode, which is embedded
-		// For now, we'll skip this optimization and just leave range info
-		// This is a performance optimization, not a correctness requirement
-		return true
-	}))
-}
@@ -3,6 +3,7 @@ package typescriptestree_test
 import (
 	"testing"
 
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 	"github.com/kdy1/go-typescript-eslint/pkg/typescriptestree"
 )
 
@@ -295,6 +296,72 @@ func TestParse_FilePath_AutoDetectJSX(t *testing.T) {
 	}
 }
 
+func TestParse_ParentLinks(t *testing.T) {
+	source := `f(a.b);`
+
+	result, err := typescriptestree.Parse(source, nil)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	property := ast.FindFirst(result.AST, func(n ast.Node) bool {
+		id, ok := n.(*ast.Identifier)
+		return ok && id.Name == "b"
+	})
+	if property == nil {
+		t.Fatal("expected to find identifier b")
+	}
+
+	var types []string
+	for _, ancestor := range ast.Ancestors(property) {
+		types = append(types, ancestor.Type())
+	}
+	want := []string{"Program", "ExpressionStatement", "CallExpression", "MemberExpression"}
+	if len(types) != len(want) {
+		t.Fatalf("expected ancestors %v, got %v", want, types)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("expected ancestors %v, got %v", want, types)
+			break
+		}
+	}
+
+	result, err = typescriptestree.Parse(source, typescriptestree.NewBuilder().WithOmitParents(true).MustBuild())
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if parent := ast.Parent(result.AST.Body[0]); parent != nil {
+		t.Errorf("expected no parent links with OmitParents, got %v", parent.Type())
+	}
+}
+
+func TestParse_ParentLinksOfTypeAnnotations(t *testing.T) {
+	result, err := typescriptestree.Parse(`function f(a: number, b: string = "x") {}`, nil)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	annotations := ast.FindByType(result.AST, "TSTypeAnnotation")
+	if len(annotations) != 2 {
+		t.Fatalf("expected 2 type annotations, got %d", len(annotations))
+	}
+	for _, annotation := range annotations {
+		parent, ok := ast.Parent(annotation).(*ast.Identifier)
+		if !ok {
+			t.Errorf("expected the parameter to be the parent of its type annotation, got %v", ast.Parent(annotation))
+			continue
+		}
+		if parent.TypeAnnotation != annotation {
+			t.Errorf("expected %s to be annotated by its child", parent.Name)
+		}
+		typ := annotation.(*ast.TSTypeAnnotation).TypeAnnotation
+		if ast.Parent(typ) != annotation {
+			t.Errorf("expected the annotation to be the parent of %s", typ.Type())
+		}
+	}
+}
+
 func BenchmarkParse(b *testing.B) {
 	source := `
 		const x: number = 42;
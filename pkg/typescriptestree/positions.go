@@ -0,0 +1,219 @@
+package typescriptestree
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
+)
+
+// OffsetEncoding specifies the unit in which source offsets and columns are measured.
+type OffsetEncoding string
+
+const (
+	// OffsetEncodingUTF16 measures offsets in UTF-16 code units, matching
+	// JavaScript string indices and the ranges reported by typescript-estree and ESLint.
+	OffsetEncodingUTF16 OffsetEncoding = "utf-16"
+
+	// OffsetEncodingByte measures offsets in bytes of the UTF-8 source.
+	OffsetEncodingByte OffsetEncoding = "byte"
+
+	// OffsetEncodingRune measures offsets in Unicode code points.
+	OffsetEncodingRune OffsetEncoding = "rune"
+)
+
+// Indices into the per-encoding arrays of multiByteRune.
+const (
+	byteUnit = iota
+	utf16Unit
+	runeUnit
+)
+
+// unitIndex maps an encoding to its index in multiByteRune. The empty
+// encoding is treated as the UTF-16 default.
+func unitIndex(enc OffsetEncoding) int {
+	switch enc {
+	case OffsetEncodingByte:
+		return byteUnit
+	case OffsetEncodingRune:
+		return runeUnit
+	default:
+		return utf16Unit
+	}
+}
+
+// validOffsetEncoding reports whether enc is empty or a known encoding.
+func validOffsetEncoding(enc OffsetEncoding) bool {
+	switch enc {
+	case "", OffsetEncodingUTF16, OffsetEncodingByte, OffsetEncodingRune:
+		return true
+	default:
+		return false
+	}
+}
+
+// multiByteRune records where a rune that is not a single byte starts and how
+// long it is in each encoding. ASCII runs between such runes have the same
+// length in every encoding, so they need no entries.
+type multiByteRune struct {
+	start [3]int
+	size  [3]int
+}
+
+// SourceOffsets converts offsets within a source text between encodings and
+// computes line/column positions. It is safe for concurrent use once created.
+type SourceOffsets struct {
+	runes      []multiByteRune
+	lineStarts []int // byte offsets of the first character of each line
+}
+
+// NewSourceOffsets indexes source for offset conversion.
+func NewSourceOffsets(source string) *SourceOffsets {
+	s := &SourceOffsets{
+		lineStarts: []int{0},
+	}
+
+	var utf16Off, runeOff int
+	for i := 0; i < len(source); {
+		ch, size := utf8.DecodeRuneInString(source[i:])
+
+		width := 1
+		if ch > 0xFFFF {
+			width = 2
+		}
+		if size > 1 {
+			s.runes = append(s.runes, multiByteRune{
+				start: [3]int{i, utf16Off, runeOff},
+				size:  [3]int{size, width, 1},
+			})
+		}
+
+		switch {
+		case ch == '\r' && i+1 < len(source) && source[i+1] == '\n':
+			// \r\n is a single line break; the line starts after the \n.
+		case ch == '\n' || ch == '\r' || ch == '\u2028' || ch == '\u2029':
+			s.lineStarts = append(s.lineStarts, i+size)
+		}
+
+		i += size
+		utf16Off += width
+		runeOff++
+	}
+
+	return s
+}
+
+// Convert translates an offset from one encoding to another. An offset that
+// falls inside a multi-unit character is snapped to the start of that character.
+func (s *SourceOffsets) Convert(offset int, from, to OffsetEncoding) int {
+	f, t := unitIndex(from), unitIndex(to)
+	if f == t {
+		return offset
+	}
+
+	// Find the last multi-byte rune starting before offset.
+	i := sort.Search(len(s.runes), func(i int) bool {
+		return s.runes[i].start[f] >= offset
+	})
+	if i == 0 {
+		return offset
+	}
+
+	r := s.runes[i-1]
+	if offset < r.start[f]+r.size[f] {
+		return r.start[t]
+	}
+	return r.start[t] + r.size[t] + offset - r.start[f] - r.size[f]
+}
+
+// Position returns the 1-based line and 0-based column of offset, with both
+// the offset and the column measured in enc.
+func (s *SourceOffsets) Position(offset int, enc OffsetEncoding) (line, column int) {
+	return s.lineColumn(s.Convert(offset, enc, OffsetEncodingByte), enc)
+}
+
+// lineColumn returns the line and the column in enc of a byte offset.
+func (s *SourceOffsets) lineColumn(byteOffset int, enc OffsetEncoding) (line, column int) {
+	line = sort.Search(len(s.lineStarts), func(i int) bool {
+		return s.lineStarts[i] > byteOffset
+	})
+	lineStart := s.lineStarts[line-1]
+
+	return line, s.Convert(byteOffset, OffsetEncodingByte, enc) - s.Convert(lineStart, OffsetEncodingByte, enc)
+}
+
+// Offset returns the offset in enc of a 1-based line and 0-based column
+// measured in enc. It returns an error if the line does not exist.
+func (s *SourceOffsets) Offset(line, column int, enc OffsetEncoding) (int, error) {
+	if line < 1 || line > len(s.lineStarts) {
+		return 0, fmt.Errorf("line %d out of range [1, %d]", line, len(s.lineStarts))
+	}
+	return s.Convert(s.lineStarts[line-1], OffsetEncodingByte, enc) + column, nil
+}
+
+// ConvertOffset translates a single offset in source from one encoding to
+// another. Use NewSourceOffsets when converting many offsets in the same source.
+func ConvertOffset(source string, offset int, from, to OffsetEncoding) int {
+	return NewSourceOffsets(source).Convert(offset, from, to)
+}
+
+// applyPositions rewrites the byte ranges produced by the parser into the
+// requested encoding and fills in loc, dropping whichever of the two the
+// options did not ask for.
+func applyPositions(program *ast.Program, source string, opts *ParseOptions) {
+	w := positionWriter{offsets: NewSourceOffsets(source), opts: opts}
+
+	w.node(program)
+	for i := range program.Comments {
+		program.Comments[i].Range, program.Comments[i].Loc = w.convert(program.Comments[i].Range)
+	}
+	for i := range program.Tokens {
+		program.Tokens[i].Range, program.Tokens[i].Loc = w.convert(program.Tokens[i].Range)
+	}
+}
+
+// positionWriter converts byte ranges of one source into the positions
+// requested by opts.
+type positionWriter struct {
+	offsets *SourceOffsets
+	opts    *ParseOptions
+}
+
+// convert returns the range and loc to report for the byte range r.
+func (w positionWriter) convert(r *ast.Range) (*ast.Range, *ast.SourceLocation) {
+	if r == nil {
+		return nil, nil
+	}
+	enc := w.opts.OffsetEncoding
+
+	var loc *ast.SourceLocation
+	if w.opts.Loc {
+		loc = &ast.SourceLocation{}
+		loc.Start.Line, loc.Start.Column = w.offsets.lineColumn(r[0], enc)
+		loc.End.Line, loc.End.Column = w.offsets.lineColumn(r[1], enc)
+	}
+
+	if !w.opts.Range {
+		return nil, loc
+	}
+	return &ast.Range{
+		w.offsets.Convert(r[0], OffsetEncodingByte, enc),
+		w.offsets.Convert(r[1], OffsetEncodingByte, enc),
+	}, loc
+}
+
+// node converts the positions of root and every node below it. A node can be
+// reached twice, as with the shared identifier of "import { a }", so each one
+// is converted only the first time.
+func (w positionWriter) node(root ast.Node) {
+	seen := map[*ast.BaseNode]bool{}
+	ast.Walk(root, ast.VisitorFunc(func(n ast.Node) bool {
+		if b, ok := n.(interface{ Base() *ast.BaseNode }); ok && !seen[b.Base()] {
+			base := b.Base()
+			seen[base] = true
+			base.Range, base.Loc = w.convert(base.Range)
+		}
+		return true
+	}))
+}
@@ -0,0 +1,166 @@
+package typescriptestree
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
+)
+
+func TestSourceOffsets_Convert(t *testing.T) {
+	// "é" is 2 bytes and 1 UTF-16 unit, "😀" is 4 bytes and 2 UTF-16 units.
+	source := "aé😀b"
+	offsets := NewSourceOffsets(source)
+
+	tests := []struct {
+		name   string
+		offset int
+		from   OffsetEncoding
+		to     OffsetEncoding
+		want   int
+	}{
+		{"byte to utf-16 before non-ascii", 1, OffsetEncodingByte, OffsetEncodingUTF16, 1},
+		{"byte to utf-16 after two-byte rune", 3, OffsetEncodingByte, OffsetEncodingUTF16, 2},
+		{"byte to utf-16 after surrogate pair", 7, OffsetEncodingByte, OffsetEncodingUTF16, 4},
+		{"byte to utf-16 at end", 8, OffsetEncodingByte, OffsetEncodingUTF16, 5},
+		{"byte to rune at end", 8, OffsetEncodingByte, OffsetEncodingRune, 4},
+		{"utf-16 to byte", 4, OffsetEncodingUTF16, OffsetEncodingByte, 7},
+		{"utf-16 inside surrogate pair snaps to start", 3, OffsetEncodingUTF16, OffsetEncodingByte, 3},
+		{"rune to utf-16", 3, OffsetEncodingRune, OffsetEncodingUTF16, 4},
+		{"same encoding", 5, OffsetEncodingByte, OffsetEncodingByte, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := offsets.Convert(tt.offset, tt.from, tt.to); got != tt.want {
+				t.Errorf("Convert(%d, %s, %s) = %d, want %d", tt.offset, tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+
+	if got := ConvertOffset(source, 7, OffsetEncodingByte, OffsetEncodingUTF16); got != 4 {
+		t.Errorf("ConvertOffset() = %d, want 4", got)
+	}
+}
+
+func TestSourceOffsets_Position(t *testing.T) {
+	source := "// 中文\r\nlet 😀 = 1;\nx"
+	offsets := NewSourceOffsets(source)
+
+	line, column := offsets.Position(len("// 中文\r\nlet 😀 = "), OffsetEncodingByte)
+	if line != 2 || column != 11 {
+		t.Errorf("byte Position() = %d:%d, want 2:11", line, column)
+	}
+
+	utf16 := offsets.Convert(len("// 中文\r\nlet 😀 = "), OffsetEncodingByte, OffsetEncodingUTF16)
+	line, column = offsets.Position(utf16, OffsetEncodingUTF16)
+	if line != 2 || column != 9 {
+		t.Errorf("utf-16 Position() = %d:%d, want 2:9", line, column)
+	}
+
+	offset, err := offsets.Offset(3, 0, OffsetEncodingUTF16)
+	if err != nil {
+		t.Fatalf("Offset() error = %v", err)
+	}
+	if offset != 19 {
+		t.Errorf("Offset(3, 0) = %d, want 19", offset)
+	}
+
+	if _, err := offsets.Offset(4, 0, OffsetEncodingUTF16); err == nil {
+		t.Error("expected error for out-of-range line")
+	}
+}
+
+func TestParse_OffsetEncoding(t *testing.T) {
+	source := "const s = '😀é';\nlet y = s;"
+
+	tests := []struct {
+		encoding OffsetEncoding
+		rang     ast.Range
+		column   int
+	}{
+		{OffsetEncodingUTF16, ast.Range{25, 26}, 8},
+		{OffsetEncodingByte, ast.Range{28, 29}, 8},
+		{OffsetEncodingRune, ast.Range{24, 25}, 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.encoding), func(t *testing.T) {
+			opts := NewBuilder().
+				WithLoc(true).
+				WithRange(true).
+				WithOffsetEncoding(tt.encoding).
+				MustBuild()
+
+			result, err := Parse(source, opts)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			ids := ast.FindAll(result.AST, func(n ast.Node) bool {
+				id, ok := n.(*ast.Identifier)
+				return ok && id.Name == "s"
+			})
+			if len(ids) != 2 {
+				t.Fatalf("expected 2 identifiers s, got %d", len(ids))
+			}
+
+			id := ids[1].(*ast.Identifier)
+			if id.Range == nil || *id.Range != tt.rang {
+				t.Errorf("expected range %v, got %v", tt.rang, id.Range)
+			}
+			if id.Loc == nil || id.Loc.Start.Line != 2 || id.Loc.Start.Column != tt.column {
+				t.Errorf("expected loc 2:%d, got %+v", tt.column, id.Loc)
+			}
+		})
+	}
+}
+
+func TestParse_OmitsPositionsByDefault(t *testing.T) {
+	result, err := Parse("let x = 1;", NewParseOptions())
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if result.AST.Range != nil || result.AST.Loc != nil {
+		t.Errorf("expected no range or loc, got %v %v", result.AST.Range, result.AST.Loc)
+	}
+}
+
+func TestParseOptions_ValidateOffsetEncoding(t *testing.T) {
+	opts := NewParseOptions()
+	opts.OffsetEncoding = "utf-8"
+	if err := opts.Validate(); err == nil {
+		t.Error("expected error for unknown offset encoding")
+	}
+}
+
+func TestParse_OffsetEncodingTypeAnnotations(t *testing.T) {
+	source := "\"😀\"; function f(a: number, b: string = 'é'): void {}\nlet c: number[] = [];"
+	annotations := []string{": number,", ": string =", ": void", ": number[]"}
+
+	opts := NewBuilder().WithLoc(true).WithRange(true).MustBuild()
+	result, err := Parse(source, opts)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	found := ast.FindAll(result.AST, func(n ast.Node) bool {
+		_, ok := n.(*ast.TSTypeAnnotation)
+		return ok
+	})
+	if len(found) != len(annotations) {
+		t.Fatalf("expected %d type annotations, got %d", len(annotations), len(found))
+	}
+	for i, node := range found {
+		annotation := node.(*ast.TSTypeAnnotation)
+		// The range of an annotation starts at its type, after the ": "
+		start := ConvertOffset(source, strings.Index(source, annotations[i])+2, OffsetEncodingByte, OffsetEncodingUTF16)
+		if annotation.Range == nil || annotation.Range[0] != start {
+			t.Errorf("annotation %d: expected range to start at %d, got %v", i, start, annotation.Range)
+		}
+		if annotation.Loc == nil || annotation.Loc.Start.Line == 1 && annotation.Loc.Start.Column != start {
+			t.Errorf("annotation %d: expected loc at column %d, got %+v", i, start, annotation.Loc)
+		}
+	}
+}
@@ -0,0 +1,405 @@
+package typescriptestree
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kdy1/go-typescript-eslint/internal/program"
+)
+
+// ErrFileNotInProject is returned, wrapped, by ParseAndGenerateServices when
+// the file being parsed is not included in any of the configured projects or
+// programs.
+var ErrFileNotInProject = errors.New("file not included in any project")
+
+// defaultProjectFolderIgnoreList is the ProjectFolderIgnoreList used when the
+// option is nil.
+var defaultProjectFolderIgnoreList = []string{"**/node_modules/**"}
+
+// defaultGlobCacheLifetime is how long the files that project globs match are
+// reused when CacheLifetime.Glob is not set, as in typescript-estree.
+const defaultGlobCacheLifetime = 30 * time.Second
+
+// globCache holds the files that project globs matched, keyed by the root
+// directory, the patterns and the ignore list, so that parsing a file does
+// not walk the root directory every time.
+var globCache = struct {
+	entries map[string]globCacheEntry
+	mu      sync.Mutex
+}{entries: make(map[string]globCacheEntry)}
+
+// globCacheEntry is the result of a glob search and when it was made.
+type globCacheEntry struct {
+	matches   []string
+	createdAt time.Time
+}
+
+// defaultFileExtensions are the extensions that projects include without
+// extraFileExtensions.
+var defaultFileExtensions = []string{".ts", ".tsx", ".js", ".jsx", ".mjs", ".mts", ".cjs", ".cts"}
+
+// fileNotInProjectError is the error for a file that no project includes. Its
+// message explains how to fix the configuration, as typescript-estree's does.
+type fileNotInProjectError struct {
+	message string
+}
+
+func (e *fileNotInProjectError) Error() string {
+	return e.message
+}
+
+func (e *fileNotInProjectError) Unwrap() error {
+	return ErrFileNotInProject
+}
+
+// absoluteFilePath returns the absolute path of the file being parsed, which
+// is relative to TSConfigRootDir, or to the working directory without it.
+func absoluteFilePath(opts *ParseAndGenerateServicesOptions) string {
+	filePath := opts.FilePath
+	if !filepath.IsAbs(filePath) && opts.TSConfigRootDir != "" {
+		filePath = filepath.Join(opts.TSConfigRootDir, filePath)
+	}
+	if abs, err := filepath.Abs(filePath); err == nil {
+		return abs
+	}
+	return filePath
+}
+
+// tsconfigRootDir returns the absolute TSConfigRootDir, or the working
+// directory if it is not set.
+func tsconfigRootDir(opts *ParseAndGenerateServicesOptions) string {
+	dir, err := filepath.Abs(opts.TSConfigRootDir)
+	if err != nil {
+		return opts.TSConfigRootDir
+	}
+	return dir
+}
+
+// selectProgram returns the first of the provided programs whose projects,
+// or those they reference, include the file being parsed. Without a file
+// path, it returns the first program.
+func selectProgram(opts *ParseAndGenerateServicesOptions) (*program.Program, error) {
+	if opts.FilePath == "" {
+		return opts.Programs[0], nil
+	}
+
+	filePath := absoluteFilePath(opts)
+	for _, prog := range opts.Programs {
+		if owner := prog.ProjectForFile(filePath); owner != nil {
+			return owner, nil
+		}
+	}
+
+	rel, err := filepath.Rel(tsconfigRootDir(opts), filePath)
+	if err != nil {
+		rel = filePath
+	}
+	return nil, &fileNotInProjectError{message: strings.Join([]string{
+		`"parserOptions.programs" has been provided for @typescript-eslint/parser.`,
+		"The file was not found in any of the provided program instance(s): " + rel,
+	}, "\n")}
+}
+
+// selectProjectProgram returns the program of the first project, in the
+// order of resolveProjectList, that includes the file being parsed, itself
+// or through its project references. Programs are created, or taken from
+// cache, only as far as needed. Without a file path, it returns the program
+// of the first project.
+func selectProjectProgram(opts *ParseAndGenerateServicesOptions, cache *program.ProgramCache) (*program.Program, error) {
+	projects, err := resolveProjectList(opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(projects) == 0 {
+		return nil, fmt.Errorf("no tsconfig files matched parserOptions.project: %s", strings.Join(opts.Project, ", "))
+	}
+
+	filePath := absoluteFilePath(opts)
+	for _, project := range projects {
+		prog, err := cache.GetOrCreate(&program.ProgramOptions{
+			TSConfigPath:        project,
+			RootDir:             opts.TSConfigRootDir,
+			ExtraFileExtensions: opts.ExtraFileExtensions,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create TypeScript program: %w", err)
+		}
+		if opts.FilePath == "" {
+			return prog, nil
+		}
+		if owner := prog.ProjectForFile(filePath); owner != nil {
+			return owner, nil
+		}
+	}
+
+	return nil, projectFileError(opts, filePath, projects)
+}
+
+// projectFileError returns the error for a file that none of projects
+// includes, worded as typescript-estree words it.
+func projectFileError(opts *ParseAndGenerateServicesOptions, filePath string, projects []string) error {
+	rootDir := tsconfigRootDir(opts)
+	describe := func(path string) string {
+		rel, err := filepath.Rel(rootDir, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return path
+		}
+		return "<tsconfigRootDir>/" + filepath.ToSlash(rel)
+	}
+
+	described := make([]string, len(projects))
+	for i, project := range projects {
+		described[i] = describe(project)
+	}
+	describedPrograms := " " + described[0]
+	if len(described) > 1 {
+		describedPrograms = "\n- " + strings.Join(described, "\n- ")
+	}
+	lines := []string{
+		fmt.Sprintf("ESLint was configured to run on `%s` using `parserOptions.project`:%s", describe(filePath), describedPrograms),
+	}
+
+	ext := filepath.Ext(filePath)
+	switch {
+	case slices.Contains(defaultFileExtensions, ext) || slices.Contains(opts.ExtraFileExtensions, ext):
+		inclusion, specifier := "that TSConfig does not", "that TSConfig"
+		if len(projects) > 1 {
+			inclusion, specifier = "none of those TSConfigs", "one of those TSConfigs"
+		}
+		lines = append(lines,
+			fmt.Sprintf("However, %s include this file. Either:", inclusion),
+			"- Change ESLint's list of included files to not include this file",
+			fmt.Sprintf("- Change %s to include this file", specifier),
+			"- Create a new TSConfig that includes this file and include it in your parserOptions.project",
+			"See the typescript-eslint docs for more info: https://typescript-eslint.io/troubleshooting/typed-linting#i-get-errors-telling-me-eslint-was-configured-to-run--however-that-tsconfig-does-not--none-of-those-tsconfigs-include-this-file",
+		)
+	case len(opts.ExtraFileExtensions) > 0:
+		lines = append(lines,
+			"- parserOptions.extraFileExtensions is: "+strings.Join(opts.ExtraFileExtensions, ", "),
+			fmt.Sprintf("The extension for the file (`%s`) is non-standard. It should be added to your existing `parserOptions.extraFileExtensions`.", ext),
+		)
+	default:
+		lines = append(lines,
+			fmt.Sprintf("The extension for the file (`%s`) is non-standard. You should add `parserOptions.extraFileExtensions` to your config.", ext),
+		)
+	}
+
+	return &fileNotInProjectError{message: strings.Join(lines, "\n")}
+}
+
+// resolveProjectList returns the absolute paths of the tsconfig files that
+// the Project option names, relative to TSConfigRootDir, as typescript-estree
+// resolves them: entries without wildcards as given, then the files that the
+// glob patterns among them match, sorted, leaving out those in folders of
+// the ProjectFolderIgnoreList. An entry naming a directory stands for the
+// tsconfig.json in it. Each path appears once.
+func resolveProjectList(opts *ParseAndGenerateServicesOptions) ([]string, error) {
+	rootDir := tsconfigRootDir(opts)
+	ignore := opts.ProjectFolderIgnoreList
+	if ignore == nil {
+		ignore = defaultProjectFolderIgnoreList
+	}
+
+	var literal, patterns []string
+	for _, project := range opts.Project {
+		if isGlob(project) {
+			patterns = append(patterns, expandBraces(filepath.ToSlash(project))...)
+		} else {
+			literal = append(literal, project)
+		}
+	}
+
+	var projects []string
+	seen := make(map[string]bool)
+	add := func(project string) {
+		if !filepath.IsAbs(project) {
+			project = filepath.Join(rootDir, project)
+		}
+		if info, err := os.Stat(project); err == nil && info.IsDir() {
+			project = filepath.Join(project, "tsconfig.json")
+		}
+		if !seen[project] {
+			seen[project] = true
+			projects = append(projects, project)
+		}
+	}
+
+	for _, project := range literal {
+		add(project)
+	}
+
+	matches, err := cachedGlobProjects(rootDir, patterns, ignore, globCacheLifetime(opts))
+	if err != nil {
+		return nil, err
+	}
+	for _, match := range matches {
+		add(match)
+	}
+	return projects, nil
+}
+
+// isGlob reports whether a project entry is a glob pattern.
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[{")
+}
+
+// expandBraces expands the first {a,b} alternation in pattern, recursively,
+// into one pattern per alternative.
+func expandBraces(pattern string) []string {
+	start := strings.IndexByte(pattern, '{')
+	if start < 0 {
+		return []string{pattern}
+	}
+	end := strings.IndexByte(pattern[start:], '}')
+	if end < 0 {
+		return []string{pattern}
+	}
+	end += start
+
+	var expanded []string
+	for _, alternative := range strings.Split(pattern[start+1:end], ",") {
+		expanded = append(expanded, expandBraces(pattern[:start]+alternative+pattern[end+1:])...)
+	}
+	return expanded
+}
+
+// globCacheLifetime returns how long glob matches are reused: CacheLifetime.Glob
+// seconds, or defaultGlobCacheLifetime if it is not set. They are not reused
+// if it is zero or negative.
+func globCacheLifetime(opts *ParseAndGenerateServicesOptions) time.Duration {
+	if opts.CacheLifetime == nil || opts.CacheLifetime.Glob == nil {
+		return defaultGlobCacheLifetime
+	}
+	return time.Duration(*opts.CacheLifetime.Glob) * time.Second
+}
+
+// cachedGlobProjects returns the matches of globProjects, from globCache if
+// the same search was made less than lifetime ago.
+func cachedGlobProjects(rootDir string, patterns, ignore []string, lifetime time.Duration) ([]string, error) {
+	if len(patterns) == 0 || lifetime <= 0 {
+		return globProjects(rootDir, patterns, ignore)
+	}
+	key := rootDir + "\x00" + strings.Join(patterns, "\x01") + "\x00" + strings.Join(ignore, "\x01")
+
+	globCache.mu.Lock()
+	entry, ok := globCache.entries[key]
+	globCache.mu.Unlock()
+	if ok && time.Since(entry.createdAt) < lifetime {
+		return entry.matches, nil
+	}
+
+	matches, err := globProjects(rootDir, patterns, ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	globCache.mu.Lock()
+	globCache.entries[key] = globCacheEntry{matches: matches, createdAt: time.Now()}
+	globCache.mu.Unlock()
+	return matches, nil
+}
+
+// clearGlobCache forgets the matches of every glob search.
+func clearGlobCache() {
+	globCache.mu.Lock()
+	defer globCache.mu.Unlock()
+
+	globCache.entries = make(map[string]globCacheEntry)
+}
+
+// globProjects returns the absolute paths of the files and directories under
+// rootDir that match any of patterns, sorted, skipping directories that match
+// the ignore list.
+func globProjects(rootDir string, patterns, ignore []string) ([]string, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	var matches []string
+	err := filepath.WalkDir(rootDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == rootDir {
+				return err
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(rootDir, p)
+		if err != nil || rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() && ignored(rel, ignore) {
+			return filepath.SkipDir
+		}
+		for _, pattern := range patterns {
+			if matchGlob(strings.Split(path.Clean(pattern), "/"), strings.Split(rel, "/")) {
+				matches = append(matches, p)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for projects in %s: %w", rootDir, err)
+	}
+	return matches, nil
+}
+
+// ignored reports whether the directory at rel, relative to the root
+// directory, matches the ignore list. A pattern without a "/" is a folder
+// name, and matches a folder of that name anywhere.
+func ignored(rel string, ignore []string) bool {
+	segments := strings.Split(rel, "/")
+	for _, pattern := range ignore {
+		if !strings.Contains(pattern, "/") {
+			if ok, _ := path.Match(pattern, segments[len(segments)-1]); ok {
+				return true
+			}
+			continue
+		}
+		if matchGlob(strings.Split(path.Clean(pattern), "/"), segments) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob reports whether the path segments match the pattern segments,
+// where "**" matches any number of segments and other segments are matched
+// with path.Match. As in fast-glob, wildcards do not match names starting with
+// a dot.
+func matchGlob(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchGlob(pattern[1:], segments[i:]) {
+				return true
+			}
+			if i < len(segments) && strings.HasPrefix(segments[i], ".") {
+				break
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if strings.HasPrefix(segments[0], ".") && !strings.HasPrefix(pattern[0], ".") {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchGlob(pattern[1:], segments[1:])
+}
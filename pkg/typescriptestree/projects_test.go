@@ -0,0 +1,356 @@
+package typescriptestree
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kdy1/go-typescript-eslint/internal/program"
+)
+
+func TestParseAndGenerateServices_MultipleProjects(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"packages/a/tsconfig.json":                `{"compilerOptions": {"target": "ES2020"}, "include": ["src"]}`,
+		"packages/a/src/a.ts":                     "export const a = 1;\n",
+		"packages/b/tsconfig.json":                `{"compilerOptions": {"target": "ES2022"}, "include": ["src"]}`,
+		"packages/b/src/b.ts":                     "export const b = 1;\n",
+		"packages/b/node_modules/x/tsconfig.json": `{"include": ["../../src"]}`,
+		"tsconfig.json":                           `{"compilerOptions": {"target": "ES5"}, "include": ["scripts"]}`,
+		"scripts/build.ts":                        "export {};\n",
+	})
+
+	tests := []struct {
+		name    string
+		project []string
+		ignore  []string
+		file    string
+		want    string
+	}{
+		{
+			name:    "first project including the file",
+			project: []string{"packages/a/tsconfig.json", "packages/b/tsconfig.json"},
+			file:    "packages/b/src/b.ts",
+			want:    "packages/b/tsconfig.json",
+		},
+		{
+			name:    "directory",
+			project: []string{"packages/a"},
+			file:    "packages/a/src/a.ts",
+			want:    "packages/a/tsconfig.json",
+		},
+		{
+			name:    "glob",
+			project: []string{"./tsconfig.json", "packages/*/tsconfig.json"},
+			file:    "packages/b/src/b.ts",
+			want:    "packages/b/tsconfig.json",
+		},
+		{
+			name:    "globstar skips node_modules",
+			project: []string{"**/tsconfig.json"},
+			file:    "packages/b/src/b.ts",
+			want:    "packages/b/tsconfig.json",
+		},
+		{
+			name:    "braces",
+			project: []string{"packages/{a,b}/tsconfig.json"},
+			file:    "packages/a/src/a.ts",
+			want:    "packages/a/tsconfig.json",
+		},
+		{
+			name:    "literal entries before glob matches",
+			project: []string{"packages/*/tsconfig.json", "tsconfig.json"},
+			file:    "scripts/build.ts",
+			want:    "tsconfig.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := NewServicesBuilder().
+				WithProject(tt.project...).
+				WithTSConfigRootDir(tmpDir).
+				WithFilePath(tt.file).
+				MustBuild()
+			result, err := ParseAndGenerateServices("export {};", opts)
+			if err != nil {
+				t.Fatalf("ParseAndGenerateServices() error = %v", err)
+			}
+			want := filepath.Join(tmpDir, filepath.FromSlash(tt.want))
+			if got := result.Services.Program.Config.GetPath(); got != want {
+				t.Errorf("Expected the program of %s, got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestResolveProjectList(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"tsconfig.json":                           "{}",
+		"packages/a/tsconfig.json":                "{}",
+		"packages/b/tsconfig.json":                "{}",
+		"packages/b/tsconfig.build.json":          "{}",
+		"packages/.hidden/tsconfig.json":          "{}",
+		"packages/b/node_modules/x/tsconfig.json": "{}",
+		"packages/b/dist/tsconfig.json":           "{}",
+	})
+
+	tests := []struct {
+		name    string
+		project []string
+		ignore  []string
+		want    []string
+	}{
+		{
+			name:    "literal entries keep their order",
+			project: []string{"packages/b/tsconfig.json", "tsconfig.json", "./packages/b/tsconfig.json"},
+			want:    []string{"packages/b/tsconfig.json", "tsconfig.json"},
+		},
+		{
+			name:    "glob matches in walk order",
+			project: []string{"packages/*/tsconfig*.json"},
+			want:    []string{"packages/a/tsconfig.json", "packages/b/tsconfig.build.json", "packages/b/tsconfig.json"},
+		},
+		{
+			name:    "default ignore list",
+			project: []string{"**/tsconfig.json"},
+			want: []string{
+				"packages/a/tsconfig.json",
+				"packages/b/dist/tsconfig.json",
+				"packages/b/tsconfig.json",
+				"tsconfig.json",
+			},
+		},
+		{
+			name:    "custom ignore list",
+			project: []string{"packages/**/tsconfig.json"},
+			ignore:  []string{"dist", "**/node_modules/**"},
+			want:    []string{"packages/a/tsconfig.json", "packages/b/tsconfig.json"},
+		},
+		{
+			name:    "empty ignore list",
+			project: []string{"packages/b/**/tsconfig.json"},
+			ignore:  []string{},
+			want: []string{
+				"packages/b/dist/tsconfig.json",
+				"packages/b/node_modules/x/tsconfig.json",
+				"packages/b/tsconfig.json",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveProjectList(&ParseAndGenerateServicesOptions{
+				Project:                 tt.project,
+				ProjectFolderIgnoreList: tt.ignore,
+				TSConfigRootDir:         tmpDir,
+			})
+			if err != nil {
+				t.Fatalf("resolveProjectList() error = %v", err)
+			}
+			want := make([]string, len(tt.want))
+			for i, name := range tt.want {
+				want[i] = filepath.Join(tmpDir, filepath.FromSlash(name))
+			}
+			if strings.Join(got, "\n") != strings.Join(want, "\n") {
+				t.Errorf("resolveProjectList() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestResolveProjectListGlobCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{"packages/a/tsconfig.json": "{}"})
+
+	resolve := func(lifetime *CacheDurationSeconds) int {
+		t.Helper()
+		got, err := resolveProjectList(&ParseAndGenerateServicesOptions{
+			Project:         []string{"packages/*/tsconfig.json"},
+			TSConfigRootDir: tmpDir,
+			CacheLifetime:   &CacheLifetime{Glob: lifetime},
+		})
+		if err != nil {
+			t.Fatalf("resolveProjectList() error = %v", err)
+		}
+		return len(got)
+	}
+
+	if n := resolve(nil); n != 1 {
+		t.Fatalf("Expected 1 project, got %d", n)
+	}
+	writeFiles(t, tmpDir, map[string]string{"packages/b/tsconfig.json": "{}"})
+
+	if n := resolve(nil); n != 1 {
+		t.Errorf("Expected the cached glob matches to be reused, got %d projects", n)
+	}
+	disabled := CacheDurationSeconds(0)
+	if n := resolve(&disabled); n != 2 {
+		t.Errorf("Expected a zero lifetime to search again, got %d projects", n)
+	}
+	ClearProgramCache()
+	if n := resolve(nil); n != 2 {
+		t.Errorf("Expected ClearProgramCache to drop the glob matches, got %d projects", n)
+	}
+}
+
+func TestParseAndGenerateServices_CacheLifetimeReusesPrograms(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"tsconfig.json": `{"include": ["src"]}`,
+		"src/a.ts":      "export const a = 1;\n",
+	})
+	t.Cleanup(ClearProgramCache)
+
+	lifetime := CacheDurationSeconds(60)
+	parse := func() *program.Program {
+		t.Helper()
+		opts := NewServicesBuilder().
+			WithProject("tsconfig.json").
+			WithTSConfigRootDir(tmpDir).
+			WithFilePath("src/a.ts").
+			WithCacheLifetime(&CacheLifetime{Glob: &lifetime}).
+			MustBuild()
+		result, err := ParseAndGenerateServices("export const a = 1;", opts)
+		if err != nil {
+			t.Fatalf("ParseAndGenerateServices() error = %v", err)
+		}
+		return result.Services.Program
+	}
+
+	first := parse()
+	if parse() != first {
+		t.Error("Expected calls with the same cache lifetime to share the program")
+	}
+	ClearProgramCache()
+	if parse() == first {
+		t.Error("Expected ClearProgramCache to drop the program")
+	}
+}
+
+func TestParseAndGenerateServices_FileNotInProject(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"a/tsconfig.json": `{"include": ["src"]}`,
+		"b/tsconfig.json": `{"include": ["src"]}`,
+	})
+
+	tests := []struct {
+		name    string
+		project []string
+		extra   []string
+		file    string
+		want    []string
+	}{
+		{
+			name:    "one project",
+			project: []string{"a/tsconfig.json"},
+			file:    "scripts/build.ts",
+			want: []string{
+				"ESLint was configured to run on `<tsconfigRootDir>/scripts/build.ts` using `parserOptions.project`: <tsconfigRootDir>/a/tsconfig.json",
+				"However, that TSConfig does not include this file.",
+			},
+		},
+		{
+			name:    "several projects",
+			project: []string{"*/tsconfig.json"},
+			file:    "scripts/build.ts",
+			want: []string{
+				"`parserOptions.project`:\n- <tsconfigRootDir>/a/tsconfig.json\n- <tsconfigRootDir>/b/tsconfig.json",
+				"However, none of those TSConfigs include this file.",
+			},
+		},
+		{
+			name:    "non-standard extension",
+			project: []string{"a/tsconfig.json"},
+			file:    "a/src/App.vue",
+			want:    []string{"The extension for the file (`.vue`) is non-standard. You should add `parserOptions.extraFileExtensions`"},
+		},
+		{
+			name:    "missing extra file extension",
+			project: []string{"a/tsconfig.json"},
+			extra:   []string{".svelte"},
+			file:    "a/src/App.vue",
+			want:    []string{"It should be added to your existing `parserOptions.extraFileExtensions`."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := NewServicesBuilder().
+				WithProject(tt.project...).
+				WithTSConfigRootDir(tmpDir).
+				WithFilePath(tt.file).
+				MustBuild()
+			opts.ExtraFileExtensions = tt.extra
+			_, err := ParseAndGenerateServices("export {};", opts)
+			if !errors.Is(err, ErrFileNotInProject) {
+				t.Fatalf("Expected ErrFileNotInProject, got %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Expected the error to contain %q, got:\n%s", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseAndGenerateServices_Programs(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"a/tsconfig.json": `{"include": ["src"]}`,
+		"a/src/a.ts":      "export const a = 1;\n",
+		"b/tsconfig.json": `{"include": ["src"]}`,
+		"b/src/b.ts":      "export const b = 1;\n",
+	})
+
+	var programs []*program.Program
+	for _, name := range []string{"a", "b"} {
+		prog, err := program.CreateProgram(&program.ProgramOptions{TSConfigPath: filepath.Join(tmpDir, name, "tsconfig.json")})
+		if err != nil {
+			t.Fatalf("Failed to create program: %v", err)
+		}
+		programs = append(programs, prog)
+	}
+
+	opts := NewServicesBuilder().WithTSConfigRootDir(tmpDir).WithFilePath("b/src/b.ts").MustBuild()
+	opts.Programs = programs
+	result, err := ParseAndGenerateServices("export const b = 1;", opts)
+	if err != nil {
+		t.Fatalf("ParseAndGenerateServices() error = %v", err)
+	}
+	if result.Services.Program != programs[1] {
+		t.Errorf("Expected the program that includes the file")
+	}
+
+	opts.FilePath = "c/src/c.ts"
+	_, err = ParseAndGenerateServices("export {};", opts)
+	if !errors.Is(err, ErrFileNotInProject) {
+		t.Fatalf("Expected ErrFileNotInProject, got %v", err)
+	}
+	if want := "The file was not found in any of the provided program instance(s): " + filepath.Join("c", "src", "c.ts"); !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected the error to contain %q, got:\n%s", want, err)
+	}
+}
+
+func TestParseAndGenerateServices_WithoutProject(t *testing.T) {
+	// Like typescript-estree, a file parsed without project, projectService
+	// or programs still gets services, only without a program
+	result, err := ParseAndGenerateServices("const x = 1;", NewServicesBuilder().MustBuild())
+	if err != nil {
+		t.Fatalf("ParseAndGenerateServices() error = %v", err)
+	}
+	if result.Services == nil {
+		t.Fatal("Expected services")
+	}
+	if result.Services.Program != nil {
+		t.Error("Expected services without a program")
+	}
+	if !result.Services.HasNodeMapping(result.AST.Body[0]) {
+		t.Error("Expected the node maps to be preserved")
+	}
+}
@@ -0,0 +1,178 @@
+package typescriptestree
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/kdy1/go-typescript-eslint/internal/program"
+)
+
+// defaultMaximumDefaultProjectFileMatchCount is the number of files that may
+// be parsed with the default project when
+// ProjectServiceOptions.MaximumDefaultProjectFileMatchCount is not set.
+const defaultMaximumDefaultProjectFileMatchCount = 8
+
+// defaultProjectFilesErrorExplanation ends the errors about files parsed with
+// the default project.
+const defaultProjectFilesErrorExplanation = `
+
+Having many files run with the default project is known to cause performance issues and slow down linting.
+
+See https://typescript-eslint.io/troubleshooting/typed-linting#allowdefaultproject-glob-too-wide
+`
+
+// projectServices holds the project services created by
+// ParseAndGenerateServices, one for each distinct configuration, so that
+// projects stay open from one parse to the next.
+var projectServices = struct {
+	services map[string]*program.ProjectService
+	mu       sync.Mutex
+}{services: make(map[string]*program.ProjectService)}
+
+// validateDefaultProjectForFilesGlob rejects allowDefaultProject globs that
+// would match so many files that the default project would make linting slow.
+func validateDefaultProjectForFilesGlob(allowDefaultProject []string) error {
+	for _, glob := range allowDefaultProject {
+		if glob == "*" {
+			//nolint:staticcheck // The message is typescript-estree's
+			return errors.New("allowDefaultProject contains the overly wide '*'." + defaultProjectFilesErrorExplanation)
+		}
+		if strings.Contains(glob, "**") {
+			//nolint:staticcheck // The message is typescript-estree's
+			return fmt.Errorf("allowDefaultProject glob '%s' contains a disallowed '**'.%s", glob, defaultProjectFilesErrorExplanation)
+		}
+	}
+	return nil
+}
+
+// getProjectService returns the project service for the options, creating
+// it on first use.
+func getProjectService(opts *ParseAndGenerateServicesOptions) (*program.ProjectService, error) {
+	serviceOpts := &program.ProjectServiceOptions{
+		RootDir:             tsconfigRootDir(opts),
+		ExtraFileExtensions: opts.ExtraFileExtensions,
+	}
+	if opts.ProjectServiceOptions != nil {
+		serviceOpts.DefaultProject = opts.ProjectServiceOptions.DefaultProject
+	}
+	key := strings.Join([]string{
+		serviceOpts.RootDir,
+		serviceOpts.DefaultProject,
+		strings.Join(serviceOpts.ExtraFileExtensions, ","),
+	}, "\x00")
+
+	projectServices.mu.Lock()
+	defer projectServices.mu.Unlock()
+
+	if service, ok := projectServices.services[key]; ok {
+		return service, nil
+	}
+	service, err := program.NewProjectService(serviceOpts)
+	if err != nil {
+		return nil, err
+	}
+	projectServices.services[key] = service
+	return service, nil
+}
+
+// useProgramFromProjectService returns the program to parse the file with
+// using the project service, as typescript-estree does: that of the file's
+// configured project or, for files no tsconfig includes that match
+// AllowDefaultProject, that of the default project.
+func useProgramFromProjectService(opts *ParseAndGenerateServicesOptions) (*program.Program, error) {
+	if opts.FilePath == "" {
+		return nil, errors.New("a file path is required to use the project service")
+	}
+
+	serviceOpts := opts.ProjectServiceOptions
+	if serviceOpts == nil {
+		serviceOpts = &ProjectServiceOptions{}
+	}
+	if err := validateDefaultProjectForFilesGlob(serviceOpts.AllowDefaultProject); err != nil {
+		return nil, err
+	}
+
+	service, err := getProjectService(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	filePath := absoluteFilePath(opts)
+	rel, err := filepath.Rel(tsconfigRootDir(opts), filePath)
+	if err != nil {
+		rel = filePath
+	}
+	rel = filepath.ToSlash(rel)
+	isDefaultProjectAllowed := matchesAny(rel, serviceOpts.AllowDefaultProject)
+
+	prog, err := service.OpenConfiguredProject(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if prog != nil {
+		if isDefaultProjectAllowed {
+			//nolint:staticcheck // The message is typescript-estree's
+			return nil, fmt.Errorf("%s was included by allowDefaultProject but also was found in the project service. Consider removing it from allowDefaultProject.", rel)
+		}
+		return prog, nil
+	}
+
+	if !isDefaultProjectAllowed {
+		return nil, &fileNotInProjectError{message: fmt.Sprintf(
+			"%s was not found by the project service. Consider either including it in the tsconfig.json or including it in allowDefaultProject.", rel)}
+	}
+
+	// Refuse to open the file if it would take the default project over the
+	// maximum, so that it is not parsed for nothing
+	maximum := serviceOpts.MaximumDefaultProjectFileMatchCount
+	if maximum == 0 {
+		maximum = defaultMaximumDefaultProjectFileMatchCount
+	}
+	if files := service.DefaultProjectFiles(); !slices.Contains(files, filePath) && len(files) >= maximum {
+		//nolint:staticcheck // The message is typescript-estree's
+		return nil, fmt.Errorf("Too many files (>%d) have matched the default project.%s\nMatching files:\n- %s\n\n"+
+			"If you absolutely need more files included, set parserOptions.projectService.maximumDefaultProjectFileMatchCount_THIS_WILL_SLOW_DOWN_LINTING to a larger value.\n",
+			maximum, defaultProjectFilesErrorExplanation, strings.Join(append(files, filePath), "\n- "))
+	}
+
+	return service.OpenDefaultProject(filePath), nil
+}
+
+// matchesAny reports whether the slash-separated path rel matches any of the
+// globs.
+func matchesAny(rel string, globs []string) bool {
+	segments := strings.Split(rel, "/")
+	for _, glob := range globs {
+		for _, pattern := range expandBraces(filepath.ToSlash(glob)) {
+			if matchGlob(strings.Split(path.Clean(pattern), "/"), segments) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// clearDefaultProjectFiles closes the files opened in the default projects
+// of every project service.
+func clearDefaultProjectFiles() {
+	projectServices.mu.Lock()
+	defer projectServices.mu.Unlock()
+
+	for _, service := range projectServices.services {
+		service.ClearDefaultProjectFiles()
+	}
+}
+
+// clearProjectServices drops every project service, with the projects open
+// in it.
+func clearProjectServices() {
+	projectServices.mu.Lock()
+	defer projectServices.mu.Unlock()
+
+	projectServices.services = make(map[string]*program.ProjectService)
+}
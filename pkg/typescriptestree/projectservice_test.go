@@ -0,0 +1,189 @@
+package typescriptestree
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseAndGenerateServices_ProjectService(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"tsconfig.json":                  `{"compilerOptions": {"target": "ES2020"}, "include": ["src", "packages/*/scripts"]}`,
+		"src/index.ts":                   "export const index = 1;\n",
+		"packages/web/tsconfig.json":     `{"compilerOptions": {"target": "ES2022"}, "include": ["src"]}`,
+		"packages/web/src/app.ts":        "export const app = 1;\n",
+		"packages/web/scripts/build.ts":  "export {};\n",
+		"tsconfig.eslint.json":           `{"compilerOptions": {"target": "ESNext"}}`,
+		"eslint.config.js":               "export default [];\n",
+		"packages/web/vitest.config.mjs": "export default {};\n",
+	})
+
+	tests := []struct {
+		name    string
+		file    string
+		options *ProjectServiceOptions
+		want    string
+		wantErr string
+	}{
+		{
+			name: "nearest tsconfig",
+			file: "packages/web/src/app.ts",
+			want: "packages/web/tsconfig.json",
+		},
+		{
+			name: "ancestor tsconfig when the nearest does not include the file",
+			file: "packages/web/scripts/build.ts",
+			want: "tsconfig.json",
+		},
+		{
+			name:    "not found",
+			file:    "eslint.config.js",
+			wantErr: "eslint.config.js was not found by the project service. Consider either including it in the tsconfig.json or including it in allowDefaultProject.",
+		},
+		{
+			name:    "default project",
+			file:    "eslint.config.js",
+			options: &ProjectServiceOptions{AllowDefaultProject: []string{"*.js"}, DefaultProject: "tsconfig.eslint.json"},
+			want:    "tsconfig.eslint.json",
+		},
+		{
+			name:    "default project with braces",
+			file:    "packages/web/vitest.config.mjs",
+			options: &ProjectServiceOptions{AllowDefaultProject: []string{"packages/*/vitest.config.{js,mjs}"}},
+			want:    "tsconfig.json",
+		},
+		{
+			name:    "allowed but found",
+			file:    "src/index.ts",
+			options: &ProjectServiceOptions{AllowDefaultProject: []string{"src/*.ts"}},
+			wantErr: "src/index.ts was included by allowDefaultProject but also was found in the project service.",
+		},
+		{
+			name:    "missing default project",
+			file:    "eslint.config.js",
+			options: &ProjectServiceOptions{AllowDefaultProject: []string{"*.js"}, DefaultProject: "tsconfig.missing.json"},
+			wantErr: "could not read default project tsconfig.missing.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ClearProgramCache()
+			opts := NewServicesBuilder().
+				WithProjectService(true).
+				WithTSConfigRootDir(tmpDir).
+				WithFilePath(tt.file).
+				MustBuild()
+			opts.ProjectServiceOptions = tt.options
+
+			result, err := ParseAndGenerateServices("export {};", opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAndGenerateServices() error = %v", err)
+			}
+
+			prog := result.Services.Program
+			if want := filepath.Join(tmpDir, filepath.FromSlash(tt.want)); prog.Config.GetPath() != want {
+				t.Errorf("Expected the program of %s, got %s", want, prog.Config.GetPath())
+			}
+			if !prog.ContainsFile(filepath.Join(tmpDir, filepath.FromSlash(tt.file))) {
+				t.Errorf("Expected the program to contain %s", tt.file)
+			}
+		})
+	}
+}
+
+func TestParseAndGenerateServices_ProjectServiceFileNotInProject(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{"tsconfig.json": `{"include": ["src"]}`})
+	ClearProgramCache()
+
+	opts := NewServicesBuilder().
+		WithProjectService(true).
+		WithTSConfigRootDir(tmpDir).
+		WithFilePath("scripts/build.ts").
+		MustBuild()
+	if _, err := ParseAndGenerateServices("export {};", opts); !errors.Is(err, ErrFileNotInProject) {
+		t.Errorf("Expected ErrFileNotInProject, got %v", err)
+	}
+}
+
+func TestParseAndGenerateServices_DefaultProjectFileMatchCount(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"a.js": "export {};\n",
+		"b.js": "export {};\n",
+		"c.js": "export {};\n",
+	})
+	ClearProgramCache()
+
+	parse := func(file string) error {
+		opts := NewServicesBuilder().
+			WithProjectServiceOptions(&ProjectServiceOptions{
+				AllowDefaultProject:                 []string{"*.js"},
+				MaximumDefaultProjectFileMatchCount: 2,
+			}).
+			WithTSConfigRootDir(tmpDir).
+			WithFilePath(file).
+			MustBuild()
+		_, err := ParseAndGenerateServices("export {};", opts)
+		return err
+	}
+
+	for _, file := range []string{"a.js", "b.js", "a.js"} {
+		if err := parse(file); err != nil {
+			t.Fatalf("Parsing %s: unexpected error %v", file, err)
+		}
+	}
+	err := parse("c.js")
+	if err == nil || !strings.Contains(err.Error(), "Too many files (>2) have matched the default project.") {
+		t.Fatalf("Expected a too many files error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "- "+filepath.Join(tmpDir, "c.js")) {
+		t.Errorf("Expected the error to list the matching files, got:\n%s", err)
+	}
+	service, err := getProjectService(&ParseAndGenerateServicesOptions{TSConfigRootDir: tmpDir})
+	if err != nil {
+		t.Fatalf("getProjectService() error = %v", err)
+	}
+	if files := service.DefaultProjectFiles(); len(files) != 2 {
+		t.Errorf("Expected c.js not to be opened in the default project, got %v", files)
+	}
+
+	ClearDefaultProjectMatchedFiles()
+	if err := parse("c.js"); err != nil {
+		t.Errorf("Expected no error after clearing the matched files, got %v", err)
+	}
+}
+
+func TestValidateDefaultProjectForFilesGlob(t *testing.T) {
+	tests := []struct {
+		globs   []string
+		wantErr string
+	}{
+		{globs: nil},
+		{globs: []string{"*.js", "scripts/*.ts"}},
+		{globs: []string{"*"}, wantErr: "allowDefaultProject contains the overly wide '*'."},
+		{globs: []string{"src/**/*.js"}, wantErr: "allowDefaultProject glob 'src/**/*.js' contains a disallowed '**'."},
+	}
+
+	for _, tt := range tests {
+		_, err := NewServicesBuilder().WithProjectServiceOptions(&ProjectServiceOptions{AllowDefaultProject: tt.globs}).Build()
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("Validate() with %v: unexpected error %v", tt.globs, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Validate() with %v: expected an error containing %q, got %v", tt.globs, tt.wantErr, err)
+		}
+	}
+}
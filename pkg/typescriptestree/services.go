@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"sync"
 
-	"github.com/kdy1/go-typescript-eslint/internal/ast"
 	"github.com/kdy1/go-typescript-eslint/internal/program"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 )
 
 // ParserServices provides access to TypeScript type information and node mappings.
@@ -6,8 +6,8 @@ import (
 	"path/filepath"
 	"testing"
 
-	"github.com/kdy1/go-typescript-eslint/internal/ast"
 	"github.com/kdy1/go-typescript-eslint/internal/program"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
 )
 
 func TestNewParserServices(t *testing.T) {
@@ -253,3 +253,45 @@ func TestGetSymbolAtLocation(t *testing.T) {
 		t.Errorf("Expected ErrNotImplemented, got %v", err)
 	}
 }
+
+func TestParseAndGenerateServices_ProjectReferences(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"tsconfig.json":             `{"files": [], "references": [{"path": "./web"}]}`,
+		"web/tsconfig.json":         `{"compilerOptions": {"target": "ES2022"}, "include": ["src"]}`,
+		"web/src/app.ts":            "export const app = 1;\n",
+		"web/src/nested/helpers.ts": "export const helper = 2;\n",
+	}
+	writeFiles(t, tmpDir, files)
+
+	opts := NewServicesBuilder().
+		WithProject(filepath.Join(tmpDir, "tsconfig.json")).
+		WithFilePath(filepath.Join(tmpDir, "web", "src", "app.ts")).
+		MustBuild()
+	result, err := ParseAndGenerateServices("export const app = 1;", opts)
+	if err != nil {
+		t.Fatalf("ParseAndGenerateServices() error = %v", err)
+	}
+
+	prog := result.Services.Program
+	if prog == nil || prog.Config.GetPath() != filepath.Join(tmpDir, "web", "tsconfig.json") {
+		t.Fatalf("Expected the program of the referenced project, got %v", prog)
+	}
+	if target := result.Services.GetCompilerOptions().Target; target != "ES2022" {
+		t.Errorf("Expected target ES2022 from the referenced project, got %q", target)
+	}
+}
+
+// writeFiles writes files, keyed by slash-separated paths relative to root.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
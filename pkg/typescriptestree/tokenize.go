@@ -0,0 +1,380 @@
+package typescriptestree
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kdy1/go-typescript-eslint/internal/lexer"
+	"github.com/kdy1/go-typescript-eslint/pkg/tsestree/ast"
+)
+
+// TokenType is the ESTree type of a token, as reported by typescript-estree.
+type TokenType string
+
+const (
+	TokenBoolean           TokenType = "Boolean"
+	TokenIdentifier        TokenType = "Identifier"
+	TokenKeyword           TokenType = "Keyword"
+	TokenNull              TokenType = "Null"
+	TokenNumeric           TokenType = "Numeric"
+	TokenPunctuator        TokenType = "Punctuator"
+	TokenRegularExpression TokenType = "RegularExpression"
+	TokenString            TokenType = "String"
+	TokenTemplate          TokenType = "Template"
+
+	// TokenInvalid is text that is not a valid token, such as an
+	// unterminated string or an unknown character.
+	TokenInvalid TokenType = "Invalid"
+
+	// TokenEOF ends every token stream. It has no text, but carries the
+	// trivia at the end of the source.
+	TokenEOF TokenType = "EOF"
+)
+
+// TriviaKind is the kind of a piece of trivia.
+type TriviaKind string
+
+const (
+	TriviaWhitespace   TriviaKind = "Whitespace"
+	TriviaNewline      TriviaKind = "Newline"
+	TriviaLineComment  TriviaKind = "Line"
+	TriviaBlockComment TriviaKind = "Block"
+)
+
+// Trivia is source text between tokens that does not affect the program:
+// a run of whitespace, a single line break or a comment.
+type Trivia struct {
+	Kind  TriviaKind
+	Text  string // the source text, including comment delimiters
+	Range ast.Range
+	Loc   ast.SourceLocation
+}
+
+// Token is a token together with the trivia around it. A token's trailing
+// trivia runs up to and including the first line break after it; all other
+// trivia leads the token that follows. Concatenating the leading trivia,
+// value and trailing trivia of every token reproduces the source exactly.
+type Token struct {
+	Type           TokenType
+	Value          string // the source text of the token
+	Range          ast.Range
+	Loc            ast.SourceLocation
+	LeadingTrivia  []Trivia
+	TrailingTrivia []Trivia
+}
+
+// Tokenizer splits source text into tokens without parsing it. Positions are
+// measured in the OffsetEncoding of its options.
+//
+// Whether a '/' starts a regular expression depends on the grammar, so the
+// tokenizer decides from the preceding token, like most syntax highlighters.
+// It does not recognize JSX: the text of JSX children is tokenized as code.
+//
+// Use it like bufio.Scanner:
+//
+//	t := typescriptestree.NewTokenizer(source, nil)
+//	for t.Next() {
+//		tok := t.Token()
+//		// ...
+//	}
+type Tokenizer struct {
+	source  string
+	scanner *lexer.Scanner
+	offsets *SourceOffsets
+	enc     OffsetEncoding
+
+	pos     int          // end of the input consumed so far
+	peeked  *lexer.Token // the next scanner token, once looked at
+	prev    lexer.TokenType
+	started bool
+	done    bool
+
+	// For each '{' and '${' not yet closed, whether it opened a template
+	// substitution, whose closing '}' continues the template.
+	braces []bool
+
+	token Token
+}
+
+// NewTokenizer creates a tokenizer for source. If opts is nil, the defaults
+// of NewParseOptions apply.
+func NewTokenizer(source string, opts *ParseOptions) *Tokenizer {
+	if opts == nil {
+		opts = NewParseOptions()
+	}
+
+	scanner := lexer.NewScanner(source)
+	scanner.SetSkipComments(false)
+
+	return &Tokenizer{
+		source:  source,
+		scanner: scanner,
+		offsets: NewSourceOffsets(source),
+		enc:     opts.OffsetEncoding,
+	}
+}
+
+// Tokenize returns all tokens of source, ending with a TokenEOF token.
+func Tokenize(source string, opts *ParseOptions) ([]Token, error) {
+	if opts != nil {
+		if err := opts.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid options: %w", err)
+		}
+	}
+
+	var tokens []Token
+	t := NewTokenizer(source, opts)
+	for t.Next() {
+		tokens = append(tokens, t.Token())
+	}
+	return tokens, nil
+}
+
+// Next advances to the next token, which is then available through Token.
+// It returns false once the TokenEOF token has been returned.
+func (t *Tokenizer) Next() bool {
+	if t.done {
+		return false
+	}
+
+	// Leading trivia: everything up to the next significant token.
+	var leading []Trivia
+	for {
+		item := t.peek()
+		leading = t.appendSpace(leading, item.Pos)
+		if item.Type != lexer.COMMENT {
+			break
+		}
+		leading = append(leading, t.comment(item))
+		t.consume()
+	}
+
+	item := t.consume()
+	t.token = Token{
+		Type:          tokenType(item),
+		Value:         t.source[item.Pos:item.End],
+		Range:         t.rangeOf(item.Pos, item.End),
+		Loc:           t.locOf(item.Pos, item.End),
+		LeadingTrivia: leading,
+	}
+	if item.Type == lexer.EOF {
+		t.done = true
+		return true
+	}
+
+	// Trailing trivia: whitespace and comments up to the end of the line.
+	var trailing []Trivia
+	for {
+		item := t.peek()
+		if i := strings.IndexAny(t.source[t.pos:item.Pos], "\r\n\u2028\u2029"); i >= 0 {
+			trailing = t.appendSpace(trailing, t.pos+i)
+			_, size := utf8.DecodeRuneInString(t.source[t.pos:])
+			if strings.HasPrefix(t.source[t.pos:], "\r\n") {
+				size = 2
+			}
+			trailing = append(trailing, t.trivia(TriviaNewline, t.pos, t.pos+size))
+			t.pos += size
+			break
+		}
+
+		trailing = t.appendSpace(trailing, item.Pos)
+		if item.Type != lexer.COMMENT {
+			break
+		}
+		trailing = append(trailing, t.comment(item))
+		t.consume()
+	}
+	t.token.TrailingTrivia = trailing
+
+	return true
+}
+
+// Token returns the token most recently read by Next.
+func (t *Tokenizer) Token() Token {
+	return t.token
+}
+
+// peek returns the next scanner token without consuming it.
+func (t *Tokenizer) peek() lexer.Token {
+	if t.peeked == nil {
+		tok := t.scan()
+		t.peeked = &tok
+	}
+	return *t.peeked
+}
+
+// consume returns the next scanner token and moves past it.
+func (t *Tokenizer) consume() lexer.Token {
+	tok := t.peek()
+	t.peeked = nil
+	t.pos = tok.End
+	if tok.Type != lexer.COMMENT {
+		t.prev = tok.Type
+		t.started = true
+	}
+	return tok
+}
+
+// scan scans the next token, resolving the ambiguities the scanner leaves to
+// its caller: '/' that starts a regular expression and '}' that continues a
+// template literal.
+func (t *Tokenizer) scan() lexer.Token {
+	tok := t.scanner.Scan()
+
+	switch tok.Type {
+	case lexer.QUO, lexer.QuoAssign:
+		if t.regExpAllowed() {
+			tok = t.scanner.ScanRegExp()
+		}
+	case lexer.LBRACE:
+		t.braces = append(t.braces, false)
+	case lexer.TemplateHead:
+		t.braces = append(t.braces, true)
+	case lexer.RBRACE:
+		if n := len(t.braces); n > 0 {
+			substitution := t.braces[n-1]
+			t.braces = t.braces[:n-1]
+			if substitution {
+				tok = t.scanner.ScanTemplateContinuation()
+				if tok.Type == lexer.TemplateMiddle {
+					t.braces = append(t.braces, true)
+				}
+			}
+		}
+	}
+
+	return tok
+}
+
+// regExpAllowed reports whether a '/' after the previous token starts a
+// regular expression rather than a division. A regular expression cannot
+// follow an operand: a name, a literal or a closing ')' or ']'.
+func (t *Tokenizer) regExpAllowed() bool {
+	if !t.started {
+		return true
+	}
+
+	switch t.prev {
+	case lexer.IDENT, lexer.NUMBER, lexer.STRING, lexer.REGEXP, lexer.TemplateNoSub, lexer.TemplateTail,
+		lexer.RPAREN, lexer.RBRACK, lexer.THIS, lexer.SUPER, lexer.TRUE, lexer.FALSE, lexer.NULL,
+		lexer.INC, lexer.DEC:
+		return false
+	}
+	return !isIdentifierToken(t.prev)
+}
+
+// appendSpace appends trivia for the whitespace between the consumed input
+// and end, splitting it into runs of spaces and single line breaks.
+func (t *Tokenizer) appendSpace(trivia []Trivia, end int) []Trivia {
+	for t.pos < end {
+		start := t.pos
+		r, size := utf8.DecodeRuneInString(t.source[start:])
+
+		kind := TriviaWhitespace
+		switch {
+		case r == '\r' && strings.HasPrefix(t.source[start:], "\r\n"):
+			kind, size = TriviaNewline, 2
+		case r == '\n' || r == '\r' || r == '\u2028' || r == '\u2029':
+			kind = TriviaNewline
+		default:
+			for i := start + size; i < end; {
+				r, n := utf8.DecodeRuneInString(t.source[i:])
+				if r == '\n' || r == '\r' || r == '\u2028' || r == '\u2029' {
+					break
+				}
+				i += n
+				size = i - start
+			}
+		}
+
+		t.pos += size
+		trivia = append(trivia, t.trivia(kind, start, t.pos))
+	}
+	return trivia
+}
+
+// comment returns the trivia for a comment token.
+func (t *Tokenizer) comment(tok lexer.Token) Trivia {
+	kind := TriviaLineComment
+	if strings.HasPrefix(tok.Literal, "/*") {
+		kind = TriviaBlockComment
+	}
+	return t.trivia(kind, tok.Pos, tok.End)
+}
+
+// trivia returns the trivia of the given kind spanning [start, end).
+func (t *Tokenizer) trivia(kind TriviaKind, start, end int) Trivia {
+	return Trivia{
+		Kind:  kind,
+		Text:  t.source[start:end],
+		Range: t.rangeOf(start, end),
+		Loc:   t.locOf(start, end),
+	}
+}
+
+// rangeOf converts a byte range to the tokenizer's encoding.
+func (t *Tokenizer) rangeOf(start, end int) ast.Range {
+	return ast.Range{
+		t.offsets.Convert(start, OffsetEncodingByte, t.enc),
+		t.offsets.Convert(end, OffsetEncodingByte, t.enc),
+	}
+}
+
+// locOf returns the location of a byte range.
+func (t *Tokenizer) locOf(start, end int) ast.SourceLocation {
+	var loc ast.SourceLocation
+	loc.Start.Line, loc.Start.Column = t.offsets.lineColumn(start, t.enc)
+	loc.End.Line, loc.End.Column = t.offsets.lineColumn(end, t.enc)
+	return loc
+}
+
+// tokenType returns the ESTree type of a scanner token. As in
+// typescript-estree, reserved and strict mode reserved words are keywords,
+// while contextual TypeScript keywords such as "type" are identifiers.
+func tokenType(tok lexer.Token) TokenType {
+	switch tok.Type {
+	case lexer.EOF:
+		return TokenEOF
+	case lexer.ILLEGAL:
+		return TokenInvalid
+	case lexer.NUMBER:
+		return TokenNumeric
+	case lexer.STRING:
+		return TokenString
+	case lexer.REGEXP:
+		return TokenRegularExpression
+	case lexer.TEMPLATE, lexer.TemplateHead, lexer.TemplateMiddle, lexer.TemplateTail, lexer.TemplateNoSub:
+		return TokenTemplate
+	case lexer.TRUE, lexer.FALSE:
+		return TokenBoolean
+	case lexer.NULL:
+		return TokenNull
+	}
+
+	switch {
+	case tok.Type >= lexer.BREAK && tok.Type <= lexer.YIELD, isStrictReservedWord(tok.Type):
+		return TokenKeyword
+	case isIdentifierToken(tok.Type):
+		return TokenIdentifier
+	default:
+		return TokenPunctuator
+	}
+}
+
+// isIdentifierToken reports whether typ is an identifier or a contextual
+// keyword, which is an identifier outside of the contexts that give it meaning.
+func isIdentifierToken(typ lexer.TokenType) bool {
+	return typ == lexer.IDENT || (typ >= lexer.AS && typ <= lexer.UNDEFINED && !isStrictReservedWord(typ))
+}
+
+// isStrictReservedWord reports whether typ is a word that is reserved in
+// strict mode code, which the scanner lists among the TypeScript keywords.
+func isStrictReservedWord(typ lexer.TokenType) bool {
+	switch typ {
+	case lexer.INTERFACE, lexer.LET, lexer.PACKAGE, lexer.PRIVATE, lexer.PROTECTED,
+		lexer.PUBLIC, lexer.STATIC, lexer.IMPLEMENTS:
+		return true
+	}
+	return false
+}
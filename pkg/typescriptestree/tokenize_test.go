@@ -0,0 +1,143 @@
+package typescriptestree
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{
+			name:   "keywords and identifiers",
+			source: `let x: type = null; interface I {}`,
+			want: []string{
+				"Keyword let", "Identifier x", "Punctuator :", "Identifier type", "Punctuator =",
+				"Null null", "Punctuator ;", "Keyword interface", "Identifier I", "Punctuator {", "Punctuator }",
+			},
+		},
+		{
+			name:   "regular expression and division",
+			source: `a = b / c; r = /[/]+/gi.test(s)`,
+			want: []string{
+				"Identifier a", "Punctuator =", "Identifier b", "Punctuator /", "Identifier c", "Punctuator ;",
+				"Identifier r", "Punctuator =", "RegularExpression /[/]+/gi", "Punctuator .", "Identifier test",
+				"Punctuator (", "Identifier s", "Punctuator )",
+			},
+		},
+		{
+			name:   "template substitutions",
+			source: "`a${ {b}.b }c${d}`",
+			want: []string{
+				"Template `a${", "Punctuator {", "Identifier b", "Punctuator }", "Punctuator .", "Identifier b",
+				"Template }c${", "Identifier d", "Template }`",
+			},
+		},
+		{
+			name:   "literals",
+			source: `true 1.5 'str'`,
+			want:   []string{"Boolean true", "Numeric 1.5", "String 'str'"},
+		},
+		{
+			name:   "unterminated string",
+			source: `"abc`,
+			want:   []string{`Invalid "abc`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := Tokenize(tt.source, nil)
+			if err != nil {
+				t.Fatalf("Tokenize() error = %v", err)
+			}
+
+			var got []string
+			for _, tok := range tokens {
+				if tok.Type != TokenEOF {
+					got = append(got, fmt.Sprintf("%s %s", tok.Type, tok.Value))
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTokenize_Trivia(t *testing.T) {
+	source := "// header\nlet a = 1; // trailing\n\n  /* lead */ a++\r\n"
+
+	tokens, err := Tokenize(source, nil)
+	if err != nil {
+		t.Fatalf("Tokenize() error = %v", err)
+	}
+
+	var sb strings.Builder
+	for _, tok := range tokens {
+		for _, trivia := range tok.LeadingTrivia {
+			sb.WriteString(trivia.Text)
+		}
+		sb.WriteString(tok.Value)
+		for _, trivia := range tok.TrailingTrivia {
+			sb.WriteString(trivia.Text)
+		}
+	}
+	if sb.String() != source {
+		t.Errorf("expected tokens and trivia to reproduce the source, got %q", sb.String())
+	}
+
+	describe := func(trivia []Trivia) []string {
+		var kinds []string
+		for _, tr := range trivia {
+			kinds = append(kinds, fmt.Sprintf("%s %q", tr.Kind, tr.Text))
+		}
+		return kinds
+	}
+
+	let, semicolon, second := tokens[0], tokens[4], tokens[5]
+	if want := []string{`Line "// header"`, `Newline "\n"`}; !slices.Equal(describe(let.LeadingTrivia), want) {
+		t.Errorf("expected leading trivia %q, got %q", want, describe(let.LeadingTrivia))
+	}
+	if want := []string{`Whitespace " "`, `Line "// trailing"`, `Newline "\n"`}; !slices.Equal(describe(semicolon.TrailingTrivia), want) {
+		t.Errorf("expected trailing trivia %q, got %q", want, describe(semicolon.TrailingTrivia))
+	}
+	if want := []string{`Newline "\n"`, `Whitespace "  "`, `Block "/* lead */"`, `Whitespace " "`}; !slices.Equal(describe(second.LeadingTrivia), want) {
+		t.Errorf("expected leading trivia %q, got %q", want, describe(second.LeadingTrivia))
+	}
+
+	if second.Loc.Start.Line != 4 || second.Loc.Start.Column != 13 {
+		t.Errorf("expected second a at 4:13, got %+v", second.Loc.Start)
+	}
+	if eof := tokens[len(tokens)-1]; eof.Type != TokenEOF || len(eof.LeadingTrivia) != 0 {
+		t.Errorf("expected EOF token without trivia, got %+v", eof)
+	}
+}
+
+func TestTokenizer_OffsetEncoding(t *testing.T) {
+	source := "'😀' + x"
+
+	tokens, err := Tokenize(source, NewBuilder().WithOffsetEncoding(OffsetEncodingByte).MustBuild())
+	if err != nil {
+		t.Fatalf("Tokenize() error = %v", err)
+	}
+	if got := tokens[2].Range; got[0] != 9 {
+		t.Errorf("expected x at byte 9, got %v", got)
+	}
+
+	tokenizer := NewTokenizer(source, nil)
+	var last Token
+	for tokenizer.Next() {
+		if tok := tokenizer.Token(); tok.Type == TokenIdentifier {
+			last = tok
+		}
+	}
+	if last.Range[0] != 7 {
+		t.Errorf("expected x at UTF-16 offset 7, got %v", last.Range)
+	}
+}